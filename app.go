@@ -4,18 +4,23 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
+	goruntime "runtime"
 	"strings"
+	"sync"
 	"time"
 
 	_ "modernc.org/sqlite"
 
 	"git-analytics/internal/config"
 	"git-analytics/internal/git"
+	"git-analytics/internal/hosting"
 	"git-analytics/internal/indexer"
+	"git-analytics/internal/issues"
 	"git-analytics/internal/query"
 	"git-analytics/internal/store"
 	sqlitestore "git-analytics/internal/store/sqlite"
@@ -23,14 +28,32 @@ import (
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+// profileLimit caps the ranked lists (co-changed files, top files and
+// directories) returned in file and author profiles.
+const profileLimit = 10
+
+// blameWorkers bounds the number of files blamed concurrently.
+var blameWorkers = goruntime.NumCPU()
+
+// searchLimit caps the number of commit message search results.
+const searchLimit = 100
+
 // App struct
 type App struct {
 	ctx       context.Context
 	repo      git.Repository
+	repoPath  string
 	store     store.Store
 	db        *sql.DB
 	configDir string
 	version   string
+
+	// Complexity and line survival are indexed in the background; see
+	// startBackgroundIndexing.
+	indexCancel context.CancelFunc
+	indexDone   chan struct{}
+	indexMu     sync.Mutex
+	indexErrs   map[string]error
 }
 
 // NewApp creates a new App application struct
@@ -110,6 +133,7 @@ func (a *App) startup(ctx context.Context) {
 
 // shutdown is called when the app is closing.
 func (a *App) shutdown(ctx context.Context) {
+	a.stopBackgroundIndexing()
 	if a.repo != nil {
 		a.repo.Close()
 	}
@@ -125,6 +149,7 @@ func (a *App) shutdown(ctx context.Context) {
 // analytics database, and runs the indexer.
 func (a *App) OpenRepository(path string) error {
 	// Close any previously opened resources.
+	a.stopBackgroundIndexing()
 	if a.repo != nil {
 		a.repo.Close()
 		a.repo = nil
@@ -143,8 +168,10 @@ func (a *App) OpenRepository(path string) error {
 		return fmt.Errorf("opening repository: %w", err)
 	}
 
+	// Background indexing writes while queries index on demand, so
+	// every connection waits for a busy database rather than failing.
 	dbPath := filepath.Join(path, ".git-analytics.db")
-	db, err := sql.Open("sqlite", dbPath)
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=busy_timeout(5000)")
 	if err != nil {
 		repo.Close()
 		return fmt.Errorf("opening database: %w", err)
@@ -169,6 +196,7 @@ func (a *App) OpenRepository(path string) error {
 	}
 
 	a.repo = repo
+	a.repoPath = path
 	a.store = s
 	a.db = db
 
@@ -176,6 +204,26 @@ func (a *App) OpenRepository(path string) error {
 	if err := idx.Index(); err != nil {
 		return fmt.Errorf("indexing: %w", err)
 	}
+	if err := idx.IndexPathKinds(); err != nil {
+		return fmt.Errorf("classifying paths: %w", err)
+	}
+
+	extractor, err := a.issueExtractor()
+	if err != nil {
+		return err
+	}
+	if err := s.LinkIssues(extractor); err != nil {
+		return fmt.Errorf("linking issues: %w", err)
+	}
+
+	if a.configDir != "" {
+		if cfg, err := config.Load(a.configDir); err == nil && cfg.Repo(path).ExtractHunks {
+			if err := idx.IndexHunks(); err != nil {
+				return fmt.Errorf("extracting hunks: %w", err)
+			}
+		}
+	}
+	a.startBackgroundIndexing()
 
 	// Persist this repo in the recent list.
 	if a.configDir != "" {
@@ -221,8 +269,8 @@ func (a *App) RemoveRecentRepo(path string) error {
 
 // CommitHeatmap returns per-day commit counts between the given dates.
 // Dates should be in "2006-01-02" format. An empty email returns counts for
-// all authors.
-func (a *App) CommitHeatmap(fromDate, toDate, email string) ([]query.HeatmapDay, error) {
+// all authors. Commits are narrowed by filter.
+func (a *App) CommitHeatmap(fromDate, toDate, email string, filter query.Filter) ([]query.HeatmapDay, error) {
 	if a.db == nil {
 		return nil, fmt.Errorf("no repository open")
 	}
@@ -236,13 +284,15 @@ func (a *App) CommitHeatmap(fromDate, toDate, email string) ([]query.HeatmapDay,
 		return nil, fmt.Errorf("parsing to date: %w", err)
 	}
 
-	return query.CommitHeatmap(a.db, from, to, email)
+	return query.CommitHeatmap(a.db, from, to, email, filter)
 }
 
 // FileHotspots returns per-file churn (lines changed) and commit counts
-// between the given dates. Dates should be in "2006-01-02" format.
-// Files matching any of the excludeGlobs patterns are omitted.
-func (a *App) FileHotspots(fromDate, toDate string, excludeGlobs []string) ([]query.FileHotspot, error) {
+// between the given dates, with each file's size and complexity at HEAD.
+// Files are measured in the background once the repository is open, so
+// files not measured yet have no size or complexity. Dates should be in
+// "2006-01-02" format. Commits and files are narrowed by filter.
+func (a *App) FileHotspots(fromDate, toDate string, filter query.Filter) ([]query.FileHotspot, error) {
 	if a.db == nil {
 		return nil, fmt.Errorf("no repository open")
 	}
@@ -256,13 +306,16 @@ func (a *App) FileHotspots(fromDate, toDate string, excludeGlobs []string) ([]qu
 		return nil, fmt.Errorf("parsing to date: %w", err)
 	}
 
-	return query.FileHotspots(a.db, from, to, excludeGlobs)
+	if err := a.backgroundErr(passComplexity); err != nil {
+		return nil, err
+	}
+	return query.FileHotspots(a.db, from, to, filter)
 }
 
 // Contributors returns per-author commit counts, additions, and deletions
 // between the given dates. Dates should be in "2006-01-02" format.
-// Files matching any of the excludeGlobs patterns are excluded from stats.
-func (a *App) Contributors(fromDate, toDate string, excludeGlobs []string) ([]query.Contributor, error) {
+// Commits and files are narrowed by filter.
+func (a *App) Contributors(fromDate, toDate string, filter query.Filter) ([]query.Contributor, error) {
 	if a.db == nil {
 		return nil, fmt.Errorf("no repository open")
 	}
@@ -276,13 +329,13 @@ func (a *App) Contributors(fromDate, toDate string, excludeGlobs []string) ([]qu
 		return nil, fmt.Errorf("parsing to date: %w", err)
 	}
 
-	return query.Contributors(a.db, from, to, excludeGlobs)
+	return query.Contributors(a.db, from, to, filter)
 }
 
 // FileOwnerships returns per-file ownership analysis showing the dominant
 // contributors between the given dates. Dates should be in "2006-01-02" format.
-// Files matching any of the excludeGlobs patterns are omitted.
-func (a *App) FileOwnerships(fromDate, toDate string, excludeGlobs []string) ([]query.FileOwnership, error) {
+// Commits and files are narrowed by filter.
+func (a *App) FileOwnerships(fromDate, toDate string, filter query.Filter) ([]query.FileOwnership, error) {
 	if a.db == nil {
 		return nil, fmt.Errorf("no repository open")
 	}
@@ -296,14 +349,39 @@ func (a *App) FileOwnerships(fromDate, toDate string, excludeGlobs []string) ([]
 		return nil, fmt.Errorf("parsing to date: %w", err)
 	}
 
-	return query.FileOwnerships(a.db, from, to, excludeGlobs)
+	return query.FileOwnerships(a.db, from, to, filter)
 }
 
 // TemporalHotspots returns per-file churn weighted by recency (exponential
-// decay) between the given dates. Dates should be in "2006-01-02" format.
-// halfLifeDays controls how fast old changes decay. Files matching any of
-// the excludeGlobs patterns are omitted.
-func (a *App) TemporalHotspots(fromDate, toDate string, halfLifeDays float64, excludeGlobs []string) ([]query.TemporalHotspot, error) {
+// decay) between the given dates, with each file's size and complexity at
+// HEAD as in FileHotspots. Dates should be in "2006-01-02" format.
+// halfLifeDays controls how fast old changes decay. Commits and files are
+// narrowed by filter.
+func (a *App) TemporalHotspots(fromDate, toDate string, halfLifeDays float64, filter query.Filter) ([]query.TemporalHotspot, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	if err := a.backgroundErr(passComplexity); err != nil {
+		return nil, err
+	}
+	return query.TemporalHotspots(a.db, from, to, halfLifeDays, filter)
+}
+
+// DefectHotspots ranks files, or directories if byDirectory is true, by the
+// number and recency of bug-fix commits touching them, normalized by their
+// total commits. Dates should be in "2006-01-02" format. halfLifeDays
+// controls how fast old fixes decay. Commits and files are narrowed by filter.
+func (a *App) DefectHotspots(fromDate, toDate string, halfLifeDays float64, byDirectory bool, filter query.Filter) ([]query.DefectHotspot, error) {
 	if a.db == nil {
 		return nil, fmt.Errorf("no repository open")
 	}
@@ -317,14 +395,14 @@ func (a *App) TemporalHotspots(fromDate, toDate string, halfLifeDays float64, ex
 		return nil, fmt.Errorf("parsing to date: %w", err)
 	}
 
-	return query.TemporalHotspots(a.db, from, to, halfLifeDays, excludeGlobs)
+	return query.DefectHotspots(a.db, from, to, halfLifeDays, byDirectory, filter)
 }
 
 // CoChanges returns file pairs that frequently change together in commits
 // between the given dates. Dates should be in "2006-01-02" format.
 // Only pairs with at least minCount shared commits are returned, up to limit.
-// Files matching any of the excludeGlobs patterns are omitted.
-func (a *App) CoChanges(fromDate, toDate string, minCount int, limit int, excludeGlobs []string) ([]query.CoChangePair, error) {
+// Commits and files are narrowed by filter.
+func (a *App) CoChanges(fromDate, toDate string, minCount int, limit int, filter query.Filter) ([]query.CoChangePair, error) {
 	if a.db == nil {
 		return nil, fmt.Errorf("no repository open")
 	}
@@ -338,7 +416,7 @@ func (a *App) CoChanges(fromDate, toDate string, minCount int, limit int, exclud
 		return nil, fmt.Errorf("parsing to date: %w", err)
 	}
 
-	return query.CoChanges(a.db, from, to, minCount, limit, excludeGlobs)
+	return query.CoChanges(a.db, from, to, minCount, limit, filter)
 }
 
 // RepoInfo holds metadata about the currently opened repository.
@@ -369,7 +447,8 @@ func (a *App) RepoInfo() (*RepoInfo, error) {
 		HeadHash: hash[:min(7, len(hash))],
 	}
 
-	var authorName, authorEmail, message, committedAt string
+	var authorName, authorEmail, message string
+	var committedAt int64
 	err = a.db.QueryRow(
 		`SELECT author_name, author_email, message, committed_at
 		 FROM commits ORDER BY committed_at DESC LIMIT 1`,
@@ -384,20 +463,7 @@ func (a *App) RepoInfo() (*RepoInfo, error) {
 	info.LastAuthor = authorName
 	info.LastEmail = authorEmail
 	info.LastMessage = strings.TrimSpace(message)
-
-	t, err := time.Parse(time.RFC3339, committedAt)
-	if err != nil {
-		trimmed := committedAt
-		if idx := strings.LastIndex(trimmed, " "); idx > 0 {
-			trimmed = trimmed[:idx]
-		}
-		t, err = time.Parse("2006-01-02 15:04:05 -0700", trimmed)
-		if err != nil {
-			info.LastCommitAge = committedAt
-			return info, nil
-		}
-	}
-	info.LastCommitAge = relativeTime(t)
+	info.LastCommitAge = relativeTime(time.Unix(committedAt, 0))
 
 	return info, nil
 }
@@ -430,8 +496,8 @@ func relativeTime(t time.Time) string {
 
 // DashboardStats returns aggregate commit and file-change stats between the
 // given dates. Dates should be in "2006-01-02" format.
-// Files matching any of the excludeGlobs patterns are omitted from file-level metrics.
-func (a *App) DashboardStats(fromDate, toDate string, excludeGlobs []string) (*query.DashboardStats, error) {
+// Commits and files are narrowed by filter.
+func (a *App) DashboardStats(fromDate, toDate string, filter query.Filter) (*query.DashboardStats, error) {
 	if a.db == nil {
 		return nil, fmt.Errorf("no repository open")
 	}
@@ -445,12 +511,368 @@ func (a *App) DashboardStats(fromDate, toDate string, excludeGlobs []string) (*q
 		return nil, fmt.Errorf("parsing to date: %w", err)
 	}
 
-	return query.GetDashboardStats(a.db, from, to, excludeGlobs)
+	return query.GetDashboardStats(a.db, from, to, filter)
 }
 
 // CommitsByHour returns per-hour commit counts between the given dates.
-// Dates should be in "2006-01-02" format.
-func (a *App) CommitsByHour(fromDate, toDate string) ([]query.HourBucket, error) {
+// Dates should be in "2006-01-02" format. Commits are narrowed by filter.
+func (a *App) CommitsByHour(fromDate, toDate string, filter query.Filter) ([]query.HourBucket, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	return query.CommitsByHour(a.db, from, to, filter)
+}
+
+// CommitPunchcard returns a weekday × hour grid of commit counts between the
+// given dates. Dates should be in "2006-01-02" format. An empty email returns
+// counts for all authors. An empty timezone buckets each commit in its
+// author's local time; otherwise timezone is an IANA name (e.g.
+// "Europe/Berlin") or "Local" and all commits are converted to it. Commits
+// are narrowed by filter.
+func (a *App) CommitPunchcard(fromDate, toDate, email, timezone string, filter query.Filter) (*query.Punchcard, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	var loc *time.Location
+	if timezone != "" {
+		loc, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("loading timezone: %w", err)
+		}
+	}
+
+	return query.CommitPunchcard(a.db, from, to, email, loc, filter)
+}
+
+// ActivitySeries returns commits, additions, deletions, active authors and
+// files touched per day, week, month or quarter between the given dates.
+// Dates should be in "2006-01-02" format. A non-empty pathPrefix restricts
+// the series to files under it and a non-empty email to that author.
+// Commits and files are narrowed by filter.
+func (a *App) ActivitySeries(fromDate, toDate, granularity, pathPrefix, email string, filter query.Filter) ([]query.SeriesPoint, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	return query.ActivitySeries(a.db, from, to, query.Granularity(granularity), pathPrefix, email, filter)
+}
+
+// FileProfile returns the history of a single file between the given dates:
+// its commits, churn series at the given granularity, author breakdown and
+// most frequently co-changed files. Renames are followed. Dates should be in
+// "2006-01-02" format. Commits and co-changed files are narrowed by filter.
+func (a *App) FileProfile(path, fromDate, toDate, granularity string, filter query.Filter) (*query.FileProfile, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	return query.GetFileProfile(a.db, path, from, to, query.Granularity(granularity), profileLimit, filter)
+}
+
+// AuthorProfile returns the activity of the author with the given email
+// between the given dates: totals, activity series at the given granularity,
+// punchcard, most changed files and directories, owned files, languages and
+// co-changed files. Dates should be in "2006-01-02" format. Commits and
+// files are narrowed by filter.
+func (a *App) AuthorProfile(email, fromDate, toDate, granularity string, filter query.Filter) (*query.AuthorProfile, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	if err := indexer.New(a.repo, a.store).IndexLanguages(); err != nil {
+		return nil, fmt.Errorf("detecting languages: %w", err)
+	}
+	return query.GetAuthorProfile(a.db, email, from, to, query.Granularity(granularity), profileLimit, filter)
+}
+
+// SearchCommits searches commit subjects and descriptions between the given
+// dates and returns the most relevant matches with highlighted excerpts.
+// Dates should be in "2006-01-02" format. A non-empty email restricts results
+// to that author and a non-empty pathPrefix to commits touching files under it.
+// Commits are narrowed by filter.
+func (a *App) SearchCommits(text, fromDate, toDate, email, pathPrefix string, filter query.Filter) ([]query.CommitMatch, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	return query.SearchCommits(a.db, text, from, to, email, pathPrefix, searchLimit, filter)
+}
+
+// ChangeTypeSeries returns commit counts per change type (feat, fix, ...) per
+// day, week, month or quarter between the given dates. Dates should be in
+// "2006-01-02" format. A non-empty pathPrefix restricts the series to commits
+// touching files under it. Commits and files are narrowed by filter.
+func (a *App) ChangeTypeSeries(fromDate, toDate, granularity, pathPrefix string, filter query.Filter) ([]query.ChangeTypePoint, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	return query.ChangeTypeSeries(a.db, from, to, query.Granularity(granularity), pathPrefix, filter)
+}
+
+// issueExtractor returns an extractor for the issue patterns configured for
+// the open repository.
+func (a *App) issueExtractor() (*issues.Extractor, error) {
+	var patterns []string
+	if a.configDir != "" {
+		if cfg, err := config.Load(a.configDir); err == nil {
+			patterns = cfg.Repo(a.repoPath).IssuePatterns
+		}
+	}
+	e, err := issues.NewExtractor(patterns)
+	if err != nil {
+		return nil, fmt.Errorf("compiling issue patterns: %w", err)
+	}
+	return e, nil
+}
+
+// IssuePatterns returns the regular expressions used to find issue keys in
+// the open repository's commit messages.
+func (a *App) IssuePatterns() ([]string, error) {
+	if a.store == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+	if a.configDir != "" {
+		cfg, err := config.Load(a.configDir)
+		if err != nil {
+			return nil, err
+		}
+		if patterns := cfg.Repo(a.repoPath).IssuePatterns; len(patterns) > 0 {
+			return patterns, nil
+		}
+	}
+	return issues.DefaultPatterns, nil
+}
+
+// SetIssuePatterns saves the regular expressions used to find issue keys in
+// the open repository's commit messages and relinks all commits. An empty
+// list restores the defaults.
+func (a *App) SetIssuePatterns(patterns []string) error {
+	if a.store == nil {
+		return fmt.Errorf("no repository open")
+	}
+	if a.configDir == "" {
+		return fmt.Errorf("config directory unavailable")
+	}
+	e, err := issues.NewExtractor(patterns)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(a.configDir)
+	if err != nil {
+		return err
+	}
+	settings := cfg.Repo(a.repoPath)
+	settings.IssuePatterns = patterns
+	cfg.SetRepo(a.repoPath, settings)
+	if err := cfg.Save(a.configDir); err != nil {
+		return err
+	}
+
+	return a.store.LinkIssues(e)
+}
+
+// ImportIssues reads the contents of a Jira CSV or JSON export, or a GitHub
+// issue list in JSON, and stores the issue types, statuses and dates.
+// Returns the number of issues imported.
+func (a *App) ImportIssues(data string) (int, error) {
+	if a.store == nil {
+		return 0, fmt.Errorf("no repository open")
+	}
+	list, err := issues.ParseExport([]byte(data))
+	if err != nil {
+		return 0, err
+	}
+	if err := a.store.ImportIssues(list); err != nil {
+		return 0, fmt.Errorf("importing issues: %w", err)
+	}
+	return len(list), nil
+}
+
+// Issues returns the issues referenced by commits between the given dates
+// with their commit and file totals. Dates should be in "2006-01-02" format.
+// Commits and files are narrowed by filter.
+func (a *App) Issues(fromDate, toDate string, filter query.Filter) ([]query.IssueSummary, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	return query.Issues(a.db, from, to, filter)
+}
+
+// IssueCommits returns the commits referencing the issue key, newest first.
+// Commits and files are narrowed by filter.
+func (a *App) IssueCommits(key string, filter query.Filter) ([]query.IssueCommit, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+	return query.IssueCommits(a.db, key, filter)
+}
+
+// IssueFiles returns the files changed by commits referencing the issue key.
+// Commits and files are narrowed by filter.
+func (a *App) IssueFiles(key string, filter query.Filter) ([]query.FileHotspot, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+	return query.IssueFiles(a.db, key, filter)
+}
+
+// FileIssues returns the issues referenced by commits that changed path
+// between the given dates. Dates should be in "2006-01-02" format. Commits
+// are narrowed by filter.
+func (a *App) FileIssues(path, fromDate, toDate string, filter query.Filter) ([]query.IssueSummary, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	return query.FileIssues(a.db, path, from, to, filter)
+}
+
+// PullRequestSource returns the hosting provider settings last used to
+// import pull requests for the open repository.
+func (a *App) PullRequestSource() (config.PullRequestSource, error) {
+	if a.store == nil {
+		return config.PullRequestSource{}, fmt.Errorf("no repository open")
+	}
+	if a.configDir == "" {
+		return config.PullRequestSource{}, nil
+	}
+	cfg, err := config.Load(a.configDir)
+	if err != nil {
+		return config.PullRequestSource{}, err
+	}
+	return cfg.Repo(a.repoPath).PullRequests, nil
+}
+
+// ImportPullRequests fetches the pull requests of repository from a GitHub
+// or GitLab API at baseURL (empty for the public service) and stores their
+// reviewers, reviews, timestamps and commits. The source is remembered for
+// the open repository; the token is not. When importing again from the
+// remembered source, only pull requests updated since the last import are
+// fetched. Returns the number of pull requests imported.
+func (a *App) ImportPullRequests(provider, baseURL, repository, token string) (int, error) {
+	if a.store == nil {
+		return 0, fmt.Errorf("no repository open")
+	}
+	p, err := hosting.New(provider, baseURL, repository, token)
+	if err != nil {
+		return 0, err
+	}
+	source := config.PullRequestSource{Provider: provider, BaseURL: baseURL, Repository: repository}
+	var since time.Time
+	if previous, err := a.PullRequestSource(); err == nil && previous == source {
+		if since, err = a.store.GetLastPullRequestUpdate(); err != nil {
+			return 0, err
+		}
+	}
+	prs, err := p.PullRequests(a.ctx, since)
+	if err != nil {
+		return 0, fmt.Errorf("fetching pull requests: %w", err)
+	}
+	if err := a.store.ImportPullRequests(prs); err != nil {
+		return 0, fmt.Errorf("importing pull requests: %w", err)
+	}
+
+	if a.configDir != "" {
+		cfg, _ := config.Load(a.configDir)
+		settings := cfg.Repo(a.repoPath)
+		settings.PullRequests = source
+		cfg.SetRepo(a.repoPath, settings)
+		_ = cfg.Save(a.configDir)
+	}
+	return len(prs), nil
+}
+
+// ReviewLatency returns, per day, week, month or quarter, the median time
+// pull requests opened between the given dates waited for a first review and
+// took to merge. Dates should be in "2006-01-02" format. Pull requests are
+// narrowed by filter through their commits.
+func (a *App) ReviewLatency(fromDate, toDate, granularity string, filter query.Filter) ([]query.ReviewLatencyPoint, error) {
 	if a.db == nil {
 		return nil, fmt.Errorf("no repository open")
 	}
@@ -464,7 +886,511 @@ func (a *App) CommitsByHour(fromDate, toDate string) ([]query.HourBucket, error)
 		return nil, fmt.Errorf("parsing to date: %w", err)
 	}
 
-	return query.CommitsByHour(a.db, from, to)
+	return query.ReviewLatency(a.db, from, to, query.Granularity(granularity), filter)
+}
+
+// ReviewerLoads returns review activity per reviewer on pull requests opened
+// between the given dates. Dates should be in "2006-01-02" format. Pull
+// requests are narrowed by filter through their commits.
+func (a *App) ReviewerLoads(fromDate, toDate string, filter query.Filter) ([]query.ReviewerLoad, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	return query.ReviewerLoads(a.db, from, to, filter)
+}
+
+// PullRequestFiles returns the files changed by the commits of the pull
+// request number, narrowed by filter.
+func (a *App) PullRequestFiles(number int, filter query.Filter) ([]query.FileHotspot, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+	return query.PullRequestFiles(a.db, number, filter)
+}
+
+// RevertSeries returns the number of commits and reverts per day, week, month
+// or quarter between the given dates. Dates should be in "2006-01-02" format.
+// Commits are narrowed by filter.
+func (a *App) RevertSeries(fromDate, toDate, granularity string, filter query.Filter) ([]query.RevertPoint, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	return query.RevertSeries(a.db, from, to, query.Granularity(granularity), filter)
+}
+
+// RevertedFiles returns the files whose changes between the given dates were
+// most often reverted. Dates should be in "2006-01-02" format. Commits and
+// files are narrowed by filter.
+func (a *App) RevertedFiles(fromDate, toDate string, filter query.Filter) ([]query.RevertedFile, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	return query.RevertedFiles(a.db, from, to, filter)
+}
+
+// Reverts returns the reverting commits between the given dates with the
+// commits they revert. Dates should be in "2006-01-02" format. Commits are
+// narrowed by filter.
+func (a *App) Reverts(fromDate, toDate string, filter query.Filter) ([]query.Revert, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	return query.Reverts(a.db, from, to, filter)
+}
+
+// CommitSizes returns the distribution of commit sizes between the given
+// dates, overall, per author and per day, week, month or quarter, with up to
+// outlierLimit unusually large commits. Dates should be in "2006-01-02"
+// format. Commits and files are narrowed by filter.
+func (a *App) CommitSizes(fromDate, toDate, granularity string, outlierLimit int, filter query.Filter) (*query.CommitSizeReport, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	return query.CommitSizes(a.db, from, to, query.Granularity(granularity), outlierLimit, filter)
+}
+
+// CodeAge returns the creation date and age since last modification of each
+// file at HEAD, with age histograms overall and per directory. Commits and
+// files are narrowed by filter.
+func (a *App) CodeAge(filter query.Filter) (*query.CodeAgeReport, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+	return query.CodeAge(a.db, time.Now(), filter)
+}
+
+// BinaryChurn returns the binary files changed between the given dates with
+// their change counts and size changes. Dates should be in "2006-01-02"
+// format. Commits and files are narrowed by filter.
+func (a *App) BinaryChurn(fromDate, toDate string, filter query.Filter) ([]query.BinaryFile, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	return query.BinaryChurn(a.db, from, to, filter)
+}
+
+// BlameOwnerships returns who owns the surviving lines of each file (or, if
+// byDirectory is true, each directory) at HEAD, next to the churn-based top
+// author between the given dates. Files not blamed before are blamed first,
+// which can take a while on the first call. Dates should be in "2006-01-02"
+// format. Commits and files are narrowed by filter.
+func (a *App) BlameOwnerships(fromDate, toDate string, byDirectory bool, filter query.Filter) ([]query.BlameOwnership, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	if err := indexer.New(a.repo, a.store).IndexBlame(blameWorkers); err != nil {
+		return nil, fmt.Errorf("blaming files: %w", err)
+	}
+	return query.BlameOwnerships(a.db, from, to, byDirectory, filter)
+}
+
+// SurvivalCurves returns line survival curves grouped by "author", "cohort"
+// or "directory". Months are sampled in the background once the repository
+// is open, so the latest months are missing until sampling finishes.
+// Commits and files are narrowed by filter.
+func (a *App) SurvivalCurves(groupBy string, filter query.Filter) ([]query.SurvivalCurve, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	if err := a.backgroundErr(passSurvival); err != nil {
+		return nil, err
+	}
+	return query.SurvivalCurves(a.db, query.SurvivalGrouping(groupBy), filter)
+}
+
+// Background indexing passes, in the order they run.
+const (
+	passComplexity = "measuring complexity"
+	passSurvival   = "sampling line survival"
+)
+
+// startBackgroundIndexing measures the complexity of files at HEAD, then
+// samples line survival for the months ended since the last run, in a
+// background goroutine, as both can take a long time on the first run.
+func (a *App) startBackgroundIndexing() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	a.indexCancel, a.indexDone = cancel, done
+
+	idx := indexer.New(a.repo, a.store)
+	passes := []struct {
+		name string
+		run  func() error
+	}{
+		{passComplexity, func() error { return idx.IndexComplexity(ctx) }},
+		{passSurvival, func() error { return idx.IndexSurvival(ctx, blameWorkers, time.Now()) }},
+	}
+	go func() {
+		defer close(done)
+		for _, p := range passes {
+			err := p.run()
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			if err != nil {
+				a.indexMu.Lock()
+				if a.indexErrs == nil {
+					a.indexErrs = make(map[string]error)
+				}
+				a.indexErrs[p.name] = err
+				a.indexMu.Unlock()
+			}
+		}
+	}()
+}
+
+// stopBackgroundIndexing cancels background indexing and waits for it to
+// stop, so that the repository and store it uses can be closed.
+func (a *App) stopBackgroundIndexing() {
+	if a.indexCancel == nil {
+		return
+	}
+	a.indexCancel()
+	<-a.indexDone
+	a.indexCancel, a.indexDone = nil, nil
+	a.indexMu.Lock()
+	a.indexErrs = nil
+	a.indexMu.Unlock()
+}
+
+// backgroundErr returns the error the background indexing pass failed
+// with, if any.
+func (a *App) backgroundErr(pass string) error {
+	a.indexMu.Lock()
+	defer a.indexMu.Unlock()
+	if err := a.indexErrs[pass]; err != nil {
+		return fmt.Errorf("%s: %w", pass, err)
+	}
+	return nil
+}
+
+// Rework returns how much churn between the given dates rewrote lines written
+// within the previous days days, per author, per file and per day, week,
+// month or quarter. Hunks of commits not seen before are extracted first,
+// which can take a while on the first call; it fails if hunk extraction is
+// disabled. Dates should be in "2006-01-02" format. Commits and files are
+// narrowed by filter.
+func (a *App) Rework(fromDate, toDate, granularity string, days int, filter query.Filter) (*query.ReworkReport, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	if err := a.indexHunks(); err != nil {
+		return nil, err
+	}
+	return query.Rework(a.db, from, to, query.Granularity(granularity), days, filter)
+}
+
+// HunkExtraction reports whether the open repository extracts diff hunks
+// while indexing.
+func (a *App) HunkExtraction() (bool, error) {
+	if a.store == nil {
+		return false, fmt.Errorf("no repository open")
+	}
+	if a.configDir == "" {
+		return false, nil
+	}
+	cfg, err := config.Load(a.configDir)
+	if err != nil {
+		return false, err
+	}
+	return cfg.Repo(a.repoPath).ExtractHunks, nil
+}
+
+// errHunkExtractionDisabled is returned by the analyses built on diff hunks
+// when the open repository has not opted in to extracting them.
+var errHunkExtractionDisabled = errors.New("hunk extraction is disabled for this repository")
+
+// indexHunks extracts the hunks of commits not processed yet, or returns
+// errHunkExtractionDisabled if the open repository does not extract hunks.
+func (a *App) indexHunks() error {
+	enabled, err := a.HunkExtraction()
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return errHunkExtractionDisabled
+	}
+	if err := indexer.New(a.repo, a.store).IndexHunks(); err != nil {
+		return fmt.Errorf("extracting hunks: %w", err)
+	}
+	return nil
+}
+
+// SetHunkExtraction saves whether the open repository extracts diff hunks
+// while indexing. Enabling it extracts the hunks of commits not processed
+// yet right away.
+func (a *App) SetHunkExtraction(enabled bool) error {
+	if a.store == nil {
+		return fmt.Errorf("no repository open")
+	}
+	if a.configDir == "" {
+		return fmt.Errorf("config directory unavailable")
+	}
+
+	cfg, err := config.Load(a.configDir)
+	if err != nil {
+		return err
+	}
+	settings := cfg.Repo(a.repoPath)
+	settings.ExtractHunks = enabled
+	cfg.SetRepo(a.repoPath, settings)
+	if err := cfg.Save(a.configDir); err != nil {
+		return err
+	}
+
+	if !enabled {
+		return nil
+	}
+	if err := indexer.New(a.repo, a.store).IndexHunks(); err != nil {
+		return fmt.Errorf("extracting hunks: %w", err)
+	}
+	return nil
+}
+
+// LineHistory returns the commits that changed lines start to end of path
+// at HEAD, newest first. Hunks of commits not seen before are extracted
+// first; it fails if hunk extraction is disabled. Commits are narrowed by
+// filter.
+func (a *App) LineHistory(path string, start, end int, filter query.Filter) ([]query.LineRangeCommit, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	if err := a.indexHunks(); err != nil {
+		return nil, err
+	}
+	return query.LineHistory(a.db, path, start, end, filter)
+}
+
+// FunctionHotspots returns per-function churn of the Go code at HEAD for
+// commits between fromDate (inclusive) and toDate (exclusive), extracting
+// hunks and parsing Go files as needed. It fails if hunk extraction is
+// disabled.
+func (a *App) FunctionHotspots(fromDate, toDate string, filter query.Filter) ([]query.FunctionHotspot, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	if err := a.indexHunks(); err != nil {
+		return nil, err
+	}
+	if err := indexer.New(a.repo, a.store).IndexFunctions(); err != nil {
+		return nil, fmt.Errorf("parsing functions: %w", err)
+	}
+	return query.FunctionHotspots(a.db, from, to, filter)
+}
+
+// FunctionParseErrors returns the Go files at HEAD whose functions
+// FunctionHotspots cannot report because they do not parse, parsing Go files
+// as needed. Files are narrowed by filter.
+func (a *App) FunctionParseErrors(filter query.Filter) ([]query.FunctionParseError, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	if err := indexer.New(a.repo, a.store).IndexFunctions(); err != nil {
+		return nil, fmt.Errorf("parsing functions: %w", err)
+	}
+	return query.FunctionParseErrors(a.db, filter)
+}
+
+// ComplexityTrend returns the size and complexity of path after each commit
+// that changed it, or after the last such commit of each bucket if
+// granularity is not empty, oldest first. Revisions not measured before
+// are measured first. Commits are narrowed by filter.
+func (a *App) ComplexityTrend(path, granularity string, filter query.Filter) ([]query.ComplexityPoint, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	g := query.Granularity(granularity)
+	revs, err := query.FileRevisions(a.db, path, g, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := indexer.New(a.repo, a.store).IndexRevisions(revs); err != nil {
+		return nil, fmt.Errorf("measuring complexity: %w", err)
+	}
+	return query.ComplexityTrend(a.db, path, g, filter)
+}
+
+// LanguageBreakdown returns per-language files and lines of code at HEAD and
+// churn between the given dates. Languages are detected first where needed;
+// line counts are measured in the background as in FileHotspots. Dates
+// should be in "2006-01-02" format. Commits and files are narrowed by
+// filter.
+func (a *App) LanguageBreakdown(fromDate, toDate string, filter query.Filter) ([]query.LanguageStat, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	idx := indexer.New(a.repo, a.store)
+	if err := idx.IndexLanguages(); err != nil {
+		return nil, fmt.Errorf("detecting languages: %w", err)
+	}
+	if err := a.backgroundErr(passComplexity); err != nil {
+		return nil, err
+	}
+	return query.LanguageBreakdown(a.db, from, to, filter)
+}
+
+// LanguageActivity returns per-language churn per bucket of the given
+// granularity between the given dates. Dates should be in "2006-01-02"
+// format. Commits and files are narrowed by filter.
+func (a *App) LanguageActivity(fromDate, toDate, granularity string, filter query.Filter) ([]query.LanguageSeries, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	if err := indexer.New(a.repo, a.store).IndexLanguages(); err != nil {
+		return nil, fmt.Errorf("detecting languages: %w", err)
+	}
+	return query.LanguageActivity(a.db, from, to, query.Granularity(granularity), filter)
+}
+
+// AuthorLanguages returns every author's language mix between the given
+// dates. Dates should be in "2006-01-02" format. Commits and files are
+// narrowed by filter.
+func (a *App) AuthorLanguages(fromDate, toDate string, filter query.Filter) ([]query.AuthorLanguageMix, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from date: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to date: %w", err)
+	}
+
+	if err := indexer.New(a.repo, a.store).IndexLanguages(); err != nil {
+		return nil, fmt.Errorf("detecting languages: %w", err)
+	}
+	return query.AuthorLanguages(a.db, from, to, filter)
+}
+
+// ClassifiedPaths returns the paths detected as generated, vendored or
+// documentation, which query filters omit (generated and vendored) or can
+// omit (documentation) unless told otherwise.
+func (a *App) ClassifiedPaths() ([]query.ClassifiedPath, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("no repository open")
+	}
+
+	if err := indexer.New(a.repo, a.store).IndexPathKinds(); err != nil {
+		return nil, fmt.Errorf("classifying paths: %w", err)
+	}
+	return query.ClassifiedPaths(a.db)
 }
 
 // addToGitExclude adds a pattern to .git/info/exclude if it's not already present.
@@ -1,12 +1,50 @@
-import { type Ref, ref, watch } from 'vue'
+import { type Ref, computed, ref, watch } from 'vue'
+import { query } from '../../wailsjs/go/models'
 
-const STORAGE_PREFIX = 'exclude-patterns:'
+// Patterns use .gitignore syntax since v2. Those saved under the old prefix
+// were SQLite GLOB patterns and are converted on first load.
+const STORAGE_PREFIX = 'exclude-patterns-v2:'
+const GLOB_STORAGE_PREFIX = 'exclude-patterns:'
+const KINDS_STORAGE_PREFIX = 'path-kinds:'
+
+// Which classified paths queries keep. Generated and vendored files are
+// omitted and documentation kept unless told otherwise, as in query.Filter.
+export type PathKinds = {
+  include_generated: boolean
+  include_vendored: boolean
+  exclude_documentation: boolean
+}
+
+const DEFAULT_KINDS: PathKinds = {
+  include_generated: false,
+  include_vendored: false,
+  exclude_documentation: false,
+}
+
+// fromGlob converts a SQLite GLOB pattern, which matched the whole path with
+// "*" crossing directories, to the .gitignore pattern closest to it.
+function fromGlob(glob: string): string {
+  // A leading "*/" could span several directories.
+  if (glob.startsWith('*/')) return '**/' + glob.slice(2)
+  // Slash-free patterns match at any depth unless anchored, which "*.ext"
+  // already did.
+  if (!glob.includes('/') && !glob.startsWith('*')) return '/' + glob
+  // "!", "#" and "+" now mean something at the start of a pattern.
+  if (/^[!#+]/.test(glob)) return '\\' + glob
+  return glob
+}
 
 function load(repoPath: string): string[] {
   if (!repoPath) return []
   try {
     const raw = localStorage.getItem(STORAGE_PREFIX + repoPath)
-    return raw ? JSON.parse(raw) : []
+    if (raw) return JSON.parse(raw)
+    const globs = localStorage.getItem(GLOB_STORAGE_PREFIX + repoPath)
+    if (!globs) return []
+    const patterns = (JSON.parse(globs) as string[]).map(fromGlob)
+    save(repoPath, patterns)
+    localStorage.removeItem(GLOB_STORAGE_PREFIX + repoPath)
+    return patterns
   } catch {
     return []
   }
@@ -17,16 +55,34 @@ function save(repoPath: string, patterns: string[]) {
   localStorage.setItem(STORAGE_PREFIX + repoPath, JSON.stringify(patterns))
 }
 
+function loadKinds(repoPath: string): PathKinds {
+  if (!repoPath) return { ...DEFAULT_KINDS }
+  try {
+    const raw = localStorage.getItem(KINDS_STORAGE_PREFIX + repoPath)
+    return raw ? { ...DEFAULT_KINDS, ...JSON.parse(raw) } : { ...DEFAULT_KINDS }
+  } catch {
+    return { ...DEFAULT_KINDS }
+  }
+}
+
+function saveKinds(repoPath: string, kinds: PathKinds) {
+  if (!repoPath) return
+  localStorage.setItem(KINDS_STORAGE_PREFIX + repoPath, JSON.stringify(kinds))
+}
+
 export function useExcludePatterns(repoPath: Ref<string>) {
   const patterns = ref<string[]>(load(repoPath.value))
+  const kinds = ref<PathKinds>(loadKinds(repoPath.value))
 
   watch(repoPath, (path) => {
     patterns.value = load(path)
+    kinds.value = loadKinds(path)
   })
 
   function addPattern(p: string) {
-    const trimmed = p.trim()
-    if (!trimmed || patterns.value.includes(trimmed)) return
+    // As in .gitignore, only unescaped trailing whitespace is insignificant.
+    const trimmed = p.replace(/(^|[^\\])((?:\\\\)*)[ \t]+$/, '$1$2')
+    if (!trimmed.trim() || patterns.value.includes(trimmed)) return
     patterns.value = [...patterns.value, trimmed]
     save(repoPath.value, patterns.value)
   }
@@ -36,5 +92,22 @@ export function useExcludePatterns(repoPath: Ref<string>) {
     save(repoPath.value, patterns.value)
   }
 
-  return { patterns, addPattern, removePattern }
+  function setKinds(k: PathKinds) {
+    kinds.value = k
+    saveKinds(repoPath.value, k)
+  }
+
+  // Query filter carrying the patterns and path kinds, for App query
+  // methods. Patterns use .gitignore syntax; those prefixed with "+" are
+  // include patterns, which keep only the files they match.
+  const filter = computed(() =>
+    query.Filter.createFrom({
+      include_globs: patterns.value.filter((p) => p.startsWith('+')).map((p) => p.slice(1)),
+      exclude_globs: patterns.value.filter((p) => !p.startsWith('+')),
+      change_types: [],
+      ...kinds.value,
+    }),
+  )
+
+  return { patterns, kinds, filter, addPattern, removePattern, setKinds }
 }
@@ -1,37 +1,113 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
-import {main} from '../models';
 import {query} from '../models';
+import {main} from '../models';
 import {config} from '../models';
 
+export function ActivitySeries(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:query.Filter):Promise<Array<query.SeriesPoint>>;
+
+export function AuthorLanguages(arg1:string,arg2:string,arg3:query.Filter):Promise<Array<query.AuthorLanguageMix>>;
+
+export function AuthorProfile(arg1:string,arg2:string,arg3:string,arg4:string,arg5:query.Filter):Promise<query.AuthorProfile>;
+
+export function BinaryChurn(arg1:string,arg2:string,arg3:query.Filter):Promise<Array<query.BinaryFile>>;
+
+export function BlameOwnerships(arg1:string,arg2:string,arg3:boolean,arg4:query.Filter):Promise<Array<query.BlameOwnership>>;
+
+export function ChangeTypeSeries(arg1:string,arg2:string,arg3:string,arg4:string,arg5:query.Filter):Promise<Array<query.ChangeTypePoint>>;
+
 export function CheckForUpdate():Promise<main.UpdateInfo>;
 
-export function CoChanges(arg1:string,arg2:string,arg3:number,arg4:number,arg5:Array<string>):Promise<Array<query.CoChangePair>>;
+export function ClassifiedPaths():Promise<Array<query.ClassifiedPath>>;
+
+export function CoChanges(arg1:string,arg2:string,arg3:number,arg4:number,arg5:query.Filter):Promise<Array<query.CoChangePair>>;
+
+export function CodeAge(arg1:query.Filter):Promise<query.CodeAgeReport>;
+
+export function CommitHeatmap(arg1:string,arg2:string,arg3:string,arg4:query.Filter):Promise<Array<query.HeatmapDay>>;
+
+export function CommitPunchcard(arg1:string,arg2:string,arg3:string,arg4:string,arg5:query.Filter):Promise<query.Punchcard>;
+
+export function CommitSizes(arg1:string,arg2:string,arg3:string,arg4:number,arg5:query.Filter):Promise<query.CommitSizeReport>;
+
+export function CommitsByHour(arg1:string,arg2:string,arg3:query.Filter):Promise<Array<query.HourBucket>>;
+
+export function ComplexityTrend(arg1:string,arg2:string,arg3:query.Filter):Promise<Array<query.ComplexityPoint>>;
+
+export function Contributors(arg1:string,arg2:string,arg3:query.Filter):Promise<Array<query.Contributor>>;
+
+export function DashboardStats(arg1:string,arg2:string,arg3:query.Filter):Promise<query.DashboardStats>;
+
+export function DefectHotspots(arg1:string,arg2:string,arg3:number,arg4:boolean,arg5:query.Filter):Promise<Array<query.DefectHotspot>>;
+
+export function FileHotspots(arg1:string,arg2:string,arg3:query.Filter):Promise<Array<query.FileHotspot>>;
+
+export function FileIssues(arg1:string,arg2:string,arg3:string,arg4:query.Filter):Promise<Array<query.IssueSummary>>;
 
-export function CommitHeatmap(arg1:string,arg2:string,arg3:string):Promise<Array<query.HeatmapDay>>;
+export function FileOwnerships(arg1:string,arg2:string,arg3:query.Filter):Promise<Array<query.FileOwnership>>;
 
-export function CommitsByHour(arg1:string,arg2:string):Promise<Array<query.HourBucket>>;
+export function FileProfile(arg1:string,arg2:string,arg3:string,arg4:string,arg5:query.Filter):Promise<query.FileProfile>;
 
-export function Contributors(arg1:string,arg2:string,arg3:Array<string>):Promise<Array<query.Contributor>>;
+export function FunctionHotspots(arg1:string,arg2:string,arg3:query.Filter):Promise<Array<query.FunctionHotspot>>;
 
-export function DashboardStats(arg1:string,arg2:string,arg3:Array<string>):Promise<query.DashboardStats>;
+export function FunctionParseErrors(arg1:query.Filter):Promise<Array<query.FunctionParseError>>;
 
-export function FileHotspots(arg1:string,arg2:string,arg3:Array<string>):Promise<Array<query.FileHotspot>>;
+export function HunkExtraction():Promise<boolean>;
 
-export function FileOwnerships(arg1:string,arg2:string,arg3:Array<string>):Promise<Array<query.FileOwnership>>;
+export function ImportIssues(arg1:string):Promise<number>;
+
+export function ImportPullRequests(arg1:string,arg2:string,arg3:string,arg4:string):Promise<number>;
+
+export function IssueCommits(arg1:string,arg2:query.Filter):Promise<Array<query.IssueCommit>>;
+
+export function IssueFiles(arg1:string,arg2:query.Filter):Promise<Array<query.FileHotspot>>;
+
+export function IssuePatterns():Promise<Array<string>>;
+
+export function Issues(arg1:string,arg2:string,arg3:query.Filter):Promise<Array<query.IssueSummary>>;
+
+export function LanguageActivity(arg1:string,arg2:string,arg3:string,arg4:query.Filter):Promise<Array<query.LanguageSeries>>;
+
+export function LanguageBreakdown(arg1:string,arg2:string,arg3:query.Filter):Promise<Array<query.LanguageStat>>;
+
+export function LineHistory(arg1:string,arg2:number,arg3:number,arg4:query.Filter):Promise<Array<query.LineRangeCommit>>;
 
 export function OpenRepository(arg1:string):Promise<void>;
 
 export function OpenURL(arg1:string):Promise<void>;
 
+export function PullRequestFiles(arg1:number,arg2:query.Filter):Promise<Array<query.FileHotspot>>;
+
+export function PullRequestSource():Promise<config.PullRequestSource>;
+
 export function RecentRepos():Promise<Array<config.RecentRepo>>;
 
 export function RemoveRecentRepo(arg1:string):Promise<void>;
 
 export function RepoInfo():Promise<main.RepoInfo>;
 
+export function RevertSeries(arg1:string,arg2:string,arg3:string,arg4:query.Filter):Promise<Array<query.RevertPoint>>;
+
+export function RevertedFiles(arg1:string,arg2:string,arg3:query.Filter):Promise<Array<query.RevertedFile>>;
+
+export function Reverts(arg1:string,arg2:string,arg3:query.Filter):Promise<Array<query.Revert>>;
+
+export function ReviewLatency(arg1:string,arg2:string,arg3:string,arg4:query.Filter):Promise<Array<query.ReviewLatencyPoint>>;
+
+export function ReviewerLoads(arg1:string,arg2:string,arg3:query.Filter):Promise<Array<query.ReviewerLoad>>;
+
+export function Rework(arg1:string,arg2:string,arg3:string,arg4:number,arg5:query.Filter):Promise<query.ReworkReport>;
+
+export function SearchCommits(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:query.Filter):Promise<Array<query.CommitMatch>>;
+
 export function SelectDirectory():Promise<string>;
 
-export function TemporalHotspots(arg1:string,arg2:string,arg3:number,arg4:Array<string>):Promise<Array<query.TemporalHotspot>>;
+export function SetHunkExtraction(arg1:boolean):Promise<void>;
+
+export function SetIssuePatterns(arg1:Array<string>):Promise<void>;
+
+export function SurvivalCurves(arg1:string,arg2:query.Filter):Promise<Array<query.SurvivalCurve>>;
+
+export function TemporalHotspots(arg1:string,arg2:string,arg3:number,arg4:query.Filter):Promise<Array<query.TemporalHotspot>>;
 
 export function Version():Promise<string>;
@@ -2,20 +2,64 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function ActivitySeries(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['ActivitySeries'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function AuthorLanguages(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AuthorLanguages'](arg1, arg2, arg3);
+}
+
+export function AuthorProfile(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['AuthorProfile'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function BinaryChurn(arg1, arg2, arg3) {
+  return window['go']['main']['App']['BinaryChurn'](arg1, arg2, arg3);
+}
+
+export function BlameOwnerships(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['BlameOwnerships'](arg1, arg2, arg3, arg4);
+}
+
+export function ChangeTypeSeries(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['ChangeTypeSeries'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function CheckForUpdate() {
   return window['go']['main']['App']['CheckForUpdate']();
 }
 
+export function ClassifiedPaths() {
+  return window['go']['main']['App']['ClassifiedPaths']();
+}
+
 export function CoChanges(arg1, arg2, arg3, arg4, arg5) {
   return window['go']['main']['App']['CoChanges'](arg1, arg2, arg3, arg4, arg5);
 }
 
-export function CommitHeatmap(arg1, arg2, arg3) {
-  return window['go']['main']['App']['CommitHeatmap'](arg1, arg2, arg3);
+export function CodeAge(arg1) {
+  return window['go']['main']['App']['CodeAge'](arg1);
+}
+
+export function CommitHeatmap(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CommitHeatmap'](arg1, arg2, arg3, arg4);
+}
+
+export function CommitPunchcard(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['CommitPunchcard'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function CommitSizes(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['CommitSizes'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function CommitsByHour(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CommitsByHour'](arg1, arg2, arg3);
 }
 
-export function CommitsByHour(arg1, arg2) {
-  return window['go']['main']['App']['CommitsByHour'](arg1, arg2);
+export function ComplexityTrend(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ComplexityTrend'](arg1, arg2, arg3);
 }
 
 export function Contributors(arg1, arg2, arg3) {
@@ -26,14 +70,74 @@ export function DashboardStats(arg1, arg2, arg3) {
   return window['go']['main']['App']['DashboardStats'](arg1, arg2, arg3);
 }
 
+export function DefectHotspots(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['DefectHotspots'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function FileHotspots(arg1, arg2, arg3) {
   return window['go']['main']['App']['FileHotspots'](arg1, arg2, arg3);
 }
 
+export function FileIssues(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['FileIssues'](arg1, arg2, arg3, arg4);
+}
+
 export function FileOwnerships(arg1, arg2, arg3) {
   return window['go']['main']['App']['FileOwnerships'](arg1, arg2, arg3);
 }
 
+export function FileProfile(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['FileProfile'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function FunctionHotspots(arg1, arg2, arg3) {
+  return window['go']['main']['App']['FunctionHotspots'](arg1, arg2, arg3);
+}
+
+export function FunctionParseErrors(arg1) {
+  return window['go']['main']['App']['FunctionParseErrors'](arg1);
+}
+
+export function HunkExtraction() {
+  return window['go']['main']['App']['HunkExtraction']();
+}
+
+export function ImportIssues(arg1) {
+  return window['go']['main']['App']['ImportIssues'](arg1);
+}
+
+export function ImportPullRequests(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ImportPullRequests'](arg1, arg2, arg3, arg4);
+}
+
+export function IssueCommits(arg1, arg2) {
+  return window['go']['main']['App']['IssueCommits'](arg1, arg2);
+}
+
+export function IssueFiles(arg1, arg2) {
+  return window['go']['main']['App']['IssueFiles'](arg1, arg2);
+}
+
+export function IssuePatterns() {
+  return window['go']['main']['App']['IssuePatterns']();
+}
+
+export function Issues(arg1, arg2, arg3) {
+  return window['go']['main']['App']['Issues'](arg1, arg2, arg3);
+}
+
+export function LanguageActivity(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['LanguageActivity'](arg1, arg2, arg3, arg4);
+}
+
+export function LanguageBreakdown(arg1, arg2, arg3) {
+  return window['go']['main']['App']['LanguageBreakdown'](arg1, arg2, arg3);
+}
+
+export function LineHistory(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['LineHistory'](arg1, arg2, arg3, arg4);
+}
+
 export function OpenRepository(arg1) {
   return window['go']['main']['App']['OpenRepository'](arg1);
 }
@@ -42,6 +146,14 @@ export function OpenURL(arg1) {
   return window['go']['main']['App']['OpenURL'](arg1);
 }
 
+export function PullRequestFiles(arg1, arg2) {
+  return window['go']['main']['App']['PullRequestFiles'](arg1, arg2);
+}
+
+export function PullRequestSource() {
+  return window['go']['main']['App']['PullRequestSource']();
+}
+
 export function RecentRepos() {
   return window['go']['main']['App']['RecentRepos']();
 }
@@ -54,10 +166,50 @@ export function RepoInfo() {
   return window['go']['main']['App']['RepoInfo']();
 }
 
+export function RevertSeries(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['RevertSeries'](arg1, arg2, arg3, arg4);
+}
+
+export function RevertedFiles(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RevertedFiles'](arg1, arg2, arg3);
+}
+
+export function Reverts(arg1, arg2, arg3) {
+  return window['go']['main']['App']['Reverts'](arg1, arg2, arg3);
+}
+
+export function ReviewLatency(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ReviewLatency'](arg1, arg2, arg3, arg4);
+}
+
+export function ReviewerLoads(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ReviewerLoads'](arg1, arg2, arg3);
+}
+
+export function Rework(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['Rework'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function SearchCommits(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['SearchCommits'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
 export function SelectDirectory() {
   return window['go']['main']['App']['SelectDirectory']();
 }
 
+export function SetHunkExtraction(arg1) {
+  return window['go']['main']['App']['SetHunkExtraction'](arg1);
+}
+
+export function SetIssuePatterns(arg1) {
+  return window['go']['main']['App']['SetIssuePatterns'](arg1);
+}
+
+export function SurvivalCurves(arg1, arg2) {
+  return window['go']['main']['App']['SurvivalCurves'](arg1, arg2);
+}
+
 export function TemporalHotspots(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['TemporalHotspots'](arg1, arg2, arg3, arg4);
 }
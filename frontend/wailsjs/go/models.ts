@@ -1,5 +1,21 @@
 export namespace config {
 	
+	export class PullRequestSource {
+	    provider: string;
+	    base_url: string;
+	    repository: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PullRequestSource(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
+	        this.base_url = source["base_url"];
+	        this.repository = source["repository"];
+	    }
+	}
 	export class RecentRepo {
 	    path: string;
 	    name: string;
@@ -85,6 +101,102 @@ export namespace main {
 
 export namespace query {
 	
+	export class AgeBucket {
+	    label: string;
+	    min_days: number;
+	    max_days: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgeBucket(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.min_days = source["min_days"];
+	        this.max_days = source["max_days"];
+	        this.count = source["count"];
+	    }
+	}
+	export class AuthorCommitSize {
+	    author_name: string;
+	    author_email: string;
+	    commits: number;
+	    median_lines: number;
+	    p90_lines: number;
+	    median_files: number;
+	    large_commits: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuthorCommitSize(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.author_name = source["author_name"];
+	        this.author_email = source["author_email"];
+	        this.commits = source["commits"];
+	        this.median_lines = source["median_lines"];
+	        this.p90_lines = source["p90_lines"];
+	        this.median_files = source["median_files"];
+	        this.large_commits = source["large_commits"];
+	    }
+	}
+	export class LanguageShare {
+	    language: string;
+	    files: number;
+	    lines_changed: number;
+	    pct: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LanguageShare(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.language = source["language"];
+	        this.files = source["files"];
+	        this.lines_changed = source["lines_changed"];
+	        this.pct = source["pct"];
+	    }
+	}
+	export class AuthorLanguageMix {
+	    author_name: string;
+	    author_email: string;
+	    lines_changed: number;
+	    languages: LanguageShare[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AuthorLanguageMix(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.author_name = source["author_name"];
+	        this.author_email = source["author_email"];
+	        this.lines_changed = source["lines_changed"];
+	        this.languages = this.convertValues(source["languages"], LanguageShare);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class CoChangePair {
 	    file_a: string;
 	    file_b: string;
@@ -107,122 +219,1273 @@ export namespace query {
 	        this.coupling_ratio = source["coupling_ratio"];
 	    }
 	}
-	export class Contributor {
-	    author_name: string;
-	    author_email: string;
-	    commits: number;
+	export class FileOwnership {
+	    path: string;
+	    top_author_name: string;
+	    top_author_email: string;
+	    top_author_pct: number;
+	    second_author_name: string;
+	    second_author_email: string;
+	    second_author_pct: number;
+	    contributor_count: number;
+	    total_lines: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileOwnership(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.top_author_name = source["top_author_name"];
+	        this.top_author_email = source["top_author_email"];
+	        this.top_author_pct = source["top_author_pct"];
+	        this.second_author_name = source["second_author_name"];
+	        this.second_author_email = source["second_author_email"];
+	        this.second_author_pct = source["second_author_pct"];
+	        this.contributor_count = source["contributor_count"];
+	        this.total_lines = source["total_lines"];
+	    }
+	}
+	export class FileHotspot {
+	    path: string;
+	    lines_changed: number;
 	    additions: number;
 	    deletions: number;
+	    commits: number;
+	    code: number;
+	    comment: number;
+	    blank: number;
+	    indent: number;
+	    max_indent: number;
+	    cyclomatic: number;
+	    quadrant: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Contributor(source);
+	        return new FileHotspot(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.author_name = source["author_name"];
-	        this.author_email = source["author_email"];
-	        this.commits = source["commits"];
+	        this.path = source["path"];
+	        this.lines_changed = source["lines_changed"];
 	        this.additions = source["additions"];
 	        this.deletions = source["deletions"];
+	        this.commits = source["commits"];
+	        this.code = source["code"];
+	        this.comment = source["comment"];
+	        this.blank = source["blank"];
+	        this.indent = source["indent"];
+	        this.max_indent = source["max_indent"];
+	        this.cyclomatic = source["cyclomatic"];
+	        this.quadrant = source["quadrant"];
 	    }
 	}
-	export class DashboardStats {
+	export class Punchcard {
+	    timezone: string;
+	    counts: number[][];
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Punchcard(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.timezone = source["timezone"];
+	        this.counts = source["counts"];
+	        this.total = source["total"];
+	    }
+	}
+	export class SeriesPoint {
+	    period: string;
 	    commits: number;
-	    contributors: number;
 	    additions: number;
 	    deletions: number;
-	    files_changed: number;
+	    authors: number;
+	    files_touched: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new DashboardStats(source);
+	        return new SeriesPoint(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.period = source["period"];
 	        this.commits = source["commits"];
-	        this.contributors = source["contributors"];
 	        this.additions = source["additions"];
 	        this.deletions = source["deletions"];
-	        this.files_changed = source["files_changed"];
+	        this.authors = source["authors"];
+	        this.files_touched = source["files_touched"];
 	    }
 	}
-	export class FileHotspot {
-	    path: string;
-	    lines_changed: number;
-	    additions: number;
-	    deletions: number;
+	export class AuthorProfile {
+	    author_name: string;
+	    author_email: string;
 	    commits: number;
+	    first_commit: string;
+	    last_commit: string;
+	    avg_lines_per_commit: number;
+	    avg_files_per_commit: number;
+	    series: SeriesPoint[];
+	    punchcard?: Punchcard;
+	    top_files: FileHotspot[];
+	    top_directories: FileHotspot[];
+	    owned_files: FileOwnership[];
+	    languages: LanguageShare[];
+	    co_changes: CoChangePair[];
 	
 	    static createFrom(source: any = {}) {
-	        return new FileHotspot(source);
+	        return new AuthorProfile(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
-	        this.lines_changed = source["lines_changed"];
-	        this.additions = source["additions"];
-	        this.deletions = source["deletions"];
+	        this.author_name = source["author_name"];
+	        this.author_email = source["author_email"];
 	        this.commits = source["commits"];
+	        this.first_commit = source["first_commit"];
+	        this.last_commit = source["last_commit"];
+	        this.avg_lines_per_commit = source["avg_lines_per_commit"];
+	        this.avg_files_per_commit = source["avg_files_per_commit"];
+	        this.series = this.convertValues(source["series"], SeriesPoint);
+	        this.punchcard = this.convertValues(source["punchcard"], Punchcard);
+	        this.top_files = this.convertValues(source["top_files"], FileHotspot);
+	        this.top_directories = this.convertValues(source["top_directories"], FileHotspot);
+	        this.owned_files = this.convertValues(source["owned_files"], FileOwnership);
+	        this.languages = this.convertValues(source["languages"], LanguageShare);
+	        this.co_changes = this.convertValues(source["co_changes"], CoChangePair);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BinaryFile {
+	    path: string;
+	    changes: number;
+	    size_delta: number;
+	    bytes_changed: number;
+	    last_changed: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BinaryFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.changes = source["changes"];
+	        this.size_delta = source["size_delta"];
+	        this.bytes_changed = source["bytes_changed"];
+	        this.last_changed = source["last_changed"];
 	    }
 	}
-	export class FileOwnership {
+	export class BlameOwnership {
 	    path: string;
+	    lines: number;
 	    top_author_name: string;
 	    top_author_email: string;
 	    top_author_pct: number;
 	    second_author_name: string;
 	    second_author_email: string;
 	    second_author_pct: number;
-	    contributor_count: number;
-	    total_lines: number;
+	    author_count: number;
+	    churn_top_author_name: string;
+	    churn_top_author_email: string;
+	    churn_top_author_pct: number;
+	    churn_lines: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new FileOwnership(source);
+	        return new BlameOwnership(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.path = source["path"];
+	        this.lines = source["lines"];
 	        this.top_author_name = source["top_author_name"];
 	        this.top_author_email = source["top_author_email"];
 	        this.top_author_pct = source["top_author_pct"];
 	        this.second_author_name = source["second_author_name"];
 	        this.second_author_email = source["second_author_email"];
 	        this.second_author_pct = source["second_author_pct"];
-	        this.contributor_count = source["contributor_count"];
-	        this.total_lines = source["total_lines"];
+	        this.author_count = source["author_count"];
+	        this.churn_top_author_name = source["churn_top_author_name"];
+	        this.churn_top_author_email = source["churn_top_author_email"];
+	        this.churn_top_author_pct = source["churn_top_author_pct"];
+	        this.churn_lines = source["churn_lines"];
 	    }
 	}
-	export class HeatmapDay {
-	    date: string;
-	    count: number;
+	export class ChangeTypePoint {
+	    period: string;
+	    counts: Record<string, number>;
+	    total: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new HeatmapDay(source);
+	        return new ChangeTypePoint(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.date = source["date"];
-	        this.count = source["count"];
+	        this.period = source["period"];
+	        this.counts = source["counts"];
+	        this.total = source["total"];
 	    }
 	}
-	export class HourBucket {
-	    hour: number;
-	    count: number;
+	export class ClassifiedPath {
+	    path: string;
+	    generated: boolean;
+	    vendored: boolean;
+	    documentation: boolean;
+	    exists: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new HourBucket(source);
+	        return new ClassifiedPath(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.hour = source["hour"];
-	        this.count = source["count"];
+	        this.path = source["path"];
+	        this.generated = source["generated"];
+	        this.vendored = source["vendored"];
+	        this.documentation = source["documentation"];
+	        this.exists = source["exists"];
+	    }
+	}
+	
+	export class DirectoryAge {
+	    directory: string;
+	    files: number;
+	    median_age_days: number;
+	    buckets: AgeBucket[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DirectoryAge(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.directory = source["directory"];
+	        this.files = source["files"];
+	        this.median_age_days = source["median_age_days"];
+	        this.buckets = this.convertValues(source["buckets"], AgeBucket);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FileAge {
+	    path: string;
+	    created: string;
+	    last_modified: string;
+	    age_days: number;
+	    lifetime_days: number;
+	    commits: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileAge(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.created = source["created"];
+	        this.last_modified = source["last_modified"];
+	        this.age_days = source["age_days"];
+	        this.lifetime_days = source["lifetime_days"];
+	        this.commits = source["commits"];
 	    }
 	}
+	export class CodeAgeReport {
+	    files: FileAge[];
+	    directories: DirectoryAge[];
+	    buckets: AgeBucket[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CodeAgeReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.files = this.convertValues(source["files"], FileAge);
+	        this.directories = this.convertValues(source["directories"], DirectoryAge);
+	        this.buckets = this.convertValues(source["buckets"], AgeBucket);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CommitMatch {
+	    hash: string;
+	    author_name: string;
+	    author_email: string;
+	    date: string;
+	    subject: string;
+	    snippet: string;
+	    score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitMatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hash = source["hash"];
+	        this.author_name = source["author_name"];
+	        this.author_email = source["author_email"];
+	        this.date = source["date"];
+	        this.subject = source["subject"];
+	        this.snippet = source["snippet"];
+	        this.score = source["score"];
+	    }
+	}
+	export class CommitSize {
+	    hash: string;
+	    author_name: string;
+	    author_email: string;
+	    date: string;
+	    subject: string;
+	    files: number;
+	    lines: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitSize(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hash = source["hash"];
+	        this.author_name = source["author_name"];
+	        this.author_email = source["author_email"];
+	        this.date = source["date"];
+	        this.subject = source["subject"];
+	        this.files = source["files"];
+	        this.lines = source["lines"];
+	    }
+	}
+	export class CommitSizePoint {
+	    period: string;
+	    commits: number;
+	    median_lines: number;
+	    p90_lines: number;
+	    median_files: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitSizePoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.period = source["period"];
+	        this.commits = source["commits"];
+	        this.median_lines = source["median_lines"];
+	        this.p90_lines = source["p90_lines"];
+	        this.median_files = source["median_files"];
+	    }
+	}
+	export class SizeBucket {
+	    label: string;
+	    min: number;
+	    max: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SizeBucket(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.min = source["min"];
+	        this.max = source["max"];
+	        this.count = source["count"];
+	    }
+	}
+	export class SizeStats {
+	    buckets: SizeBucket[];
+	    p50: number;
+	    p75: number;
+	    p90: number;
+	    p95: number;
+	    max: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SizeStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.buckets = this.convertValues(source["buckets"], SizeBucket);
+	        this.p50 = source["p50"];
+	        this.p75 = source["p75"];
+	        this.p90 = source["p90"];
+	        this.p95 = source["p95"];
+	        this.max = source["max"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CommitSizeReport {
+	    commits: number;
+	    lines: SizeStats;
+	    files: SizeStats;
+	    authors: AuthorCommitSize[];
+	    series: CommitSizePoint[];
+	    outlier_threshold: number;
+	    outliers: CommitSize[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitSizeReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.commits = source["commits"];
+	        this.lines = this.convertValues(source["lines"], SizeStats);
+	        this.files = this.convertValues(source["files"], SizeStats);
+	        this.authors = this.convertValues(source["authors"], AuthorCommitSize);
+	        this.series = this.convertValues(source["series"], CommitSizePoint);
+	        this.outlier_threshold = source["outlier_threshold"];
+	        this.outliers = this.convertValues(source["outliers"], CommitSize);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ComplexityPoint {
+	    hash: string;
+	    date: string;
+	    period: string;
+	    path: string;
+	    lines: number;
+	    code: number;
+	    comment: number;
+	    blank: number;
+	    indent: number;
+	    max_indent: number;
+	    cyclomatic: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ComplexityPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hash = source["hash"];
+	        this.date = source["date"];
+	        this.period = source["period"];
+	        this.path = source["path"];
+	        this.lines = source["lines"];
+	        this.code = source["code"];
+	        this.comment = source["comment"];
+	        this.blank = source["blank"];
+	        this.indent = source["indent"];
+	        this.max_indent = source["max_indent"];
+	        this.cyclomatic = source["cyclomatic"];
+	    }
+	}
+	export class Contributor {
+	    author_name: string;
+	    author_email: string;
+	    commits: number;
+	    additions: number;
+	    deletions: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Contributor(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.author_name = source["author_name"];
+	        this.author_email = source["author_email"];
+	        this.commits = source["commits"];
+	        this.additions = source["additions"];
+	        this.deletions = source["deletions"];
+	    }
+	}
+	export class CoupledFile {
+	    path: string;
+	    co_change_count: number;
+	    coupling_ratio: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CoupledFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.co_change_count = source["co_change_count"];
+	        this.coupling_ratio = source["coupling_ratio"];
+	    }
+	}
+	export class DashboardStats {
+	    commits: number;
+	    contributors: number;
+	    additions: number;
+	    deletions: number;
+	    files_changed: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DashboardStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.commits = source["commits"];
+	        this.contributors = source["contributors"];
+	        this.additions = source["additions"];
+	        this.deletions = source["deletions"];
+	        this.files_changed = source["files_changed"];
+	    }
+	}
+	export class DefectHotspot {
+	    path: string;
+	    commits: number;
+	    fix_commits: number;
+	    fix_ratio: number;
+	    last_fix: string;
+	    days_since_fix: number;
+	    score: number;
+	    temporal_score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DefectHotspot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.commits = source["commits"];
+	        this.fix_commits = source["fix_commits"];
+	        this.fix_ratio = source["fix_ratio"];
+	        this.last_fix = source["last_fix"];
+	        this.days_since_fix = source["days_since_fix"];
+	        this.score = source["score"];
+	        this.temporal_score = source["temporal_score"];
+	    }
+	}
+	
+	
+	export class FileAuthor {
+	    author_name: string;
+	    author_email: string;
+	    commits: number;
+	    additions: number;
+	    deletions: number;
+	    pct: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileAuthor(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.author_name = source["author_name"];
+	        this.author_email = source["author_email"];
+	        this.commits = source["commits"];
+	        this.additions = source["additions"];
+	        this.deletions = source["deletions"];
+	        this.pct = source["pct"];
+	    }
+	}
+	export class FileCommit {
+	    hash: string;
+	    author_name: string;
+	    author_email: string;
+	    date: string;
+	    subject: string;
+	    path: string;
+	    status: string;
+	    additions: number;
+	    deletions: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileCommit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hash = source["hash"];
+	        this.author_name = source["author_name"];
+	        this.author_email = source["author_email"];
+	        this.date = source["date"];
+	        this.subject = source["subject"];
+	        this.path = source["path"];
+	        this.status = source["status"];
+	        this.additions = source["additions"];
+	        this.deletions = source["deletions"];
+	    }
+	}
+	
+	
+	export class FileProfile {
+	    path: string;
+	    previous_paths: string[];
+	    exists: boolean;
+	    created: string;
+	    deleted: string;
+	    commits: FileCommit[];
+	    series: SeriesPoint[];
+	    authors: FileAuthor[];
+	    coupled: CoupledFile[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FileProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.previous_paths = source["previous_paths"];
+	        this.exists = source["exists"];
+	        this.created = source["created"];
+	        this.deleted = source["deleted"];
+	        this.commits = this.convertValues(source["commits"], FileCommit);
+	        this.series = this.convertValues(source["series"], SeriesPoint);
+	        this.authors = this.convertValues(source["authors"], FileAuthor);
+	        this.coupled = this.convertValues(source["coupled"], CoupledFile);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Filter {
+	    exclude_globs: string[];
+	    include_globs: string[];
+	    change_types: string[];
+	    exclude_reverts: boolean;
+	    existing_only: boolean;
+	    binary: string;
+	    include_generated: boolean;
+	    include_vendored: boolean;
+	    exclude_documentation: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Filter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.exclude_globs = source["exclude_globs"];
+	        this.include_globs = source["include_globs"];
+	        this.change_types = source["change_types"];
+	        this.exclude_reverts = source["exclude_reverts"];
+	        this.existing_only = source["existing_only"];
+	        this.binary = source["binary"];
+	        this.include_generated = source["include_generated"];
+	        this.include_vendored = source["include_vendored"];
+	        this.exclude_documentation = source["exclude_documentation"];
+	    }
+	}
+	export class FunctionHotspot {
+	    path: string;
+	    name: string;
+	    start_line: number;
+	    end_line: number;
+	    lines_changed: number;
+	    commits: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FunctionHotspot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.start_line = source["start_line"];
+	        this.end_line = source["end_line"];
+	        this.lines_changed = source["lines_changed"];
+	        this.commits = source["commits"];
+	    }
+	}
+	export class FunctionParseError {
+	    path: string;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FunctionParseError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.error = source["error"];
+	    }
+	}
+	export class HeatmapDay {
+	    date: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HeatmapDay(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.count = source["count"];
+	    }
+	}
+	export class HourBucket {
+	    hour: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HourBucket(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hour = source["hour"];
+	        this.count = source["count"];
+	    }
+	}
+	export class IssueCommit {
+	    hash: string;
+	    author_name: string;
+	    author_email: string;
+	    date: string;
+	    subject: string;
+	    additions: number;
+	    deletions: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new IssueCommit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hash = source["hash"];
+	        this.author_name = source["author_name"];
+	        this.author_email = source["author_email"];
+	        this.date = source["date"];
+	        this.subject = source["subject"];
+	        this.additions = source["additions"];
+	        this.deletions = source["deletions"];
+	    }
+	}
+	export class IssueSummary {
+	    key: string;
+	    type: string;
+	    title: string;
+	    status: string;
+	    created_at: string;
+	    resolved_at: string;
+	    commits: number;
+	    files: number;
+	    additions: number;
+	    deletions: number;
+	    first_commit: string;
+	    last_commit: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new IssueSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.type = source["type"];
+	        this.title = source["title"];
+	        this.status = source["status"];
+	        this.created_at = source["created_at"];
+	        this.resolved_at = source["resolved_at"];
+	        this.commits = source["commits"];
+	        this.files = source["files"];
+	        this.additions = source["additions"];
+	        this.deletions = source["deletions"];
+	        this.first_commit = source["first_commit"];
+	        this.last_commit = source["last_commit"];
+	    }
+	}
+	export class LanguagePoint {
+	    period: string;
+	    lines_changed: number;
+	    additions: number;
+	    deletions: number;
+	    commits: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LanguagePoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.period = source["period"];
+	        this.lines_changed = source["lines_changed"];
+	        this.additions = source["additions"];
+	        this.deletions = source["deletions"];
+	        this.commits = source["commits"];
+	    }
+	}
+	export class LanguageSeries {
+	    language: string;
+	    lines_changed: number;
+	    points: LanguagePoint[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LanguageSeries(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.language = source["language"];
+	        this.lines_changed = source["lines_changed"];
+	        this.points = this.convertValues(source["points"], LanguagePoint);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class LanguageStat {
+	    language: string;
+	    files: number;
+	    code: number;
+	    comment: number;
+	    blank: number;
+	    lines_changed: number;
+	    additions: number;
+	    deletions: number;
+	    commits: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LanguageStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.language = source["language"];
+	        this.files = source["files"];
+	        this.code = source["code"];
+	        this.comment = source["comment"];
+	        this.blank = source["blank"];
+	        this.lines_changed = source["lines_changed"];
+	        this.additions = source["additions"];
+	        this.deletions = source["deletions"];
+	        this.commits = source["commits"];
+	    }
+	}
+	export class LineRangeCommit {
+	    hash: string;
+	    author_name: string;
+	    author_email: string;
+	    date: string;
+	    subject: string;
+	    path: string;
+	    start: number;
+	    end: number;
+	    changed: number;
+	    removed: number;
+	    coalesced: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new LineRangeCommit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hash = source["hash"];
+	        this.author_name = source["author_name"];
+	        this.author_email = source["author_email"];
+	        this.date = source["date"];
+	        this.subject = source["subject"];
+	        this.path = source["path"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.changed = source["changed"];
+	        this.removed = source["removed"];
+	        this.coalesced = source["coalesced"];
+	    }
+	}
+	
+	export class Revert {
+	    hash: string;
+	    author_name: string;
+	    author_email: string;
+	    date: string;
+	    subject: string;
+	    method: string;
+	    original_hash: string;
+	    original_author_email: string;
+	    original_date: string;
+	    original_subject: string;
+	    hours_to_revert: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Revert(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hash = source["hash"];
+	        this.author_name = source["author_name"];
+	        this.author_email = source["author_email"];
+	        this.date = source["date"];
+	        this.subject = source["subject"];
+	        this.method = source["method"];
+	        this.original_hash = source["original_hash"];
+	        this.original_author_email = source["original_author_email"];
+	        this.original_date = source["original_date"];
+	        this.original_subject = source["original_subject"];
+	        this.hours_to_revert = source["hours_to_revert"];
+	    }
+	}
+	export class RevertPoint {
+	    period: string;
+	    commits: number;
+	    reverts: number;
+	    rate: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RevertPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.period = source["period"];
+	        this.commits = source["commits"];
+	        this.reverts = source["reverts"];
+	        this.rate = source["rate"];
+	    }
+	}
+	export class RevertedFile {
+	    path: string;
+	    reverted: number;
+	    commits: number;
+	    revert_rate: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RevertedFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.reverted = source["reverted"];
+	        this.commits = source["commits"];
+	        this.revert_rate = source["revert_rate"];
+	    }
+	}
+	export class ReviewLatencyPoint {
+	    period: string;
+	    opened: number;
+	    reviewed: number;
+	    merged: number;
+	    median_first_review_hours: number;
+	    median_merge_hours: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReviewLatencyPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.period = source["period"];
+	        this.opened = source["opened"];
+	        this.reviewed = source["reviewed"];
+	        this.merged = source["merged"];
+	        this.median_first_review_hours = source["median_first_review_hours"];
+	        this.median_merge_hours = source["median_merge_hours"];
+	    }
+	}
+	export class ReviewerLoad {
+	    reviewer: string;
+	    requested: number;
+	    pull_requests: number;
+	    reviews: number;
+	    approvals: number;
+	    median_response_hours: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReviewerLoad(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.reviewer = source["reviewer"];
+	        this.requested = source["requested"];
+	        this.pull_requests = source["pull_requests"];
+	        this.reviews = source["reviews"];
+	        this.approvals = source["approvals"];
+	        this.median_response_hours = source["median_response_hours"];
+	    }
+	}
+	export class ReworkAuthor {
+	    author_name: string;
+	    author_email: string;
+	    churn: number;
+	    rework: number;
+	    self_rework: number;
+	    ratio: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReworkAuthor(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.author_name = source["author_name"];
+	        this.author_email = source["author_email"];
+	        this.churn = source["churn"];
+	        this.rework = source["rework"];
+	        this.self_rework = source["self_rework"];
+	        this.ratio = source["ratio"];
+	    }
+	}
+	export class ReworkFile {
+	    path: string;
+	    churn: number;
+	    rework: number;
+	    self_rework: number;
+	    ratio: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReworkFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.churn = source["churn"];
+	        this.rework = source["rework"];
+	        this.self_rework = source["self_rework"];
+	        this.ratio = source["ratio"];
+	    }
+	}
+	export class ReworkPoint {
+	    period: string;
+	    churn: number;
+	    rework: number;
+	    self_rework: number;
+	    ratio: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReworkPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.period = source["period"];
+	        this.churn = source["churn"];
+	        this.rework = source["rework"];
+	        this.self_rework = source["self_rework"];
+	        this.ratio = source["ratio"];
+	    }
+	}
+	export class ReworkReport {
+	    days: number;
+	    churn: number;
+	    rework: number;
+	    self_rework: number;
+	    ratio: number;
+	    authors: ReworkAuthor[];
+	    files: ReworkFile[];
+	    series: ReworkPoint[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ReworkReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.days = source["days"];
+	        this.churn = source["churn"];
+	        this.rework = source["rework"];
+	        this.self_rework = source["self_rework"];
+	        this.ratio = source["ratio"];
+	        this.authors = this.convertValues(source["authors"], ReworkAuthor);
+	        this.files = this.convertValues(source["files"], ReworkFile);
+	        this.series = this.convertValues(source["series"], ReworkPoint);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class SurvivalPoint {
+	    age_months: number;
+	    lines: number;
+	    initial: number;
+	    fraction: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SurvivalPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.age_months = source["age_months"];
+	        this.lines = source["lines"];
+	        this.initial = source["initial"];
+	        this.fraction = source["fraction"];
+	    }
+	}
+	export class SurvivalCurve {
+	    key: string;
+	    name: string;
+	    cohorts: number;
+	    initial_lines: number;
+	    points: SurvivalPoint[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SurvivalCurve(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.name = source["name"];
+	        this.cohorts = source["cohorts"];
+	        this.initial_lines = source["initial_lines"];
+	        this.points = this.convertValues(source["points"], SurvivalPoint);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
 	export class TemporalHotspot {
 	    path: string;
 	    lines_changed: number;
@@ -232,6 +1495,13 @@ export namespace query {
 	    last_changed: string;
 	    days_since: number;
 	    score: number;
+	    code: number;
+	    comment: number;
+	    blank: number;
+	    indent: number;
+	    max_indent: number;
+	    cyclomatic: number;
+	    quadrant: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new TemporalHotspot(source);
@@ -247,6 +1517,13 @@ export namespace query {
 	        this.last_changed = source["last_changed"];
 	        this.days_since = source["days_since"];
 	        this.score = source["score"];
+	        this.code = source["code"];
+	        this.comment = source["comment"];
+	        this.blank = source["blank"];
+	        this.indent = source["indent"];
+	        this.max_indent = source["max_indent"];
+	        this.cyclomatic = source["cyclomatic"];
+	        this.quadrant = source["quadrant"];
 	    }
 	}
 
@@ -0,0 +1,115 @@
+// Package classify derives a change type from a commit message, following
+// the Conventional Commits specification where the message uses it and
+// falling back to keyword heuristics otherwise.
+package classify
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Change types. The Conventional Commits types are recognized verbatim;
+// TypeMerge and TypeOther are only produced by the fallback heuristics.
+const (
+	TypeFeat     = "feat"
+	TypeFix      = "fix"
+	TypeDocs     = "docs"
+	TypeStyle    = "style"
+	TypeRefactor = "refactor"
+	TypePerf     = "perf"
+	TypeTest     = "test"
+	TypeBuild    = "build"
+	TypeCI       = "ci"
+	TypeChore    = "chore"
+	TypeRevert   = "revert"
+	TypeMerge    = "merge"
+	TypeOther    = "other"
+)
+
+// Classification is the result of classifying a commit message.
+type Classification struct {
+	Type         string
+	Scope        string
+	Breaking     bool
+	Conventional bool // true if the subject follows Conventional Commits
+}
+
+// conventionalTypes maps accepted type spellings to canonical change types.
+var conventionalTypes = map[string]string{
+	"feat":     TypeFeat,
+	"feature":  TypeFeat,
+	"fix":      TypeFix,
+	"bugfix":   TypeFix,
+	"hotfix":   TypeFix,
+	"docs":     TypeDocs,
+	"doc":      TypeDocs,
+	"style":    TypeStyle,
+	"refactor": TypeRefactor,
+	"perf":     TypePerf,
+	"test":     TypeTest,
+	"tests":    TypeTest,
+	"build":    TypeBuild,
+	"ci":       TypeCI,
+	"chore":    TypeChore,
+	"revert":   TypeRevert,
+}
+
+// conventionalRe matches "type(scope)!: description".
+var conventionalRe = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^)]*)\))?(!)?:\s`)
+
+// breakingRe matches a BREAKING CHANGE footer in the message body.
+var breakingRe = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:`)
+
+// Keyword heuristics for messages that don't follow Conventional Commits,
+// checked in order.
+var (
+	revertRe   = regexp.MustCompile(`(?i)\brevert(s|ed|ing)?\b`)
+	fixRe      = regexp.MustCompile(`(?i)\b(fix(es|ed|ing)?|bugs?|bugfix|hotfix)\b`)
+	refactorRe = regexp.MustCompile(`(?i)\brefactor(s|ed|ing)?\b`)
+)
+
+// Classify derives the change type of a commit from its subject line and
+// description body.
+func Classify(subject, body string) Classification {
+	subject = strings.TrimSpace(subject)
+	breaking := breakingRe.MatchString(body)
+
+	if m := conventionalRe.FindStringSubmatch(subject); m != nil {
+		if t, ok := conventionalTypes[strings.ToLower(m[1])]; ok {
+			return Classification{
+				Type:         t,
+				Scope:        strings.TrimSpace(m[2]),
+				Breaking:     breaking || m[3] == "!",
+				Conventional: true,
+			}
+		}
+	}
+
+	c := Classification{Type: TypeOther, Breaking: breaking}
+	switch {
+	case strings.HasPrefix(subject, "Merge "):
+		c.Type = TypeMerge
+	case revertRe.MatchString(subject):
+		c.Type = TypeRevert
+	case fixRe.MatchString(subject):
+		c.Type = TypeFix
+	case refactorRe.MatchString(subject):
+		c.Type = TypeRefactor
+	}
+	return c
+}
+
+// revertedRe matches the trailer `git revert` writes into the message body.
+var revertedRe = regexp.MustCompile(`This reverts commit ([0-9a-f]{7,40})`)
+
+// RevertedCommit returns the (possibly abbreviated) hash of the commit that
+// a `git revert` commit reverts, or an empty string if the message does not
+// name one.
+func RevertedCommit(subject, body string) string {
+	for _, text := range []string{body, subject} {
+		if m := revertedRe.FindStringSubmatch(text); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
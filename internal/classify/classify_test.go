@@ -0,0 +1,52 @@
+package classify_test
+
+import (
+	"testing"
+
+	"git-analytics/internal/classify"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		subject string
+		body    string
+		want    classify.Classification
+	}{
+		{"feat: add punchcard", "", classify.Classification{Type: "feat", Conventional: true}},
+		{"fix(store): migrate timestamps", "", classify.Classification{Type: "fix", Scope: "store", Conventional: true}},
+		{"feat(api)!: drop v1 endpoints", "", classify.Classification{Type: "feat", Scope: "api", Breaking: true, Conventional: true}},
+		{"refactor: split parser", "BREAKING CHANGE: Parse now returns an error", classify.Classification{Type: "refactor", Breaking: true, Conventional: true}},
+		{"Docs: typo", "", classify.Classification{Type: "docs", Conventional: true}},
+		{"hotfix: null check", "", classify.Classification{Type: "fix", Conventional: true}},
+		{"Revert \"feat: add punchcard\"", "This reverts commit abc123.", classify.Classification{Type: "revert"}},
+		{"Fixes crash when repo is empty", "", classify.Classification{Type: "fix"}},
+		{"Handle bug in date parsing", "", classify.Classification{Type: "fix"}},
+		{"Refactoring the indexer", "", classify.Classification{Type: "refactor"}},
+		{"Merge pull request #12 from feature/x", "", classify.Classification{Type: "merge"}},
+		{"wip: experiments", "", classify.Classification{Type: "other"}},
+		{"Add prefix search", "", classify.Classification{Type: "other"}},
+		{"Update suffix handling", "", classify.Classification{Type: "other"}},
+	}
+	for _, tt := range tests {
+		if got := classify.Classify(tt.subject, tt.body); got != tt.want {
+			t.Errorf("Classify(%q, %q) = %+v, want %+v", tt.subject, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestRevertedCommit(t *testing.T) {
+	tests := []struct {
+		subject, body, want string
+	}{
+		{`Revert "Add login"`, "This reverts commit 0123456789abcdef0123456789abcdef01234567.", "0123456789abcdef0123456789abcdef01234567"},
+		{`Revert "Add login"`, "This reverts commit abc1234, reversing\nchanges made to def5678.", "abc1234"},
+		{"Undo login. This reverts commit abcdef0", "", "abcdef0"},
+		{`Revert "Add login"`, "", ""},
+		{"fix: login", "", ""},
+	}
+	for _, tt := range tests {
+		if got := classify.RevertedCommit(tt.subject, tt.body); got != tt.want {
+			t.Errorf("RevertedCommit(%q, %q) = %q, want %q", tt.subject, tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package complexity measures the size and complexity of source files.
+package complexity
+
+import (
+	"bytes"
+	"strings"
+
+	"git-analytics/internal/gosrc"
+	"git-analytics/internal/language"
+)
+
+// Metrics describes the size and complexity of one file.
+type Metrics struct {
+	Lines   int `json:"lines"`
+	Code    int `json:"code"`    // lines with code, including those with trailing comments
+	Comment int `json:"comment"` // lines holding only comments
+	Blank   int `json:"blank"`
+	// Indent is the sum of the indentation levels of the code lines, a
+	// language-agnostic proxy for nesting complexity. A level is a tab or
+	// the file's indent width in spaces (see indentWidth).
+	Indent    int `json:"indent"`
+	MaxIndent int `json:"max_indent"`
+	// Cyclomatic is the cyclomatic complexity of Go files (see
+	// gosrc.Complexity), or 0 for other files and Go files that do not
+	// parse.
+	Cyclomatic int `json:"cyclomatic"`
+}
+
+// syntax is a language's comment syntax.
+type syntax struct {
+	line       []string // line comment prefixes
+	blockStart string
+	blockEnd   string
+}
+
+var (
+	cStyle    = syntax{line: []string{"//"}, blockStart: "/*", blockEnd: "*/"}
+	hashStyle = syntax{line: []string{"#"}}
+	markup    = syntax{blockStart: "<!--", blockEnd: "-->"}
+)
+
+// byLanguage maps language names, as returned by language.FromPath, to their
+// comment syntax.
+var byLanguage = map[string]syntax{
+	"C":                cStyle,
+	"C++":              cStyle,
+	"C#":               cStyle,
+	"CSS":              {blockStart: "/*", blockEnd: "*/"},
+	"Dart":             cStyle,
+	"Go":               cStyle,
+	"Gradle":           cStyle,
+	"Groovy":           cStyle,
+	"Java":             cStyle,
+	"JavaScript":       cStyle,
+	"Kotlin":           cStyle,
+	"Less":             cStyle,
+	"Objective-C":      cStyle,
+	"Objective-C++":    cStyle,
+	"PHP":              {line: []string{"//", "#"}, blockStart: "/*", blockEnd: "*/"},
+	"Protocol Buffers": cStyle,
+	"Rust":             cStyle,
+	"Sass":             cStyle,
+	"Scala":            cStyle,
+	"SCSS":             cStyle,
+	"Swift":            cStyle,
+	"TypeScript":       cStyle,
+	"Zig":              {line: []string{"//"}},
+	"HCL":              {line: []string{"#", "//"}, blockStart: "/*", blockEnd: "*/"},
+	"CMake":            hashStyle,
+	"Dockerfile":       hashStyle,
+	"Elixir":           hashStyle,
+	"Makefile":         hashStyle,
+	"Perl":             hashStyle,
+	"PowerShell":       {line: []string{"#"}, blockStart: "<#", blockEnd: "#>"},
+	"Python":           hashStyle,
+	"R":                hashStyle,
+	"Ruby":             hashStyle,
+	"Shell":            hashStyle,
+	"TOML":             hashStyle,
+	"YAML":             hashStyle,
+	"Haskell":          {line: []string{"--"}, blockStart: "{-", blockEnd: "-}"},
+	"Lua":              {line: []string{"--"}},
+	"SQL":              {line: []string{"--"}, blockStart: "/*", blockEnd: "*/"},
+	"Erlang":           {line: []string{"%"}},
+	"Clojure":          {line: []string{";"}},
+	"HTML":             markup,
+	"Markdown":         markup,
+	"Svelte":           markup,
+	"Vue":              markup,
+	"XML":              markup,
+}
+
+// Measure returns the metrics of src, the contents of the file at path.
+// Comments are recognized by the comment syntax of the file's language (see
+// language.FromPath); files of unknown languages have no comment lines.
+// Comment markers inside strings are not told apart from real ones. Binary
+// files, judged by a NUL byte near the start, measure as empty.
+func Measure(path string, src []byte) Metrics {
+	var m Metrics
+	if bytes.IndexByte(src[:min(len(src), 8000)], 0) >= 0 {
+		return m
+	}
+
+	lang := language.FromPath(path)
+	syn := byLanguage[lang]
+	inBlock := false
+	var indents []indent
+	for line := range strings.Lines(string(src)) {
+		m.Lines++
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" && !inBlock:
+			m.Blank++
+		case inBlock:
+			m.Comment++
+			if strings.Contains(trimmed, syn.blockEnd) {
+				inBlock = false
+			}
+		case isLineComment(trimmed, syn):
+			m.Comment++
+		case syn.blockStart != "" && strings.HasPrefix(trimmed, syn.blockStart):
+			m.Comment++
+			inBlock = !strings.Contains(trimmed[len(syn.blockStart):], syn.blockEnd)
+		default:
+			m.Code++
+			indents = append(indents, leadingIndent(line))
+		}
+	}
+	width := indentWidth(indents)
+	for _, in := range indents {
+		level := in.tabs + in.spaces/width
+		m.Indent += level
+		m.MaxIndent = max(m.MaxIndent, level)
+	}
+
+	if lang == "Go" {
+		if cc, err := gosrc.Complexity(src); err == nil {
+			m.Cyclomatic = cc
+		}
+	}
+	return m
+}
+
+func isLineComment(trimmed string, syn syntax) bool {
+	for _, prefix := range syn.line {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// indent is the leading whitespace of a line.
+type indent struct{ tabs, spaces int }
+
+// leadingIndent returns the tabs and spaces that line starts with.
+func leadingIndent(line string) indent {
+	var in indent
+	for _, r := range line {
+		switch r {
+		case ' ':
+			in.spaces++
+		case '\t':
+			in.tabs++
+		default:
+			return in
+		}
+	}
+	return in
+}
+
+// indentWidth infers the number of spaces a file indents by from the most
+// common increase in spaces between consecutive code lines, preferring the
+// smaller width on ties. Files that indent with tabs alone use 4.
+func indentWidth(indents []indent) int {
+	counts := make(map[int]int)
+	for i := 1; i < len(indents); i++ {
+		if d := indents[i].spaces - indents[i-1].spaces; d > 0 {
+			counts[d]++
+		}
+	}
+	width, best := 4, 0
+	for d, n := range counts {
+		if n > best || n == best && d < width {
+			width, best = d, n
+		}
+	}
+	return width
+}
@@ -0,0 +1,52 @@
+package complexity_test
+
+import (
+	"testing"
+
+	"git-analytics/internal/complexity"
+)
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		src  string
+		want complexity.Metrics
+	}{
+		{
+			name: "go",
+			path: "main.go",
+			src:  "package main\n\n// main runs.\nfunc main() {\n\tif true {\n\t\tprintln() // hi\n\t}\n\t/* a\n\t   b */\n}\n",
+			want: complexity.Metrics{Lines: 10, Code: 6, Comment: 3, Blank: 1, Indent: 4, MaxIndent: 2, Cyclomatic: 2},
+		},
+		{
+			name: "python",
+			path: "tool.py",
+			src:  "# tool\ndef f():\n    return 1\n\n",
+			want: complexity.Metrics{Lines: 4, Code: 2, Comment: 1, Blank: 1, Indent: 1, MaxIndent: 1},
+		},
+		{
+			name: "unknown language",
+			path: "LICENSE",
+			src:  "# not a comment\n  two spaces\n",
+			want: complexity.Metrics{Lines: 2, Code: 2, Indent: 1, MaxIndent: 1},
+		},
+		{
+			name: "two-space indent",
+			path: "app.js",
+			src:  "function f() {\n  if (x) {\n    y();\n  }\n}\n",
+			want: complexity.Metrics{Lines: 5, Code: 5, Indent: 4, MaxIndent: 2},
+		},
+		{
+			name: "binary",
+			path: "logo.png",
+			src:  "\x89PNG\x00\x01\n",
+			want: complexity.Metrics{},
+		},
+	}
+	for _, tt := range tests {
+		if got := complexity.Measure(tt.path, []byte(tt.src)); got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
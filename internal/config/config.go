@@ -20,9 +20,30 @@ type RecentRepo struct {
 	OpenedAt time.Time `json:"opened_at"`
 }
 
+// PullRequestSource describes where a repository's pull requests are
+// imported from. Access tokens are not persisted.
+type PullRequestSource struct {
+	Provider   string `json:"provider"` // "github" or "gitlab"
+	BaseURL    string `json:"base_url"` // empty for the public service
+	Repository string `json:"repository"`
+}
+
+// RepoSettings holds settings for a single repository.
+type RepoSettings struct {
+	// IssuePatterns are regular expressions matching issue keys in commit
+	// messages. Empty selects the default Jira and GitHub patterns.
+	IssuePatterns []string          `json:"issue_patterns,omitempty"`
+	PullRequests  PullRequestSource `json:"pull_requests"`
+	// ExtractHunks keeps the diff hunks of every commit up to date while
+	// indexing, rather than extracting them when an analysis first needs
+	// them.
+	ExtractHunks bool `json:"extract_hunks,omitempty"`
+}
+
 // AppConfig holds persistent application settings.
 type AppConfig struct {
-	RecentRepos []RecentRepo `json:"recent_repos"`
+	RecentRepos []RecentRepo            `json:"recent_repos"`
+	Repos       map[string]RepoSettings `json:"repos,omitempty"` // keyed by repository path
 }
 
 // DefaultConfigDir returns the platform-specific config directory for the app.
@@ -102,3 +123,17 @@ func (c *AppConfig) RemoveRecent(path string) {
 	}
 	c.RecentRepos = filtered
 }
+
+// Repo returns the settings of the repository at path, or zero settings if
+// none were saved.
+func (c *AppConfig) Repo(path string) RepoSettings {
+	return c.Repos[path]
+}
+
+// SetRepo replaces the settings of the repository at path.
+func (c *AppConfig) SetRepo(path string, settings RepoSettings) {
+	if c.Repos == nil {
+		c.Repos = make(map[string]RepoSettings)
+	}
+	c.Repos[path] = settings
+}
@@ -91,3 +91,30 @@ func TestAddRecent_MRU(t *testing.T) {
 		t.Fatalf("expected /path/b third, got %q", cfg.RecentRepos[2].Path)
 	}
 }
+
+func TestRepoSettings(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &AppConfig{}
+	if got := cfg.Repo("/path/a"); len(got.IssuePatterns) != 0 {
+		t.Fatalf("expected no patterns, got %v", got.IssuePatterns)
+	}
+	cfg.SetRepo("/path/a", RepoSettings{IssuePatterns: []string{`ABC-\d+`}, ExtractHunks: true})
+	if err := cfg.Save(dir); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	got := loaded.Repo("/path/a").IssuePatterns
+	if len(got) != 1 || got[0] != `ABC-\d+` {
+		t.Fatalf("expected [ABC-\\d+], got %v", got)
+	}
+	if !loaded.Repo("/path/a").ExtractHunks {
+		t.Errorf("expected hunk extraction to be enabled")
+	}
+	if loaded.Repo("/path/b").ExtractHunks {
+		t.Errorf("expected hunk extraction to be off by default")
+	}
+}
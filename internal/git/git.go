@@ -1,6 +1,14 @@
 package git
 
-import "time"
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrBlobTooLarge is returned by Repository.ReadBlob for blobs above the
+// size limit.
+var ErrBlobTooLarge = errors.New("blob too large")
 
 // Commit holds the extracted analytics data for a single commit.
 type Commit struct {
@@ -13,11 +21,88 @@ type Commit struct {
 	FilesChanged []FileStat
 }
 
+// Change statuses of a file in a commit, as reported by git --name-status.
+const (
+	StatusAdded    = "A"
+	StatusModified = "M"
+	StatusDeleted  = "D"
+	StatusRenamed  = "R"
+)
+
 // FileStat holds per-file change metrics for a commit.
 type FileStat struct {
 	Path      string
-	Additions int
-	Deletions int
+	OldPath   string // previous path if the file was renamed, otherwise empty
+	Status    string // one of the Status constants
+	Additions int    // always 0 for binary files
+	Deletions int    // always 0 for binary files
+	Binary    bool
+	SizeDelta int64 // change in blob size in bytes; only set for binary files
+	// OldBlob and NewBlob are the hashes of the file's blob before and
+	// after the change, empty when the file did not exist.
+	OldBlob string
+	NewBlob string
+}
+
+// Hunk is a changed line range of a file in a commit, as in the header of a
+// unified diff hunk without context lines: OldLines lines starting at line
+// OldStart were replaced by NewLines lines starting at line NewStart. When
+// no lines were removed (or added), OldStart (or NewStart) is the line after
+// which the change was made.
+type Hunk struct {
+	Path      string // the file's path after the commit
+	OldStart  int
+	OldLines  int
+	NewStart  int
+	NewLines  int
+	Coalesced bool // stands for several hunks merged to cap their number
+}
+
+// CommitHunks holds the hunks of the files a commit changed, in diff order.
+type CommitHunks struct {
+	Hash  string
+	Hunks []Hunk
+}
+
+// HunkLimits bound the work LogHunks does per file and commit. Zero fields
+// mean no limit.
+type HunkLimits struct {
+	// MaxBytes skips diffing files whose blob before or after the commit
+	// is larger, as git does for files above core.bigFileThreshold. Such
+	// files get no hunks, like binary files.
+	MaxBytes int64
+	// MaxHunks replaces the hunks of a file with more of them by a single
+	// coalesced hunk spanning them all.
+	MaxHunks int
+}
+
+// HunkIter yields the hunks of commits one at a time. Callers must call
+// Close when done.
+type HunkIter interface {
+	// Next returns the next commit's hunks, or nil, nil when exhausted.
+	Next() (*CommitHunks, error)
+	Close()
+}
+
+// TreeFile is a file in a commit's tree.
+type TreeFile struct {
+	Path string
+	Blob string // blob hash of the file's contents
+}
+
+// FileRevision is a file's path at a commit.
+type FileRevision struct {
+	Commit string
+	Path   string
+}
+
+// BlameEntry counts the lines of a blamed file last changed by one commit.
+type BlameEntry struct {
+	Commit      string
+	AuthorName  string
+	AuthorEmail string
+	Date        time.Time
+	Lines       int
 }
 
 // CommitIter yields commits one at a time. Callers must call Close when done.
@@ -32,8 +117,33 @@ type Repository interface {
 	// Log returns an iterator over commits in reverse chronological order.
 	// If sinceHash is non-empty, only commits after that hash are returned.
 	Log(sinceHash string) (CommitIter, error)
+	// LogHunks returns an iterator over the hunks of commits in the same
+	// order as Log, diffing each against its parent. Merge commits are
+	// omitted. If sinceHash is non-empty, only commits after that hash are
+	// returned. Files are diffed within limits.
+	LogHunks(sinceHash string, limits HunkLimits) (HunkIter, error)
 	// HeadHash returns the current HEAD commit hash.
 	HeadHash() (string, error)
+	// TreeFiles returns the files in the tree of commit rev. Submodules are
+	// omitted.
+	TreeFiles(rev string) ([]TreeFile, error)
+	// FirstParentBefore returns the most recent commit on HEAD's first-parent
+	// chain authored before t, or "" if there is none. Author dates are used
+	// as for Commit.Date.
+	FirstParentBefore(t time.Time) (string, error)
+	// Blame attributes the lines of path at commit rev to the commits that
+	// last changed them, one entry per commit in order of first appearance.
+	// It is safe for concurrent use, but implementations may run concurrent
+	// calls one at a time.
+	Blame(rev, path string) ([]BlameEntry, error)
+	// ReadBlob returns the contents of the blob with the given hash. If
+	// maxBytes is positive and the blob is larger, it returns
+	// ErrBlobTooLarge without buffering the contents. It is safe for
+	// concurrent use.
+	ReadBlob(hash string, maxBytes int64) ([]byte, error)
+	// BlobAt returns the blob hash of the file at path in the tree of commit
+	// rev, or "" if there is no such file.
+	BlobAt(rev, path string) (string, error)
 	// RepoName returns the base directory name of the repository.
 	RepoName() string
 	// CurrentBranch returns the short name of the current branch (e.g. "main"),
@@ -41,3 +151,101 @@ type Repository interface {
 	CurrentBranch() string
 	Close() error
 }
+
+// SplitRenamePath splits a diffstat path that may describe a rename, either
+// "old => new" or the compact "dir/{old => new}/file" form, into the old and
+// new paths. For paths without a rename, oldPath is empty.
+func SplitRenamePath(name string) (oldPath, newPath string) {
+	before, after, ok := strings.Cut(name, " => ")
+	if !ok {
+		return "", name
+	}
+
+	open := strings.LastIndex(before, "{")
+	closing := strings.Index(after, "}")
+	if open < 0 || closing < 0 {
+		return before, after
+	}
+
+	prefix, suffix := before[:open], after[closing+1:]
+	oldPath = joinRenameParts(prefix, before[open+1:], suffix)
+	newPath = joinRenameParts(prefix, after[:closing], suffix)
+	return oldPath, newPath
+}
+
+// joinRenameParts joins the pieces of a compact rename path. An empty middle
+// (e.g. "{ => sub}") would otherwise leave a doubled separator behind.
+func joinRenameParts(prefix, middle, suffix string) string {
+	if middle == "" {
+		return prefix + strings.TrimPrefix(suffix, "/")
+	}
+	return prefix + middle + suffix
+}
+
+// blameTally groups blamed lines into entries per commit, keeping the order
+// in which commits first appear.
+type blameTally struct {
+	entries []BlameEntry
+	index   map[string]int
+}
+
+// add counts one line last changed by the commit described by e.
+func (t *blameTally) add(e BlameEntry) {
+	if t.index == nil {
+		t.index = make(map[string]int)
+	}
+	i, ok := t.index[e.Commit]
+	if !ok {
+		i = len(t.entries)
+		t.index[e.Commit] = i
+		e.Lines = 0
+		t.entries = append(t.entries, e)
+	}
+	t.entries[i].Lines++
+}
+
+// capHunks replaces the hunks of each file in hunks that has more than max
+// of them with one coalesced hunk spanning from the first to the last. A
+// file's hunks must be contiguous, as in diff order. A max of 0 means no
+// limit.
+func capHunks(hunks []Hunk, max int) []Hunk {
+	if max <= 0 || len(hunks) <= max {
+		return hunks
+	}
+	capped := hunks[:0:0]
+	for start := 0; start < len(hunks); {
+		end := start + 1
+		for end < len(hunks) && hunks[end].Path == hunks[start].Path {
+			end++
+		}
+		if end-start <= max {
+			capped = append(capped, hunks[start:end]...)
+		} else {
+			first, last := hunks[start], hunks[end-1]
+			h := Hunk{Path: first.Path, Coalesced: true}
+			h.OldStart, h.OldLines = spanRange(first.OldStart, first.OldLines, last.OldStart, last.OldLines)
+			h.NewStart, h.NewLines = spanRange(first.NewStart, first.NewLines, last.NewStart, last.NewLines)
+			capped = append(capped, h)
+		}
+		start = end
+	}
+	return capped
+}
+
+// spanRange returns the hunk range covering the ranges of a first and a
+// last hunk, using the hunk header convention that an empty range starts at
+// the line before it.
+func spanRange(firstStart, firstLines, lastStart, lastLines int) (start, lines int) {
+	from := firstStart
+	if firstLines == 0 {
+		from++
+	}
+	to := lastStart + lastLines - 1
+	if lastLines == 0 {
+		to = lastStart
+	}
+	if lines = to - from + 1; lines <= 0 {
+		return from - 1, 0
+	}
+	return from, lines
+}
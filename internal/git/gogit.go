@@ -5,9 +5,13 @@ import (
 	"io"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	gogit "github.com/go-git/go-git/v6"
 	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/filemode"
+	fdiff "github.com/go-git/go-git/v6/plumbing/format/diff"
 	"github.com/go-git/go-git/v6/plumbing/object"
 	"github.com/go-git/go-git/v6/plumbing/storer"
 )
@@ -16,6 +20,11 @@ import (
 type goGitRepo struct {
 	repo *gogit.Repository
 	path string
+
+	// mu serializes every use of repo, including the walks of the iterators
+	// returned by Log and LogHunks, as go-git repositories are not safe for
+	// concurrent use.
+	mu sync.Mutex
 }
 
 // Open opens an existing git repository on disk.
@@ -32,6 +41,9 @@ func (r *goGitRepo) RepoName() string {
 }
 
 func (r *goGitRepo) CurrentBranch() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	ref, err := r.repo.Head()
 	if err != nil {
 		return "HEAD"
@@ -40,6 +52,9 @@ func (r *goGitRepo) CurrentBranch() string {
 }
 
 func (r *goGitRepo) HeadHash() (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	ref, err := r.repo.Head()
 	if err != nil {
 		return "", err
@@ -47,7 +62,142 @@ func (r *goGitRepo) HeadHash() (string, error) {
 	return ref.Hash().String(), nil
 }
 
+func (r *goGitRepo) TreeFiles(rev string) ([]TreeFile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hash, err := r.repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, err
+	}
+	commit, err := r.repo.CommitObject(*hash)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []TreeFile
+	err = tree.Files().ForEach(func(f *object.File) error {
+		files = append(files, TreeFile{Path: f.Name, Blob: f.Hash.String()})
+		return nil
+	})
+	return files, err
+}
+
+func (r *goGitRepo) FirstParentBefore(t time.Time) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ref, err := r.repo.Head()
+	if err != nil {
+		return "", err
+	}
+	commit, err := r.repo.CommitObject(ref.Hash())
+	if err != nil {
+		return "", err
+	}
+	for !commit.Author.When.Before(t) {
+		if commit.NumParents() == 0 {
+			return "", nil
+		}
+		if commit, err = commit.Parent(0); err != nil {
+			return "", err
+		}
+	}
+	return commit.Hash.String(), nil
+}
+
+// Blame runs one blame at a time on purpose: go-git repositories are not
+// safe for concurrent use, so concurrent callers wait for each other. The
+// native backend blames in parallel.
+func (r *goGitRepo) Blame(rev, path string) ([]BlameEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hash, err := r.repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, err
+	}
+	commit, err := r.repo.CommitObject(*hash)
+	if err != nil {
+		return nil, err
+	}
+	result, err := gogit.Blame(commit, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tally blameTally
+	for _, line := range result.Lines {
+		tally.add(BlameEntry{
+			Commit:      line.Hash.String(),
+			AuthorName:  line.AuthorName,
+			AuthorEmail: line.Author,
+			Date:        line.Date,
+		})
+	}
+	return tally.entries, nil
+}
+
+func (r *goGitRepo) ReadBlob(hash string, maxBytes int64) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, ok := plumbing.FromHex(hash)
+	if !ok {
+		return nil, &InvalidHashError{Hash: hash}
+	}
+	blob, err := r.repo.BlobObject(h)
+	if err != nil {
+		return nil, err
+	}
+	if maxBytes > 0 && blob.Size > maxBytes {
+		return nil, ErrBlobTooLarge
+	}
+	reader, err := blob.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func (r *goGitRepo) BlobAt(rev, path string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hash, err := r.repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return "", err
+	}
+	commit, err := r.repo.CommitObject(*hash)
+	if err != nil {
+		return "", err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return "", err
+	}
+	entry, err := tree.FindEntry(path)
+	if errors.Is(err, object.ErrEntryNotFound) || errors.Is(err, object.ErrDirectoryNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !entry.Mode.IsFile() {
+		return "", nil
+	}
+	return entry.Hash.String(), nil
+}
+
 func (r *goGitRepo) Log(sinceHash string) (CommitIter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	opts := &gogit.LogOptions{
 		Order: gogit.LogOrderCommitterTime,
 	}
@@ -68,9 +218,19 @@ func (r *goGitRepo) Log(sinceHash string) (CommitIter, error) {
 	return &goGitCommitIter{
 		iter:      iter,
 		sinceHash: sinceHash,
+		objects:   r.repo.Storer,
+		mu:        &r.mu,
 	}, nil
 }
 
+func (r *goGitRepo) LogHunks(sinceHash string, limits HunkLimits) (HunkIter, error) {
+	commits, err := r.Log(sinceHash)
+	if err != nil {
+		return nil, err
+	}
+	return &goGitHunkIter{commits: commits.(*goGitCommitIter), limits: limits}, nil
+}
+
 func (r *goGitRepo) Close() error {
 	return nil
 }
@@ -88,56 +248,305 @@ func (e *InvalidHashError) Error() string {
 type goGitCommitIter struct {
 	iter      object.CommitIter
 	sinceHash string
+	objects   storer.EncodedObjectStorer
+	mu        *sync.Mutex // the repository's
 }
 
 func (it *goGitCommitIter) Next() (*Commit, error) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	c, err := it.nextCommit()
+	if c == nil || err != nil {
+		return nil, err
+	}
+
+	files, err := commitFileStats(it.objects, c)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, body, _ := strings.Cut(c.Message, "\n\n")
+	subject = strings.TrimRight(subject, "\n")
+	description := strings.TrimSpace(body)
+
+	return &Commit{
+		Hash:         c.Hash.String(),
+		AuthorName:   c.Author.Name,
+		AuthorEmail:  c.Author.Email,
+		Date:         c.Author.When,
+		Message:      subject,
+		Description:  description,
+		FilesChanged: files,
+	}, nil
+}
+
+// nextCommit returns the next commit of the walk, or nil, nil when
+// exhausted.
+func (it *goGitCommitIter) nextCommit() (*object.Commit, error) {
+	c, err := it.iter.Next()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		// storer.ErrStop is returned when the To/TailHash commit is
+		// reached. That commit is the one we already indexed, so skip it.
+		if errors.Is(err, storer.ErrStop) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func (it *goGitCommitIter) Close() {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	it.iter.Close()
+}
+
+// goGitHunkIter implements HunkIter on top of a goGitCommitIter's walk.
+type goGitHunkIter struct {
+	commits *goGitCommitIter
+	limits  HunkLimits
+}
+
+func (it *goGitHunkIter) Next() (*CommitHunks, error) {
+	it.commits.mu.Lock()
+	defer it.commits.mu.Unlock()
+
 	for {
-		c, err := it.iter.Next()
+		c, err := it.commits.nextCommit()
+		if c == nil || err != nil {
+			return nil, err
+		}
+		// Like git log --patch, merge commits have no hunks.
+		if c.NumParents() > 1 {
+			continue
+		}
+		changes, err := firstParentChanges(c)
+		if err != nil {
+			return nil, err
+		}
+		if changes, err = it.withinMaxBytes(changes); err != nil {
+			return nil, err
+		}
+		patch, err := changes.Patch()
 		if err != nil {
-			if err == io.EOF {
-				return nil, nil
+			return nil, err
+		}
+		ch := &CommitHunks{Hash: c.Hash.String()}
+		for _, fp := range patch.FilePatches() {
+			ch.Hunks = append(ch.Hunks, filePatchHunks(fp)...)
+		}
+		ch.Hunks = capHunks(ch.Hunks, it.limits.MaxHunks)
+		return ch, nil
+	}
+}
+
+func (it *goGitHunkIter) Close() {
+	it.commits.Close()
+}
+
+// withinMaxBytes drops the changes whose blob before or after is larger than
+// the byte limit, so that they are never diffed.
+func (it *goGitHunkIter) withinMaxBytes(changes object.Changes) (object.Changes, error) {
+	if it.limits.MaxBytes <= 0 {
+		return changes, nil
+	}
+	kept := changes[:0:0]
+	for _, change := range changes {
+		small := true
+		for _, entry := range []object.ChangeEntry{change.From, change.To} {
+			if entry.TreeEntry.Hash.IsZero() || entry.TreeEntry.Mode == filemode.Submodule {
+				continue
 			}
-			// storer.ErrStop is returned when the To/TailHash commit is
-			// reached. That commit is the one we already indexed, so skip it.
-			if errors.Is(err, storer.ErrStop) {
-				return nil, nil
+			obj, err := it.commits.objects.EncodedObject(plumbing.BlobObject, entry.TreeEntry.Hash)
+			if err != nil {
+				return nil, err
+			}
+			if obj.Size() > it.limits.MaxBytes {
+				small = false
 			}
-			return nil, err
 		}
-		if c == nil {
-			return nil, nil
+		if small {
+			kept = append(kept, change)
+		}
+	}
+	return kept, nil
+}
+
+// commitFileStats diffs c against its first parent, like Commit.Stats, and
+// also records each file's change status and, for binary files, which
+// Commit.Stats omits, the change in blob size.
+func commitFileStats(objects storer.EncodedObjectStorer, c *object.Commit) ([]FileStat, error) {
+	patch, err := firstParentPatch(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []FileStat
+	for _, fp := range patch.FilePatches() {
+		var f FileStat
+		from, to := fp.Files()
+		switch {
+		case from == nil:
+			f.Path, f.Status = to.Path(), StatusAdded
+		case to == nil:
+			f.Path, f.Status = from.Path(), StatusDeleted
+		case from.Path() != to.Path():
+			f.Path, f.OldPath, f.Status = to.Path(), from.Path(), StatusRenamed
+		default:
+			f.Path, f.Status = to.Path(), StatusModified
+		}
+		if from != nil {
+			f.OldBlob = from.Hash().String()
+		}
+		if to != nil {
+			f.NewBlob = to.Hash().String()
+		}
+
+		// Binary files, empty files, pure renames and submodule updates
+		// have no chunks.
+		if len(fp.Chunks()) == 0 {
+			if isSubmodule(from) || isSubmodule(to) {
+				continue
+			}
+			newSize, newBinary, err := blobInfo(objects, to)
+			if err != nil {
+				return nil, err
+			}
+			oldSize, oldBinary, err := blobInfo(objects, from)
+			if err != nil {
+				return nil, err
+			}
+			if newBinary || oldBinary {
+				f.Binary = true
+				f.SizeDelta = newSize - oldSize
+			}
+		}
+
+		for _, chunk := range fp.Chunks() {
+			switch chunk.Type() {
+			case fdiff.Add:
+				f.Additions += countLines(chunk.Content())
+			case fdiff.Delete:
+				f.Deletions += countLines(chunk.Content())
+			}
 		}
+		files = append(files, f)
+	}
+	return files, nil
+}
 
-		stats, err := c.Stats()
+// firstParentPatch diffs c against its first parent, or against the empty
+// tree for a root commit.
+func firstParentPatch(c *object.Commit) (*object.Patch, error) {
+	changes, err := firstParentChanges(c)
+	if err != nil {
+		return nil, err
+	}
+	return changes.Patch()
+}
+
+// firstParentChanges lists the files c changed relative to its first
+// parent, or to the empty tree for a root commit, without diffing them.
+func firstParentChanges(c *object.Commit) (object.Changes, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+	parentTree := &object.Tree{}
+	if c.NumParents() != 0 {
+		parent, err := c.Parents().Next()
 		if err != nil {
 			return nil, err
 		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	return parentTree.Diff(tree)
+}
 
-		files := make([]FileStat, len(stats))
-		for i, s := range stats {
-			files[i] = FileStat{
-				Path:      s.Name,
-				Additions: s.Addition,
-				Deletions: s.Deletion,
-			}
+// filePatchHunks returns the hunks of fp as a diff without context lines
+// would report them: each run of deleted and added chunks between unchanged
+// ones is one hunk.
+func filePatchHunks(fp fdiff.FilePatch) []Hunk {
+	from, to := fp.Files()
+	path := ""
+	if to != nil {
+		path = to.Path()
+	} else if from != nil {
+		path = from.Path()
+	}
+
+	var hunks []Hunk
+	var cur *Hunk
+	oldLine, newLine := 0, 0 // lines before the current position
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		if cur.OldLines > 0 {
+			cur.OldStart++
+		}
+		if cur.NewLines > 0 {
+			cur.NewStart++
+		}
+		hunks = append(hunks, *cur)
+		cur = nil
+	}
+	for _, chunk := range fp.Chunks() {
+		n := countLines(chunk.Content())
+		if chunk.Type() == fdiff.Equal {
+			flush()
+			oldLine += n
+			newLine += n
+			continue
+		}
+		if cur == nil {
+			cur = &Hunk{Path: path, OldStart: oldLine, NewStart: newLine}
+		}
+		switch chunk.Type() {
+		case fdiff.Delete:
+			cur.OldLines += n
+			oldLine += n
+		case fdiff.Add:
+			cur.NewLines += n
+			newLine += n
 		}
+	}
+	flush()
+	return hunks
+}
 
-		subject, body, _ := strings.Cut(c.Message, "\n\n")
-		subject = strings.TrimRight(subject, "\n")
-		description := strings.TrimSpace(body)
+// isSubmodule reports whether f is a submodule entry.
+func isSubmodule(f fdiff.File) bool {
+	return f != nil && f.Mode() == filemode.Submodule
+}
 
-		return &Commit{
-			Hash:         c.Hash.String(),
-			AuthorName:   c.Author.Name,
-			AuthorEmail:  c.Author.Email,
-			Date:         c.Author.When,
-			Message:      subject,
-			Description:  description,
-			FilesChanged: files,
-		}, nil
+// blobInfo returns the size of f's blob and whether its content is binary.
+// A nil f has size 0.
+func blobInfo(objects storer.EncodedObjectStorer, f fdiff.File) (size int64, binary bool, err error) {
+	if f == nil {
+		return 0, false, nil
 	}
+	blob, err := object.GetBlob(objects, f.Hash())
+	if err != nil {
+		return 0, false, err
+	}
+	binary, err = (&object.File{Blob: *blob}).IsBinary()
+	return blob.Size, binary, err
 }
 
-func (it *goGitCommitIter) Close() {
-	it.iter.Close()
+// countLines returns the number of lines in s, counting a final line without
+// a trailing newline.
+func countLines(s string) int {
+	n := strings.Count(s, "\n")
+	if s != "" && !strings.HasSuffix(s, "\n") {
+		n++
+	}
+	return n
 }
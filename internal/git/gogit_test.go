@@ -1,6 +1,9 @@
 package git_test
 
 import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -305,3 +308,687 @@ func initTestRepo(t *testing.T) string {
 
 	return dir
 }
+
+// initTestRepoWithRename creates a temporary git repository with 2 commits,
+// the second of which renames src/old.go to src/new.go with a small edit.
+func initTestRepoWithRename(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test User",
+			"GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test User",
+			"GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("command %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	run("git", "init")
+	run("git", "config", "user.name", "Test User")
+	run("git", "config", "user.email", "test@example.com")
+
+	content := "package src\n\nfunc A() {}\n\nfunc B() {}\n\nfunc C() {}\n"
+	if err := os.MkdirAll(filepath.Join(dir, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "src", "old.go"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("git", "add", ".")
+	run("git", "commit", "-m", "add old.go")
+
+	time.Sleep(time.Second)
+
+	run("git", "mv", "src/old.go", "src/new.go")
+	if err := os.WriteFile(filepath.Join(dir, "src", "new.go"), []byte(content+"\nfunc D() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("git", "add", ".")
+	run("git", "commit", "-m", "rename old.go to new.go")
+
+	return dir
+}
+
+func TestGoGitRename(t *testing.T) {
+	repoPath := initTestRepoWithRename(t)
+
+	repo, err := git.Open(repoPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer repo.Close()
+
+	iter, err := repo.Log("")
+	if err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	defer iter.Close()
+
+	renamed, err := iter.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if len(renamed.FilesChanged) != 1 {
+		t.Fatalf("expected 1 file changed, got %d: %+v", len(renamed.FilesChanged), renamed.FilesChanged)
+	}
+	f := renamed.FilesChanged[0]
+	if f.Path != "src/new.go" || f.OldPath != "src/old.go" {
+		t.Errorf("expected src/old.go => src/new.go, got %q => %q", f.OldPath, f.Path)
+	}
+	if f.Status != git.StatusRenamed {
+		t.Errorf("expected status R, got %q", f.Status)
+	}
+}
+
+func TestGoGitTreeFiles(t *testing.T) {
+	repoPath := initTestRepoWithRename(t)
+
+	repo, err := git.Open(repoPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer repo.Close()
+
+	files, err := repo.TreeFiles("HEAD")
+	if err != nil {
+		t.Fatalf("TreeFiles: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "src/new.go" || len(files[0].Blob) != 40 {
+		t.Errorf("expected only src/new.go with a blob hash, got %+v", files)
+	}
+}
+
+// initTestRepoWithLifecycle creates a temporary git repository with 2
+// commits: the first adds a.txt and b.txt, the second modifies a.txt,
+// deletes b.txt and adds c.txt.
+func initTestRepoWithLifecycle(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test User",
+			"GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test User",
+			"GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("command %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("git", "init")
+	run("git", "config", "user.name", "Test User")
+	run("git", "config", "user.email", "test@example.com")
+
+	write("a.txt", "a\n")
+	write("b.txt", "b\nb\n")
+	run("git", "add", ".")
+	run("git", "commit", "-m", "add a and b")
+
+	time.Sleep(time.Second)
+
+	write("a.txt", "a\na\n")
+	write("c.txt", "something else entirely\n")
+	run("git", "rm", "-q", "b.txt")
+	run("git", "add", ".")
+	run("git", "commit", "-m", "modify a, delete b, add c")
+
+	return dir
+}
+
+// checkLifecycleStatuses checks the file statuses of the commits created by
+// initTestRepoWithLifecycle, newest first.
+func checkLifecycleStatuses(t *testing.T, repo git.Repository) {
+	t.Helper()
+
+	iter, err := repo.Log("")
+	if err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	defer iter.Close()
+
+	want := []map[string]string{
+		{"a.txt": git.StatusModified, "b.txt": git.StatusDeleted, "c.txt": git.StatusAdded},
+		{"a.txt": git.StatusAdded, "b.txt": git.StatusAdded},
+	}
+	wantBlobs := []map[string][2]string{
+		{
+			"a.txt": {blobHash("a\n"), blobHash("a\na\n")},
+			"b.txt": {blobHash("b\nb\n"), ""},
+			"c.txt": {"", blobHash("something else entirely\n")},
+		},
+		{"a.txt": {"", blobHash("a\n")}, "b.txt": {"", blobHash("b\nb\n")}},
+	}
+	for i, w := range want {
+		c, err := iter.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		got := make(map[string]string)
+		for _, f := range c.FilesChanged {
+			got[f.Path] = f.Status
+			if blobs := [2]string{f.OldBlob, f.NewBlob}; blobs != wantBlobs[i][f.Path] {
+				t.Errorf("commit %d: %s blobs %v, want %v", i, f.Path, blobs, wantBlobs[i][f.Path])
+			}
+		}
+		if len(got) != len(w) {
+			t.Errorf("commit %d: got %v, want %v", i, got, w)
+			continue
+		}
+		for path, status := range w {
+			if got[path] != status {
+				t.Errorf("commit %d: %s status %q, want %q", i, path, got[path], status)
+			}
+		}
+	}
+}
+
+// blobHash returns the git blob hash of content.
+func blobHash(content string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(fmt.Sprintf("blob %d\x00%s", len(content), content))))
+}
+
+func TestGoGitStatus(t *testing.T) {
+	repo, err := git.Open(initTestRepoWithLifecycle(t))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer repo.Close()
+
+	checkLifecycleStatuses(t, repo)
+}
+
+// initTestRepoWithBinary creates a temporary git repository with 2 commits:
+// the first adds a.txt and a 100-byte binary image.bin, the second grows
+// image.bin to 250 bytes.
+func initTestRepoWithBinary(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test User",
+			"GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test User",
+			"GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("command %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name string, content []byte) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("git", "init")
+	run("git", "config", "user.name", "Test User")
+	run("git", "config", "user.email", "test@example.com")
+
+	write("a.txt", []byte("a\n"))
+	write("image.bin", make([]byte, 100))
+	run("git", "add", ".")
+	run("git", "commit", "-m", "add a and image")
+
+	time.Sleep(time.Second)
+
+	write("image.bin", make([]byte, 250))
+	run("git", "add", ".")
+	run("git", "commit", "-m", "grow image")
+
+	return dir
+}
+
+// checkBinaryStats checks the file stats of the commits created by
+// initTestRepoWithBinary, newest first.
+func checkBinaryStats(t *testing.T, repo git.Repository) {
+	t.Helper()
+
+	iter, err := repo.Log("")
+	if err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	defer iter.Close()
+
+	want := []map[string]git.FileStat{
+		{"image.bin": {Binary: true, SizeDelta: 150}},
+		{"a.txt": {Additions: 1}, "image.bin": {Binary: true, SizeDelta: 100}},
+	}
+	for i, w := range want {
+		c, err := iter.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if len(c.FilesChanged) != len(w) {
+			t.Errorf("commit %d: got %+v, want %d files", i, c.FilesChanged, len(w))
+			continue
+		}
+		for _, f := range c.FilesChanged {
+			exp := w[f.Path]
+			if f.Binary != exp.Binary || f.SizeDelta != exp.SizeDelta || f.Additions != exp.Additions {
+				t.Errorf("commit %d: got %+v, want %+v", i, f, exp)
+			}
+		}
+	}
+}
+
+func TestGoGitBinary(t *testing.T) {
+	repo, err := git.Open(initTestRepoWithBinary(t))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer repo.Close()
+
+	checkBinaryStats(t, repo)
+}
+
+// checkBlame checks the blame of a.txt at HEAD in the repository created by
+// initTestRepoWithLifecycle: its first line comes from the first commit, its
+// second from HEAD.
+func checkBlame(t *testing.T, repo git.Repository) {
+	t.Helper()
+
+	head, err := repo.HeadHash()
+	if err != nil {
+		t.Fatalf("HeadHash: %v", err)
+	}
+	entries, err := repo.Blame(head, "a.txt")
+	if err != nil {
+		t.Fatalf("Blame: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if entries[0].Commit == head || entries[1].Commit != head {
+		t.Errorf("expected the first line from the first commit and the second from HEAD, got %+v", entries)
+	}
+	for _, e := range entries {
+		if e.Lines != 1 || e.AuthorEmail != "test@example.com" || e.AuthorName != "Test User" || e.Date.IsZero() {
+			t.Errorf("unexpected entry %+v", e)
+		}
+	}
+}
+
+func TestGoGitBlame(t *testing.T) {
+	repo, err := git.Open(initTestRepoWithLifecycle(t))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer repo.Close()
+
+	checkBlame(t, repo)
+}
+
+// checkFirstParentBefore checks FirstParentBefore on the repository created
+// by initTestRepoWithLifecycle, whose commits are a second apart.
+func checkFirstParentBefore(t *testing.T, repo git.Repository) {
+	t.Helper()
+
+	head, err := repo.HeadHash()
+	if err != nil {
+		t.Fatalf("HeadHash: %v", err)
+	}
+	got, err := repo.FirstParentBefore(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("FirstParentBefore: %v", err)
+	}
+	if got != head {
+		t.Errorf("expected HEAD %s, got %q", head, got)
+	}
+
+	got, err = repo.FirstParentBefore(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("FirstParentBefore: %v", err)
+	}
+	if got != "" {
+		t.Errorf("expected no commit before the repository existed, got %q", got)
+	}
+}
+
+func TestGoGitFirstParentBefore(t *testing.T) {
+	repo, err := git.Open(initTestRepoWithLifecycle(t))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer repo.Close()
+
+	checkFirstParentBefore(t, repo)
+}
+
+// initTestRepoWithBackdatedCommit creates a temporary git repository with a
+// single commit authored on 2020-01-15 but committed now, as after a rebase.
+func initTestRepoWithBackdatedCommit(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test User",
+			"GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test User",
+			"GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_AUTHOR_DATE=2020-01-15T12:00:00Z",
+		)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("command %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	run("git", "init")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("git", "add", "a.txt")
+	run("git", "commit", "-m", "backdated")
+
+	return dir
+}
+
+// checkFirstParentBeforeAuthorDate checks that FirstParentBefore compares
+// author dates, which commits are stored by, rather than committer dates.
+func checkFirstParentBeforeAuthorDate(t *testing.T, repo git.Repository) {
+	t.Helper()
+
+	head, err := repo.HeadHash()
+	if err != nil {
+		t.Fatalf("HeadHash: %v", err)
+	}
+	got, err := repo.FirstParentBefore(time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("FirstParentBefore: %v", err)
+	}
+	if got != head {
+		t.Errorf("expected HEAD %s authored before February 2020, got %q", head, got)
+	}
+	got, err = repo.FirstParentBefore(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("FirstParentBefore: %v", err)
+	}
+	if got != "" {
+		t.Errorf("expected no commit authored before 2020, got %q", got)
+	}
+}
+
+func TestGoGitFirstParentBeforeAuthorDate(t *testing.T) {
+	repo, err := git.Open(initTestRepoWithBackdatedCommit(t))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer repo.Close()
+
+	checkFirstParentBeforeAuthorDate(t, repo)
+}
+
+// initTestRepoWithHunks creates a temporary git repository with 2 commits:
+// the first adds a.txt with lines 1 to 5 and "my file.txt", the second
+// rewrites line 2 of a.txt, deletes line 4, appends a line that looks like
+// a patch header and deletes "my file.txt".
+func initTestRepoWithHunks(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test User",
+			"GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test User",
+			"GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("command %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("git", "init")
+	run("git", "config", "user.name", "Test User")
+	run("git", "config", "user.email", "test@example.com")
+
+	write("a.txt", "1\n2\n3\n4\n5\n")
+	write("my file.txt", "x\n")
+	run("git", "add", ".")
+	run("git", "commit", "-m", "add a and my file")
+
+	time.Sleep(time.Second)
+
+	write("a.txt", "1\nTWO\n3\n5\n-- six\n")
+	run("git", "rm", "-q", "my file.txt")
+	run("git", "add", ".")
+	run("git", "commit", "-m", "rewrite a, delete my file")
+
+	return dir
+}
+
+// checkHunks checks the hunks of the commits created by
+// initTestRepoWithHunks, newest first.
+func checkHunks(t *testing.T, repo git.Repository) {
+	t.Helper()
+
+	iter, err := repo.LogHunks("", git.HunkLimits{})
+	if err != nil {
+		t.Fatalf("LogHunks: %v", err)
+	}
+	defer iter.Close()
+
+	want := [][]git.Hunk{
+		{
+			{Path: "a.txt", OldStart: 2, OldLines: 1, NewStart: 2, NewLines: 1},
+			{Path: "a.txt", OldStart: 4, OldLines: 1, NewStart: 3, NewLines: 0},
+			{Path: "a.txt", OldStart: 5, OldLines: 0, NewStart: 5, NewLines: 1},
+			{Path: "my file.txt", OldStart: 1, OldLines: 1, NewStart: 0, NewLines: 0},
+		},
+		{
+			{Path: "a.txt", OldStart: 0, OldLines: 0, NewStart: 1, NewLines: 5},
+			{Path: "my file.txt", OldStart: 0, OldLines: 0, NewStart: 1, NewLines: 1},
+		},
+	}
+	for i, w := range want {
+		c, err := iter.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if c == nil {
+			t.Fatalf("commit %d: iterator exhausted", i)
+		}
+		if len(c.Hash) != 40 {
+			t.Errorf("commit %d: unexpected hash %q", i, c.Hash)
+		}
+		if len(c.Hunks) != len(w) {
+			t.Errorf("commit %d: got %+v, want %+v", i, c.Hunks, w)
+			continue
+		}
+		for j := range w {
+			if c.Hunks[j] != w[j] {
+				t.Errorf("commit %d hunk %d: got %+v, want %+v", i, j, c.Hunks[j], w[j])
+			}
+		}
+	}
+	if c, err := iter.Next(); c != nil || err != nil {
+		t.Errorf("expected end of hunks, got %+v, %v", c, err)
+	}
+
+	// Capped at 2 hunks per file, a.txt's 3 hunks become one spanning lines
+	// 2 to 5.
+	capped, err := repo.LogHunks("", git.HunkLimits{MaxHunks: 2})
+	if err != nil {
+		t.Fatalf("LogHunks: %v", err)
+	}
+	defer capped.Close()
+	c, err := capped.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	wantCapped := []git.Hunk{
+		{Path: "a.txt", OldStart: 2, OldLines: 4, NewStart: 2, NewLines: 4, Coalesced: true},
+		{Path: "my file.txt", OldStart: 1, OldLines: 1, NewStart: 0, NewLines: 0},
+	}
+	if len(c.Hunks) != len(wantCapped) || c.Hunks[0] != wantCapped[0] || c.Hunks[1] != wantCapped[1] {
+		t.Errorf("got %+v, want %+v", c.Hunks, wantCapped)
+	}
+
+	// Limited to 5 bytes, a.txt is not diffed at all.
+	small, err := repo.LogHunks("", git.HunkLimits{MaxBytes: 5})
+	if err != nil {
+		t.Fatalf("LogHunks: %v", err)
+	}
+	defer small.Close()
+	c, err = small.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	wantSmall := git.Hunk{Path: "my file.txt", OldStart: 1, OldLines: 1, NewStart: 0, NewLines: 0}
+	if len(c.Hunks) != 1 || c.Hunks[0] != wantSmall {
+		t.Errorf("got %+v, want %+v", c.Hunks, wantSmall)
+	}
+}
+
+func TestGoGitLogHunks(t *testing.T) {
+	repo, err := git.Open(initTestRepoWithHunks(t))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer repo.Close()
+
+	checkHunks(t, repo)
+}
+
+// checkReadBlob reads a.txt at HEAD in the repository created by
+// initTestRepoWithLifecycle.
+func checkReadBlob(t *testing.T, repo git.Repository) {
+	t.Helper()
+
+	head, err := repo.HeadHash()
+	if err != nil {
+		t.Fatalf("HeadHash: %v", err)
+	}
+	files, err := repo.TreeFiles(head)
+	if err != nil {
+		t.Fatalf("TreeFiles: %v", err)
+	}
+	for _, f := range files {
+		if f.Path != "a.txt" {
+			continue
+		}
+		// Read twice to check that the reader is left at the next object.
+		for range 2 {
+			data, err := repo.ReadBlob(f.Blob, 0)
+			if err != nil {
+				t.Fatalf("ReadBlob: %v", err)
+			}
+			if string(data) != "a\na\n" {
+				t.Errorf("got %q, want %q", data, "a\na\n")
+			}
+			// A blob over the limit is skipped, again leaving the reader
+			// at the next object.
+			if _, err := repo.ReadBlob(f.Blob, 3); !errors.Is(err, git.ErrBlobTooLarge) {
+				t.Errorf("got %v, want ErrBlobTooLarge", err)
+			}
+		}
+	}
+	if _, err := repo.ReadBlob("0000000000000000000000000000000000000001", 0); err == nil {
+		t.Errorf("expected an error reading a missing blob")
+	}
+}
+
+// checkBlobAt looks up files at both commits created by
+// initTestRepoWithLifecycle.
+func checkBlobAt(t *testing.T, repo git.Repository) {
+	t.Helper()
+
+	head, err := repo.HeadHash()
+	if err != nil {
+		t.Fatalf("HeadHash: %v", err)
+	}
+	files, err := repo.TreeFiles(head)
+	if err != nil {
+		t.Fatalf("TreeFiles: %v", err)
+	}
+	for _, f := range files {
+		blob, err := repo.BlobAt(head, f.Path)
+		if err != nil {
+			t.Fatalf("BlobAt: %v", err)
+		}
+		if blob != f.Blob {
+			t.Errorf("%s: got blob %s, want %s", f.Path, blob, f.Blob)
+		}
+	}
+
+	// b.txt was deleted by HEAD but exists in its parent.
+	if blob, err := repo.BlobAt(head, "b.txt"); err != nil || blob != "" {
+		t.Errorf("b.txt at HEAD: got %q, %v, want no blob", blob, err)
+	}
+	blob, err := repo.BlobAt(head+"~1", "b.txt")
+	if err != nil {
+		t.Fatalf("BlobAt: %v", err)
+	}
+	data, err := repo.ReadBlob(blob, 0)
+	if err != nil {
+		t.Fatalf("ReadBlob: %v", err)
+	}
+	if string(data) != "b\nb\n" {
+		t.Errorf("b.txt at HEAD~1: got %q, want %q", data, "b\nb\n")
+	}
+}
+
+func TestGoGitBlobAt(t *testing.T) {
+	repo, err := git.Open(initTestRepoWithLifecycle(t))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer repo.Close()
+
+	checkBlobAt(t, repo)
+}
+
+func TestGoGitReadBlob(t *testing.T) {
+	repo, err := git.Open(initTestRepoWithLifecycle(t))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer repo.Close()
+
+	checkReadBlob(t, repo)
+}
@@ -3,10 +3,12 @@ package git
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,6 +17,13 @@ import (
 // native git has optimized packfile handling and memory-mapped I/O.
 type nativeRepo struct {
 	path string
+
+	// Blobs are read with a git cat-file --batch process, started on first
+	// use and shared by concurrent readers.
+	blobMu  sync.Mutex
+	blobCmd *exec.Cmd
+	blobIn  io.WriteCloser
+	blobOut *bufio.Reader
 }
 
 // NativeOpen opens an existing git repository using the native git CLI.
@@ -51,11 +60,159 @@ func (r *nativeRepo) HeadHash() (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+func (r *nativeRepo) TreeFiles(rev string) ([]TreeFile, error) {
+	cmd := exec.Command("git", "-C", r.path, "ls-tree", "-r", "-z", rev)
+	hideWindow(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ls-tree %s: %w", rev, err)
+	}
+
+	var files []TreeFile
+	for _, entry := range strings.Split(string(out), "\x00") {
+		// Format: "<mode> <type> <object>\t<path>"
+		meta, path, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) != 3 || fields[1] != "blob" {
+			continue
+		}
+		files = append(files, TreeFile{Path: path, Blob: fields[2]})
+	}
+	return files, nil
+}
+
+func (r *nativeRepo) BlobAt(rev, path string) (string, error) {
+	cmd := exec.Command("git", "-C", r.path, "ls-tree", "-z", rev, "--", path)
+	hideWindow(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("ls-tree %s: %w", rev, err)
+	}
+	// Format: "<mode> <type> <object>\t<path>", or nothing if path is
+	// absent.
+	meta, name, ok := strings.Cut(strings.TrimSuffix(string(out), "\x00"), "\t")
+	if !ok || name != path {
+		return "", nil
+	}
+	fields := strings.Fields(meta)
+	if len(fields) != 3 || fields[1] != "blob" {
+		return "", nil
+	}
+	return fields[2], nil
+}
+
+func (r *nativeRepo) FirstParentBefore(t time.Time) (string, error) {
+	// rev-list --before compares committer dates, while commits are stored
+	// by author date, so the chain is walked here instead.
+	cmd := exec.Command("git", "-C", r.path, "log", "--first-parent", "--format=%H %at", "HEAD")
+	hideWindow(cmd)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", fmt.Errorf("creating stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("starting git log: %w", err)
+	}
+
+	var found string
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		hash, at, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		unix, err := strconv.ParseInt(at, 10, 64)
+		if err != nil {
+			continue
+		}
+		if time.Unix(unix, 0).Before(t) {
+			found = hash
+			break
+		}
+	}
+	if found != "" {
+		// The rest of the history is not needed.
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return found, nil
+	}
+	if err := scanner.Err(); err != nil {
+		_ = cmd.Wait()
+		return "", fmt.Errorf("reading git log: %w", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		return "", fmt.Errorf("git log: %w", err)
+	}
+	return "", nil
+}
+
+func (r *nativeRepo) Blame(rev, path string) ([]BlameEntry, error) {
+	cmd := exec.Command("git", "-C", r.path, "blame", "--line-porcelain", rev, "--", path)
+	hideWindow(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("blame %s: %w", path, err)
+	}
+
+	// Each line is described by a header "<hash> <orig-line> <final-line>",
+	// "key value" lines and finally the line's content prefixed by a tab.
+	var tally blameTally
+	var current BlameEntry
+	var authorTime int64
+	var authorTZ string
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "\t") {
+			date, err := blameDate(authorTime, authorTZ)
+			if err != nil {
+				return nil, err
+			}
+			current.Date = date
+			tally.add(current)
+			current = BlameEntry{}
+			continue
+		}
+		key, value, _ := strings.Cut(line, " ")
+		switch {
+		case current.Commit == "" && len(key) >= 40: // SHA-1 or SHA-256
+			current.Commit = key
+		case key == "author":
+			current.AuthorName = value
+		case key == "author-mail":
+			current.AuthorEmail = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+		case key == "author-time":
+			if authorTime, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return nil, fmt.Errorf("parsing author-time %q: %w", value, err)
+			}
+		case key == "author-tz":
+			authorTZ = value
+		}
+	}
+	return tally.entries, nil
+}
+
+// blameDate converts a blame author-time and author-tz ("+0930") into a time
+// in the author's zone.
+func blameDate(unix int64, tz string) (time.Time, error) {
+	zone, err := time.Parse("-0700", tz)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing author-tz %q: %w", tz, err)
+	}
+	return time.Unix(unix, 0).In(zone.Location()), nil
+}
+
 func (r *nativeRepo) Log(sinceHash string) (CommitIter, error) {
 	args := []string{
 		"-C", r.path, "log",
 		"--format=GITANALYTICS_COMMIT%n%H%n%aN%n%aE%n%aI%n%s%n%b%nGITANALYTICS_ENDMETA",
 		"--numstat",
+		// "create mode" and "delete mode" summary lines give the change status.
+		"--summary",
+		// Raw lines give the blob hashes before and after each change.
+		"--raw", "--no-abbrev",
+		"-M", // detect renames regardless of the user's diff.renames setting
 	}
 	if sinceHash != "" {
 		args = append(args, sinceHash+"..HEAD")
@@ -74,10 +231,102 @@ func (r *nativeRepo) Log(sinceHash string) (CommitIter, error) {
 	return &nativeCommitIter{
 		scanner: bufio.NewScanner(stdout),
 		cmd:     cmd,
+		path:    r.path,
 	}, nil
 }
 
+func (r *nativeRepo) LogHunks(sinceHash string, limits HunkLimits) (HunkIter, error) {
+	args := []string{"-C", r.path}
+	if limits.MaxBytes > 0 {
+		// git reports files above the threshold as binary without diffing
+		// them.
+		args = append(args, "-c", "core.bigFileThreshold="+strconv.FormatInt(limits.MaxBytes, 10))
+	}
+	args = append(args,
+		"log",
+		"--format=GITANALYTICS_COMMIT%n%H",
+		"--patch", "--unified=0",
+		"--no-color", "--no-ext-diff", "--no-textconv",
+		"-M", // detect renames as Log does
+	)
+	if sinceHash != "" {
+		args = append(args, sinceHash+"..HEAD")
+	}
+
+	cmd := exec.Command("git", args...)
+	hideWindow(cmd)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("creating stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting git log: %w", err)
+	}
+	// Patch lines can be arbitrarily long, so lines are read with a Reader
+	// rather than a Scanner.
+	return &nativeHunkIter{reader: bufio.NewReader(stdout), cmd: cmd, maxFileHunks: limits.MaxHunks}, nil
+}
+
+func (r *nativeRepo) ReadBlob(hash string, maxBytes int64) ([]byte, error) {
+	r.blobMu.Lock()
+	defer r.blobMu.Unlock()
+
+	if r.blobCmd == nil {
+		cmd := exec.Command("git", "-C", r.path, "cat-file", "--batch")
+		hideWindow(cmd)
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, fmt.Errorf("creating stdin pipe: %w", err)
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, fmt.Errorf("creating stdout pipe: %w", err)
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("starting git cat-file: %w", err)
+		}
+		r.blobCmd, r.blobIn, r.blobOut = cmd, stdin, bufio.NewReader(stdout)
+	}
+
+	if _, err := io.WriteString(r.blobIn, hash+"\n"); err != nil {
+		return nil, fmt.Errorf("writing to git cat-file: %w", err)
+	}
+	header, err := r.blobOut.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("reading from git cat-file: %w", err)
+	}
+	// Format: "<object> <type> <size>" followed by the contents and a
+	// newline, or "<object> missing".
+	fields := strings.Fields(header)
+	if len(fields) != 3 || fields[1] != "blob" {
+		return nil, fmt.Errorf("reading blob %s: unexpected git cat-file output %q", hash, strings.TrimSpace(header))
+	}
+	size, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing git cat-file output %q: %w", header, err)
+	}
+	if maxBytes > 0 && size > maxBytes {
+		// The contents still have to be consumed to reach the next object.
+		if _, err := io.CopyN(io.Discard, r.blobOut, size+1); err != nil {
+			return nil, fmt.Errorf("reading blob %s: %w", hash, err)
+		}
+		return nil, ErrBlobTooLarge
+	}
+	data := make([]byte, size+1)
+	if _, err := io.ReadFull(r.blobOut, data); err != nil {
+		return nil, fmt.Errorf("reading blob %s: %w", hash, err)
+	}
+	return data[:size], nil
+}
+
 func (r *nativeRepo) Close() error {
+	r.blobMu.Lock()
+	defer r.blobMu.Unlock()
+	if r.blobCmd != nil {
+		r.blobIn.Close()
+		r.blobCmd.Wait()
+		r.blobCmd = nil
+	}
 	return nil
 }
 
@@ -88,6 +337,13 @@ type nativeCommitIter struct {
 	peeked    bool   // true if we've already scanned a line that needs re-reading
 	peekLine  string // the line we peeked at
 	exhausted bool
+
+	// Blob sizes of binary files are looked up with a git cat-file
+	// --batch-check process, started on first use.
+	path    string
+	sizeCmd *exec.Cmd
+	sizeIn  io.WriteCloser
+	sizeOut *bufio.Reader
 }
 
 func (it *nativeCommitIter) nextLine() (string, bool) {
@@ -152,8 +408,11 @@ func (it *nativeCommitIter) Next() (*Commit, error) {
 	}
 	description := strings.TrimSpace(strings.Join(descLines, "\n"))
 
-	// Read numstat lines until next sentinel or EOF.
+	// Read raw, numstat and summary lines until next sentinel or EOF.
 	var files []FileStat
+	created := make(map[string]bool)
+	deleted := make(map[string]bool)
+	blobs := make(map[string][2]string) // path after the change → old, new blob
 	for {
 		line, ok := it.nextLine()
 		if !ok {
@@ -167,6 +426,20 @@ func (it *nativeCommitIter) Next() (*Commit, error) {
 		if line == "" {
 			continue
 		}
+		if strings.HasPrefix(line, ":") {
+			if path, oldBlob, newBlob, ok := parseRawLine(line); ok {
+				blobs[path] = [2]string{oldBlob, newBlob}
+			}
+			continue
+		}
+		if path, ok := summaryPath(line, " create mode "); ok {
+			created[path] = true
+			continue
+		}
+		if path, ok := summaryPath(line, " delete mode "); ok {
+			deleted[path] = true
+			continue
+		}
 
 		fs, err := parseNumstatLine(line)
 		if err != nil {
@@ -174,6 +447,24 @@ func (it *nativeCommitIter) Next() (*Commit, error) {
 		}
 		files = append(files, fs)
 	}
+	for i := range files {
+		if files[i].Binary {
+			if err := it.setSizeDelta(meta[0], &files[i]); err != nil {
+				return nil, err
+			}
+		}
+		files[i].OldBlob, files[i].NewBlob = blobs[files[i].Path][0], blobs[files[i].Path][1]
+		switch f := &files[i]; {
+		case f.OldPath != "":
+			f.Status = StatusRenamed
+		case created[f.Path]:
+			f.Status = StatusAdded
+		case deleted[f.Path]:
+			f.Status = StatusDeleted
+		default:
+			f.Status = StatusModified
+		}
+	}
 
 	return &Commit{
 		Hash:         meta[0],
@@ -191,11 +482,225 @@ func (it *nativeCommitIter) Close() {
 		it.cmd.Process.Kill()
 		it.cmd.Wait()
 	}
+	if it.sizeCmd != nil {
+		it.sizeIn.Close()
+		it.sizeCmd.Wait()
+	}
+}
+
+// nativeHunkIter parses streaming output from git log --patch --unified=0.
+type nativeHunkIter struct {
+	reader       *bufio.Reader
+	cmd          *exec.Cmd
+	maxFileHunks int
+	peeked       bool
+	peekLine     string
+	exhausted    bool
+}
+
+func (it *nativeHunkIter) nextLine() (string, bool, error) {
+	if it.peeked {
+		it.peeked = false
+		return it.peekLine, true, nil
+	}
+	line, err := it.reader.ReadString('\n')
+	if err == io.EOF {
+		return line, line != "", nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("reading git log output: %w", err)
+	}
+	return strings.TrimSuffix(line, "\n"), true, nil
+}
+
+func (it *nativeHunkIter) Next() (*CommitHunks, error) {
+	if it.exhausted {
+		return nil, nil
+	}
+
+	// Scan until we find the sentinel line.
+	for {
+		line, ok, err := it.nextLine()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			it.exhausted = true
+			return nil, nil
+		}
+		if line == "GITANALYTICS_COMMIT" {
+			break
+		}
+	}
+	hash, ok, err := it.nextLine()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of git log output (expected commit hash)")
+	}
+
+	c := &CommitHunks{Hash: hash}
+	var oldPath, newPath string
+	for {
+		line, ok, err := it.nextLine()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			it.exhausted = true
+			break
+		}
+		if line == "GITANALYTICS_COMMIT" {
+			it.peeked, it.peekLine = true, line
+			break
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			oldPath, newPath = "", ""
+		case strings.HasPrefix(line, "--- "):
+			oldPath = patchPath(line[4:], "a/")
+		case strings.HasPrefix(line, "+++ "):
+			newPath = patchPath(line[4:], "b/")
+		case strings.HasPrefix(line, "@@ "):
+			h, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			h.Path = newPath
+			if h.Path == "" {
+				h.Path = oldPath // deleted file
+			}
+			c.Hunks = append(c.Hunks, h)
+			// Skip the hunk's lines, which may themselves look like headers.
+			for n := h.OldLines + h.NewLines; n > 0; {
+				line, ok, err := it.nextLine()
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					return nil, fmt.Errorf("unexpected end of git log output in hunk of %s", h.Path)
+				}
+				if !strings.HasPrefix(line, `\`) { // "\ No newline at end of file"
+					n--
+				}
+			}
+		}
+	}
+	c.Hunks = capHunks(c.Hunks, it.maxFileHunks)
+	return c, nil
+}
+
+func (it *nativeHunkIter) Close() {
+	if it.cmd != nil && it.cmd.Process != nil {
+		it.cmd.Process.Kill()
+		it.cmd.Wait()
+	}
+}
+
+// setSizeDelta sets f's size delta from its blob sizes in commit hash and in
+// its first parent.
+func (it *nativeCommitIter) setSizeDelta(hash string, f *FileStat) error {
+	oldPath := f.Path
+	if f.OldPath != "" {
+		oldPath = f.OldPath
+	}
+	newSize, err := it.blobSize(hash + ":" + f.Path)
+	if err != nil {
+		return err
+	}
+	oldSize, err := it.blobSize(hash + "^:" + oldPath)
+	if err != nil {
+		return err
+	}
+	f.SizeDelta = newSize - oldSize
+	return nil
+}
+
+// blobSize returns the size of the object named by rev (e.g. "HEAD:path"),
+// or 0 if it does not exist.
+func (it *nativeCommitIter) blobSize(rev string) (int64, error) {
+	if it.sizeCmd == nil {
+		cmd := exec.Command("git", "-C", it.path, "cat-file", "--batch-check")
+		hideWindow(cmd)
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return 0, fmt.Errorf("creating stdin pipe: %w", err)
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return 0, fmt.Errorf("creating stdout pipe: %w", err)
+		}
+		if err := cmd.Start(); err != nil {
+			return 0, fmt.Errorf("starting git cat-file: %w", err)
+		}
+		it.sizeCmd, it.sizeIn, it.sizeOut = cmd, stdin, bufio.NewReader(stdout)
+	}
+
+	if _, err := io.WriteString(it.sizeIn, rev+"\n"); err != nil {
+		return 0, fmt.Errorf("writing to git cat-file: %w", err)
+	}
+	line, err := it.sizeOut.ReadString('\n')
+	if err != nil {
+		return 0, fmt.Errorf("reading from git cat-file: %w", err)
+	}
+	// Format: "<object> <type> <size>", or "<rev> missing".
+	line = strings.TrimSuffix(line, "\n")
+	if strings.HasSuffix(line, " missing") || strings.HasSuffix(line, " ambiguous") {
+		return 0, nil
+	}
+	size, err := strconv.ParseInt(line[strings.LastIndexByte(line, ' ')+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing git cat-file output %q: %w", line, err)
+	}
+	return size, nil
+}
+
+// parseRawLine parses a --raw line such as
+// ":100644 100644 <old blob> <new blob> M\tpath" or, for a rename,
+// ":100644 100644 <old blob> <new blob> R100\told\tnew", returning the path
+// after the change and the blob hashes, with the all-zero hash of a missing
+// side as "".
+func parseRawLine(line string) (path, oldBlob, newBlob string, ok bool) {
+	meta, paths, ok := strings.Cut(line, "\t")
+	if !ok {
+		return "", "", "", false
+	}
+	fields := strings.Fields(meta)
+	if len(fields) < 5 {
+		return "", "", "", false
+	}
+	if _, after, renamed := strings.Cut(paths, "\t"); renamed {
+		paths = after
+	}
+	return paths, nonZeroHash(fields[2]), nonZeroHash(fields[3]), true
+}
+
+// nonZeroHash returns hash, or "" if it is git's all-zero hash for a
+// missing object.
+func nonZeroHash(hash string) string {
+	if strings.Trim(hash, "0") == "" {
+		return ""
+	}
+	return hash
+}
+
+// summaryPath returns the path of a --summary line such as
+// " create mode 100644 path" that starts with prefix.
+func summaryPath(line, prefix string) (string, bool) {
+	rest, ok := strings.CutPrefix(line, prefix)
+	if !ok {
+		return "", false
+	}
+	_, path, ok := strings.Cut(rest, " ") // skip the mode
+	return path, ok
 }
 
 // parseNumstatLine parses a single --numstat output line.
 // Format: "additions\tdeletions\tpath"
-// Binary files show "-\t-\tpath" — treated as 0/0.
+// Binary files show "-\t-\tpath" — recorded as binary with 0/0.
+// Renamed files show the path as "old => new" or "dir/{old => new}".
 func parseNumstatLine(line string) (FileStat, error) {
 	parts := strings.SplitN(line, "\t", 3)
 	if len(parts) != 3 {
@@ -218,9 +723,60 @@ func parseNumstatLine(line string) (FileStat, error) {
 		}
 	}
 
+	oldPath, path := SplitRenamePath(parts[2])
 	return FileStat{
-		Path:      parts[2],
+		Path:      path,
+		OldPath:   oldPath,
 		Additions: additions,
 		Deletions: deletions,
+		Binary:    parts[0] == "-" && parts[1] == "-",
 	}, nil
 }
+
+// patchPath returns the path named by the rest of a "--- " or "+++ " patch
+// line, without its a/ or b/ prefix, or "" for /dev/null. Paths with special
+// characters are quoted by git, and paths with spaces are followed by a tab.
+func patchPath(name, prefix string) string {
+	if name == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(name, `"`) {
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		}
+	} else {
+		name = strings.TrimSuffix(name, "\t")
+	}
+	return strings.TrimPrefix(name, prefix)
+}
+
+// parseHunkHeader parses a hunk header such as "@@ -12,3 +12,0 @@ func f() {".
+// A range without a count has one line.
+func parseHunkHeader(line string) (Hunk, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return Hunk{}, fmt.Errorf("malformed hunk header %q", line)
+	}
+	var h Hunk
+	var err error
+	if h.OldStart, h.OldLines, err = parseHunkRange(fields[1][1:]); err != nil {
+		return Hunk{}, fmt.Errorf("malformed hunk header %q: %w", line, err)
+	}
+	if h.NewStart, h.NewLines, err = parseHunkRange(fields[2][1:]); err != nil {
+		return Hunk{}, fmt.Errorf("malformed hunk header %q: %w", line, err)
+	}
+	return h, nil
+}
+
+// parseHunkRange parses "start,count" or "start".
+func parseHunkRange(s string) (start, count int, err error) {
+	startStr, countStr, hasCount := strings.Cut(s, ",")
+	if start, err = strconv.Atoi(startStr); err != nil {
+		return 0, 0, err
+	}
+	if !hasCount {
+		return start, 1, nil
+	}
+	count, err = strconv.Atoi(countStr)
+	return start, count, err
+}
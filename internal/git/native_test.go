@@ -207,3 +207,135 @@ func TestNativeHeadHash(t *testing.T) {
 		t.Errorf("expected 40-char hash, got %d chars: %q", len(hash), hash)
 	}
 }
+
+func TestNativeRename(t *testing.T) {
+	repoPath := initTestRepoWithRename(t)
+
+	repo, err := git.NativeOpen(repoPath)
+	if err != nil {
+		t.Fatalf("NativeOpen: %v", err)
+	}
+	defer repo.Close()
+
+	iter, err := repo.Log("")
+	if err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	defer iter.Close()
+
+	renamed, err := iter.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if len(renamed.FilesChanged) != 1 {
+		t.Fatalf("expected 1 file changed, got %d: %+v", len(renamed.FilesChanged), renamed.FilesChanged)
+	}
+	f := renamed.FilesChanged[0]
+	if f.Path != "src/new.go" || f.OldPath != "src/old.go" {
+		t.Errorf("expected src/old.go => src/new.go, got %q => %q", f.OldPath, f.Path)
+	}
+	if f.Status != git.StatusRenamed {
+		t.Errorf("expected status R, got %q", f.Status)
+	}
+	if f.Additions != 2 {
+		t.Errorf("expected 2 additions, got %d", f.Additions)
+	}
+}
+
+func TestNativeTreeFiles(t *testing.T) {
+	repoPath := initTestRepoWithRename(t)
+
+	repo, err := git.NativeOpen(repoPath)
+	if err != nil {
+		t.Fatalf("NativeOpen: %v", err)
+	}
+	defer repo.Close()
+
+	files, err := repo.TreeFiles("HEAD")
+	if err != nil {
+		t.Fatalf("TreeFiles: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "src/new.go" || len(files[0].Blob) != 40 {
+		t.Errorf("expected only src/new.go with a blob hash, got %+v", files)
+	}
+}
+
+func TestNativeStatus(t *testing.T) {
+	repo, err := git.NativeOpen(initTestRepoWithLifecycle(t))
+	if err != nil {
+		t.Fatalf("NativeOpen: %v", err)
+	}
+	defer repo.Close()
+
+	checkLifecycleStatuses(t, repo)
+}
+
+func TestNativeBinary(t *testing.T) {
+	repo, err := git.NativeOpen(initTestRepoWithBinary(t))
+	if err != nil {
+		t.Fatalf("NativeOpen: %v", err)
+	}
+	defer repo.Close()
+
+	checkBinaryStats(t, repo)
+}
+
+func TestNativeBlame(t *testing.T) {
+	repo, err := git.NativeOpen(initTestRepoWithLifecycle(t))
+	if err != nil {
+		t.Fatalf("NativeOpen: %v", err)
+	}
+	defer repo.Close()
+
+	checkBlame(t, repo)
+}
+
+func TestNativeFirstParentBefore(t *testing.T) {
+	repo, err := git.NativeOpen(initTestRepoWithLifecycle(t))
+	if err != nil {
+		t.Fatalf("NativeOpen: %v", err)
+	}
+	defer repo.Close()
+
+	checkFirstParentBefore(t, repo)
+}
+
+func TestNativeFirstParentBeforeAuthorDate(t *testing.T) {
+	repo, err := git.NativeOpen(initTestRepoWithBackdatedCommit(t))
+	if err != nil {
+		t.Fatalf("NativeOpen: %v", err)
+	}
+	defer repo.Close()
+
+	checkFirstParentBeforeAuthorDate(t, repo)
+}
+
+func TestNativeLogHunks(t *testing.T) {
+	repo, err := git.NativeOpen(initTestRepoWithHunks(t))
+	if err != nil {
+		t.Fatalf("NativeOpen: %v", err)
+	}
+	defer repo.Close()
+
+	checkHunks(t, repo)
+}
+
+func TestNativeReadBlob(t *testing.T) {
+	repo, err := git.NativeOpen(initTestRepoWithLifecycle(t))
+	if err != nil {
+		t.Fatalf("NativeOpen: %v", err)
+	}
+	defer repo.Close()
+
+	checkReadBlob(t, repo)
+}
+
+func TestNativeBlobAt(t *testing.T) {
+	repo, err := git.NativeOpen(initTestRepoWithLifecycle(t))
+	if err != nil {
+		t.Fatalf("NativeOpen: %v", err)
+	}
+	defer repo.Close()
+
+	checkBlobAt(t, repo)
+}
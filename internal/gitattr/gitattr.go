@@ -0,0 +1,120 @@
+// Package gitattr evaluates the attributes that .gitattributes files assign
+// to paths.
+package gitattr
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"git-analytics/internal/pathmatch"
+)
+
+// Matcher answers attribute lookups for the .gitattributes files of a tree.
+type Matcher struct {
+	rules []rule // in increasing order of precedence
+}
+
+// rule is one pattern line of a .gitattributes file.
+type rule struct {
+	dir     string // directory of the .gitattributes file, "" for the root
+	pattern pathmatch.Pattern
+	attrs   map[string]string
+}
+
+// New returns a Matcher for the given .gitattributes files, keyed by their
+// slash-separated repository paths. As in git, files in deeper directories
+// take precedence over those above them, and later lines over earlier ones.
+// Macro attributes (such as binary) are not expanded, and quoted patterns
+// are not supported.
+func New(files map[string][]byte) *Matcher {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		di, dj := strings.Count(names[i], "/"), strings.Count(names[j], "/")
+		if di != dj {
+			return di < dj
+		}
+		return names[i] < names[j]
+	})
+
+	m := &Matcher{}
+	for _, name := range names {
+		dir := path.Dir(name)
+		if dir == "." {
+			dir = ""
+		}
+		for line := range strings.Lines(string(files[name])) {
+			if r, ok := parseLine(dir, line); ok {
+				m.rules = append(m.rules, r)
+			}
+		}
+	}
+	return m
+}
+
+// parseLine parses one line of the .gitattributes file in dir.
+func parseLine(dir, line string) (rule, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+		return rule{}, false
+	}
+	pattern, ok := pathmatch.Parse(fields[0])
+	// Patterns matching only directories never match a file, and
+	// negative patterns are forbidden.
+	if !ok || pattern.DirOnly || pattern.Negate {
+		return rule{}, false
+	}
+
+	r := rule{dir: dir, pattern: pattern, attrs: make(map[string]string, len(fields)-1)}
+	for _, attr := range fields[1:] {
+		switch {
+		case strings.HasPrefix(attr, "-"):
+			r.attrs[attr[1:]] = "false"
+		case strings.HasPrefix(attr, "!"):
+			r.attrs[attr[1:]] = ""
+		default:
+			name, value, ok := strings.Cut(attr, "=")
+			if !ok {
+				value = "true"
+			}
+			r.attrs[name] = value
+		}
+	}
+	return r, true
+}
+
+// Value returns the value of attribute attr for the file at p, a
+// slash-separated repository path: "true" if it is set, "false" if it is
+// unset, or its value. ok is false if the attribute is unspecified.
+func (m *Matcher) Value(p, attr string) (value string, ok bool) {
+	for i := len(m.rules) - 1; i >= 0; i-- {
+		r := m.rules[i]
+		v, has := r.attrs[attr]
+		if !has || !r.matches(p) {
+			continue
+		}
+		return v, v != ""
+	}
+	return "", false
+}
+
+// IsSet reports whether attribute attr is set for the file at p, either
+// plainly or with a value other than false.
+func (m *Matcher) IsSet(p, attr string) bool {
+	v, ok := m.Value(p, attr)
+	return ok && v != "false"
+}
+
+func (r rule) matches(p string) bool {
+	if r.dir != "" {
+		rel, ok := strings.CutPrefix(p, r.dir+"/")
+		if !ok {
+			return false
+		}
+		p = rel
+	}
+	return r.pattern.Match(p, false)
+}
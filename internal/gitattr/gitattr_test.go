@@ -0,0 +1,56 @@
+package gitattr_test
+
+import (
+	"testing"
+
+	"git-analytics/internal/gitattr"
+)
+
+func TestMatcher(t *testing.T) {
+	m := gitattr.New(map[string][]byte{
+		".gitattributes": []byte(`# comment
+*.pb.go linguist-generated
+/docs/** linguist-documentation
+scripts/* linguist-language=Python
+**/testdata/** -linguist-vendored
+third_party/** linguist-vendored
+api/*.go linguist-generated=true
+build/ linguist-generated
+`),
+		"third_party/keep/.gitattributes": []byte("*.go -linguist-vendored\n"),
+		"web/.gitattributes":              []byte("*.js linguist-generated\nlegacy.js !linguist-generated\n"),
+	})
+
+	tests := []struct {
+		path, attr string
+		value      string
+		ok         bool
+	}{
+		{"foo.pb.go", "linguist-generated", "true", true},
+		{"pkg/deep/foo.pb.go", "linguist-generated", "true", true},
+		{"foo.go", "linguist-generated", "", false},
+		{"docs/guide/intro.md", "linguist-documentation", "true", true},
+		{"pkg/docs/intro.md", "linguist-documentation", "", false},
+		{"scripts/run", "linguist-language", "Python", true},
+		{"scripts/sub/run", "linguist-language", "", false},
+		{"a/testdata/b/c.txt", "linguist-vendored", "false", true},
+		{"third_party/lib/x.c", "linguist-vendored", "true", true},
+		{"third_party/keep/x.go", "linguist-vendored", "false", true},
+		{"api/v1.go", "linguist-generated", "true", true},
+		{"api/sub/v1.go", "linguist-generated", "", false},
+		{"build/out.js", "linguist-generated", "", false},
+		{"web/app.js", "linguist-generated", "true", true},
+		{"web/legacy.js", "linguist-generated", "", false},
+		{"app.js", "linguist-generated", "", false},
+	}
+	for _, tt := range tests {
+		value, ok := m.Value(tt.path, tt.attr)
+		if value != tt.value || ok != tt.ok {
+			t.Errorf("Value(%q, %q) = %q, %v, want %q, %v", tt.path, tt.attr, value, ok, tt.value, tt.ok)
+		}
+	}
+
+	if !m.IsSet("foo.pb.go", "linguist-generated") || m.IsSet("a/testdata/x", "linguist-vendored") {
+		t.Error("IsSet disagrees with Value")
+	}
+}
@@ -0,0 +1,108 @@
+// Package gosrc extracts the function and method declarations of Go source
+// files.
+package gosrc
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+)
+
+// Func is a function or method declared in a Go file.
+type Func struct {
+	Name      string // "F", "T.M" or "(*T).M"; type parameters are omitted
+	StartLine int    // line of the func keyword
+	EndLine   int    // line of the closing brace
+}
+
+// Funcs returns the function and method declarations of the Go source src,
+// in source order.
+func Funcs(src []byte) ([]Func, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var funcs []Func
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		funcs = append(funcs, Func{
+			Name:      funcName(fn),
+			StartLine: fset.Position(fn.Pos()).Line,
+			EndLine:   fset.Position(fn.End()).Line,
+		})
+	}
+	return funcs, nil
+}
+
+// funcName returns the qualified name of fn.
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	typ := fn.Recv.List[0].Type
+	pointer := false
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ, pointer = star.X, true
+	}
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	name := "?"
+	if ident, ok := typ.(*ast.Ident); ok {
+		name = ident.Name
+	}
+	if pointer {
+		return "(*" + name + ")." + fn.Name.Name
+	}
+	return name + "." + fn.Name.Name
+}
+
+// Complexity returns the cyclomatic complexity of the Go source src: the
+// sum, over its function and method declarations, of one plus the number of
+// decision points (if, for and range statements, non-default case and
+// select clauses, and && and || operators) in the body, including any
+// function literals it contains.
+func Complexity(src []byte) (int, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		total++
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+				total++
+			case *ast.CaseClause:
+				if n.List != nil {
+					total++
+				}
+			case *ast.CommClause:
+				if n.Comm != nil {
+					total++
+				}
+			case *ast.BinaryExpr:
+				if n.Op == token.LAND || n.Op == token.LOR {
+					total++
+				}
+			}
+			return true
+		})
+	}
+	return total, nil
+}
@@ -0,0 +1,83 @@
+package gosrc_test
+
+import (
+	"testing"
+
+	"git-analytics/internal/gosrc"
+)
+
+const src = `package p
+
+func F() {}
+
+// Get returns v.
+func (t *T[K]) Get(k K) int {
+	return 0
+}
+
+type T[K comparable] struct{}
+
+func (s S) String() string {
+	return ""
+}
+`
+
+func TestFuncs(t *testing.T) {
+	funcs, err := gosrc.Funcs([]byte(src))
+	if err != nil {
+		t.Fatalf("Funcs: %v", err)
+	}
+	want := []gosrc.Func{
+		{Name: "F", StartLine: 3, EndLine: 3},
+		{Name: "(*T).Get", StartLine: 6, EndLine: 8},
+		{Name: "S.String", StartLine: 12, EndLine: 14},
+	}
+	if len(funcs) != len(want) {
+		t.Fatalf("got %+v, want %+v", funcs, want)
+	}
+	for i := range want {
+		if funcs[i] != want[i] {
+			t.Errorf("func %d: got %+v, want %+v", i, funcs[i], want[i])
+		}
+	}
+}
+
+func TestFuncs_SyntaxError(t *testing.T) {
+	if _, err := gosrc.Funcs([]byte("package p\nfunc {")); err == nil {
+		t.Error("expected a syntax error")
+	}
+}
+
+func TestComplexity(t *testing.T) {
+	src := `package p
+
+func A() {}
+
+func B(x int, ch chan int) int {
+	if x > 0 && x < 10 {
+		return 1
+	}
+	for i := range 3 {
+		switch i {
+		case 1, 2:
+		default:
+		}
+	}
+	select {
+	case <-ch:
+	default:
+	}
+	f := func() bool { return x == 1 || x == 2 }
+	_ = f
+	return 0
+}
+`
+	// A is 1; B is 1 plus if, &&, range, case, select case and ||.
+	got, err := gosrc.Complexity([]byte(src))
+	if err != nil {
+		t.Fatalf("Complexity: %v", err)
+	}
+	if got != 8 {
+		t.Errorf("got %d, want 8", got)
+	}
+}
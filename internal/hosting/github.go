@@ -0,0 +1,122 @@
+package hosting
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// gitHub reads pull requests from the GitHub REST API.
+type gitHub struct {
+	*client
+	repository string
+}
+
+type gitHubUser struct {
+	Login string `json:"login"`
+}
+
+type gitHubPull struct {
+	Number             int          `json:"number"`
+	Title              string       `json:"title"`
+	User               gitHubUser   `json:"user"`
+	State              string       `json:"state"`
+	CreatedAt          *string      `json:"created_at"`
+	UpdatedAt          *string      `json:"updated_at"`
+	MergedAt           *string      `json:"merged_at"`
+	ClosedAt           *string      `json:"closed_at"`
+	MergeCommitSHA     *string      `json:"merge_commit_sha"`
+	RequestedReviewers []gitHubUser `json:"requested_reviewers"`
+}
+
+type gitHubReview struct {
+	User        gitHubUser `json:"user"`
+	State       string     `json:"state"`
+	SubmittedAt *string    `json:"submitted_at"`
+}
+
+type gitHubCommit struct {
+	SHA string `json:"sha"`
+}
+
+func (g *gitHub) PullRequests(ctx context.Context, since time.Time) ([]PullRequest, error) {
+	base := "/repos/" + g.repository + "/pulls"
+
+	// The list endpoint has no update filter, so pull requests are listed
+	// most recently updated first until one older than since turns up.
+	var pulls []gitHubPull
+	err := g.getAll(ctx, base+"?state=all&sort=updated&direction=desc", func(page string) (int, error) {
+		var batch []gitHubPull
+		if err := g.get(ctx, page, &batch); err != nil {
+			return 0, err
+		}
+		for _, p := range batch {
+			if parseTime(p.UpdatedAt).Before(since) {
+				return 0, nil
+			}
+			pulls = append(pulls, p)
+		}
+		return len(batch), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PullRequest, 0, len(pulls))
+	for _, p := range pulls {
+		pr := PullRequest{
+			Number:    p.Number,
+			Title:     p.Title,
+			Author:    p.User.Login,
+			State:     p.State,
+			CreatedAt: parseTime(p.CreatedAt),
+			UpdatedAt: parseTime(p.UpdatedAt),
+			MergedAt:  parseTime(p.MergedAt),
+			ClosedAt:  parseTime(p.ClosedAt),
+		}
+		if !pr.MergedAt.IsZero() {
+			pr.State = "merged"
+			if p.MergeCommitSHA != nil {
+				pr.MergeCommit = *p.MergeCommitSHA
+			}
+		}
+		for _, u := range p.RequestedReviewers {
+			pr.Reviewers = append(pr.Reviewers, u.Login)
+		}
+
+		prPath := fmt.Sprintf("%s/%d", base, p.Number)
+		err := g.getAll(ctx, prPath+"/reviews", func(page string) (int, error) {
+			var batch []gitHubReview
+			if err := g.get(ctx, page, &batch); err != nil {
+				return 0, err
+			}
+			for _, r := range batch {
+				pr.Reviews = append(pr.Reviews, Review{
+					Reviewer:    r.User.Login,
+					State:       strings.ToLower(r.State),
+					SubmittedAt: parseTime(r.SubmittedAt),
+				})
+			}
+			return len(batch), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = g.getAll(ctx, prPath+"/commits", func(page string) (int, error) {
+			var batch []gitHubCommit
+			if err := g.get(ctx, page, &batch); err != nil {
+				return 0, err
+			}
+			for _, c := range batch {
+				pr.Commits = append(pr.Commits, c.SHA)
+			}
+			return len(batch), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, pr)
+	}
+	return result, nil
+}
@@ -0,0 +1,140 @@
+package hosting
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// gitLab reads merge requests from the GitLab REST API.
+type gitLab struct {
+	*client
+	repository string
+}
+
+type gitLabUser struct {
+	Username string `json:"username"`
+}
+
+type gitLabMergeRequest struct {
+	IID             int          `json:"iid"`
+	Title           string       `json:"title"`
+	Author          gitLabUser   `json:"author"`
+	State           string       `json:"state"`
+	CreatedAt       *string      `json:"created_at"`
+	UpdatedAt       *string      `json:"updated_at"`
+	MergedAt        *string      `json:"merged_at"`
+	ClosedAt        *string      `json:"closed_at"`
+	MergeCommitSHA  *string      `json:"merge_commit_sha"`
+	SquashCommitSHA *string      `json:"squash_commit_sha"`
+	Reviewers       []gitLabUser `json:"reviewers"`
+}
+
+type gitLabNote struct {
+	Author    gitLabUser `json:"author"`
+	System    bool       `json:"system"`
+	CreatedAt *string    `json:"created_at"`
+}
+
+type gitLabApprovals struct {
+	ApprovedBy []struct {
+		User gitLabUser `json:"user"`
+	} `json:"approved_by"`
+}
+
+type gitLabCommit struct {
+	ID string `json:"id"`
+}
+
+func (g *gitLab) PullRequests(ctx context.Context, since time.Time) ([]PullRequest, error) {
+	base := "/projects/" + url.PathEscape(g.repository) + "/merge_requests"
+
+	list := base + "?state=all"
+	if !since.IsZero() {
+		list += "&updated_after=" + url.QueryEscape(since.UTC().Format(time.RFC3339))
+	}
+	var mrs []gitLabMergeRequest
+	err := g.getAll(ctx, list, func(page string) (int, error) {
+		var batch []gitLabMergeRequest
+		if err := g.get(ctx, page, &batch); err != nil {
+			return 0, err
+		}
+		mrs = append(mrs, batch...)
+		return len(batch), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PullRequest, 0, len(mrs))
+	for _, m := range mrs {
+		pr := PullRequest{
+			Number:    m.IID,
+			Title:     m.Title,
+			Author:    m.Author.Username,
+			State:     m.State,
+			CreatedAt: parseTime(m.CreatedAt),
+			UpdatedAt: parseTime(m.UpdatedAt),
+			MergedAt:  parseTime(m.MergedAt),
+			ClosedAt:  parseTime(m.ClosedAt),
+		}
+		if pr.State == "opened" {
+			pr.State = "open"
+		}
+		if m.SquashCommitSHA != nil {
+			pr.MergeCommit = *m.SquashCommitSHA
+		} else if m.MergeCommitSHA != nil {
+			pr.MergeCommit = *m.MergeCommitSHA
+		}
+		for _, u := range m.Reviewers {
+			pr.Reviewers = append(pr.Reviewers, u.Username)
+		}
+
+		mrPath := fmt.Sprintf("%s/%d", base, m.IID)
+		// GitLab has no review objects; comments by other users count as
+		// reviews, and approvals are recorded without a timestamp.
+		err := g.getAll(ctx, mrPath+"/notes?sort=asc&order_by=created_at", func(page string) (int, error) {
+			var batch []gitLabNote
+			if err := g.get(ctx, page, &batch); err != nil {
+				return 0, err
+			}
+			for _, n := range batch {
+				if n.System || n.Author.Username == pr.Author {
+					continue
+				}
+				pr.Reviews = append(pr.Reviews, Review{
+					Reviewer:    n.Author.Username,
+					State:       "commented",
+					SubmittedAt: parseTime(n.CreatedAt),
+				})
+			}
+			return len(batch), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		var approvals gitLabApprovals
+		if err := g.get(ctx, mrPath+"/approvals", &approvals); err != nil {
+			return nil, err
+		}
+		for _, a := range approvals.ApprovedBy {
+			pr.Reviews = append(pr.Reviews, Review{Reviewer: a.User.Username, State: "approved"})
+		}
+		err = g.getAll(ctx, mrPath+"/commits", func(page string) (int, error) {
+			var batch []gitLabCommit
+			if err := g.get(ctx, page, &batch); err != nil {
+				return 0, err
+			}
+			for _, c := range batch {
+				pr.Commits = append(pr.Commits, c.ID)
+			}
+			return len(batch), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, pr)
+	}
+	return result, nil
+}
@@ -0,0 +1,147 @@
+// Package hosting imports pull request metadata from code hosting providers
+// through their REST APIs.
+package hosting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PullRequest is a pull request (GitHub) or merge request (GitLab). Zero
+// times mean the event has not happened.
+type PullRequest struct {
+	Number      int
+	Title       string
+	Author      string
+	State       string // "open", "merged" or "closed"
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	MergedAt    time.Time
+	ClosedAt    time.Time
+	MergeCommit string
+	Commits     []string // hashes of the commits in the pull request
+	Reviewers   []string // requested reviewers
+	Reviews     []Review
+}
+
+// Review is one review event on a pull request.
+type Review struct {
+	Reviewer    string
+	State       string    // e.g. "approved", "changes_requested", "commented"
+	SubmittedAt time.Time // zero if the provider does not record it
+}
+
+// Provider fetches pull requests from a hosting service.
+type Provider interface {
+	// PullRequests returns the pull requests updated at or after since, or
+	// every pull request if since is zero.
+	PullRequests(ctx context.Context, since time.Time) ([]PullRequest, error)
+}
+
+// Provider kinds accepted by New.
+const (
+	KindGitHub = "github"
+	KindGitLab = "gitlab"
+)
+
+// New returns a provider of the given kind for repository ("owner/name" on
+// GitHub, the project path on GitLab). An empty baseURL selects the public
+// service; set it for self-hosted instances or a local mock. token may be
+// empty for public repositories.
+func New(kind, baseURL, repository, token string) (Provider, error) {
+	if repository == "" {
+		return nil, fmt.Errorf("no repository given")
+	}
+	c := &client{http: &http.Client{Timeout: requestTimeout}, token: token}
+	switch kind {
+	case KindGitHub:
+		c.baseURL = strings.TrimRight(orDefault(baseURL, "https://api.github.com"), "/")
+		c.authHeader, c.authPrefix = "Authorization", "Bearer "
+		return &gitHub{client: c, repository: repository}, nil
+	case KindGitLab:
+		c.baseURL = strings.TrimRight(orDefault(baseURL, "https://gitlab.com/api/v4"), "/")
+		c.authHeader = "PRIVATE-TOKEN"
+		return &gitLab{client: c, repository: repository}, nil
+	}
+	return nil, fmt.Errorf("unknown hosting provider %q", kind)
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// perPage is the page size requested from paginated endpoints.
+const perPage = 100
+
+// requestTimeout bounds each API request, so that a stalled connection
+// fails the import instead of hanging it.
+const requestTimeout = 30 * time.Second
+
+// client performs authenticated JSON requests against a REST API.
+type client struct {
+	http       *http.Client
+	baseURL    string
+	token      string
+	authHeader string
+	authPrefix string
+}
+
+// get decodes the JSON response of GET baseURL+path into v.
+func (c *client) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set(c.authHeader, c.authPrefix+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// getAll fetches every page of a paginated list endpoint, until a page is
+// short or ctx is done. fetch decodes the page at the given path and returns
+// its length; it may return 0 to stop early.
+func (c *client) getAll(ctx context.Context, path string, fetch func(page string) (int, error)) error {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := fetch(fmt.Sprintf("%s%sper_page=%d&page=%d", path, sep, perPage, page))
+		if err != nil {
+			return err
+		}
+		if n < perPage {
+			return nil
+		}
+	}
+}
+
+// parseTime parses an RFC 3339 timestamp, returning the zero time for nil.
+func parseTime(s *string) time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	t, _ := time.Parse(time.RFC3339, *s)
+	return t
+}
@@ -0,0 +1,140 @@
+package hosting_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"git-analytics/internal/hosting"
+)
+
+// mockAPI serves canned JSON bodies keyed by request URI and records the
+// headers of the last request.
+func mockAPI(t *testing.T, responses map[string]string) (*httptest.Server, *http.Header) {
+	t.Helper()
+	var last http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last = r.Header.Clone()
+		body, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &last
+}
+
+func TestGitHubPullRequests(t *testing.T) {
+	srv, headers := mockAPI(t, map[string]string{
+		"/repos/acme/app/pulls?state=all&sort=updated&direction=desc&per_page=100&page=1": `[
+			{"number": 12, "title": "Add login", "user": {"login": "alice"}, "state": "closed",
+			 "created_at": "2025-01-10T10:00:00Z", "updated_at": "2025-01-11T10:00:00Z",
+			 "merged_at": "2025-01-11T10:00:00Z",
+			 "closed_at": "2025-01-11T10:00:00Z", "merge_commit_sha": "m12",
+			 "requested_reviewers": [{"login": "carol"}]},
+			{"number": 9, "title": "Old", "user": {"login": "bob"}, "state": "open",
+			 "created_at": "2024-12-01T10:00:00Z", "updated_at": "2024-12-02T10:00:00Z"}]`,
+		"/repos/acme/app/pulls/12/reviews?per_page=100&page=1": `[
+			{"user": {"login": "bob"}, "state": "APPROVED", "submitted_at": "2025-01-10T14:00:00Z"}]`,
+		"/repos/acme/app/pulls/12/commits?per_page=100&page=1": `[{"sha": "c1"}, {"sha": "c2"}]`,
+	})
+
+	p, err := hosting.New(hosting.KindGitHub, srv.URL+"/", "acme/app", "secret")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	// Pull request 9 was last updated before since, so its reviews and
+	// commits are not fetched.
+	prs, err := p.PullRequests(context.Background(), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("PullRequests: %v", err)
+	}
+	if got := headers.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("got Authorization %q, want Bearer secret", got)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("got %d pull requests, want 1", len(prs))
+	}
+	pr := prs[0]
+	if pr.Number != 12 || pr.Author != "alice" || pr.State != "merged" || pr.MergeCommit != "m12" ||
+		!pr.UpdatedAt.Equal(time.Date(2025, 1, 11, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("got %+v", pr)
+	}
+	if !pr.MergedAt.Equal(time.Date(2025, 1, 11, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("got merged at %v", pr.MergedAt)
+	}
+	if len(pr.Commits) != 2 || len(pr.Reviewers) != 1 || pr.Reviewers[0] != "carol" {
+		t.Errorf("got commits %v, reviewers %v", pr.Commits, pr.Reviewers)
+	}
+	if len(pr.Reviews) != 1 || pr.Reviews[0].Reviewer != "bob" || pr.Reviews[0].State != "approved" {
+		t.Errorf("got reviews %+v", pr.Reviews)
+	}
+}
+
+func TestGitLabPullRequests(t *testing.T) {
+	srv, headers := mockAPI(t, map[string]string{
+		"/projects/group%2Fapp/merge_requests?state=all&updated_after=2025-01-01T00%3A00%3A00Z&per_page=100&page=1": `[
+			{"iid": 3, "title": "Fix crash", "author": {"username": "alice"}, "state": "opened",
+			 "created_at": "2025-01-10T10:00:00Z", "merged_at": null, "closed_at": null,
+			 "merge_commit_sha": null, "squash_commit_sha": null,
+			 "reviewers": [{"username": "bob"}]}]`,
+		"/projects/group%2Fapp/merge_requests/3/notes?sort=asc&order_by=created_at&per_page=100&page=1": `[
+			{"author": {"username": "alice"}, "system": false, "created_at": "2025-01-10T11:00:00Z"},
+			{"author": {"username": "bob"}, "system": true, "created_at": "2025-01-10T11:30:00Z"},
+			{"author": {"username": "bob"}, "system": false, "created_at": "2025-01-10T12:00:00Z"}]`,
+		"/projects/group%2Fapp/merge_requests/3/approvals":                   `{"approved_by": [{"user": {"username": "carol"}}]}`,
+		"/projects/group%2Fapp/merge_requests/3/commits?per_page=100&page=1": `[{"id": "c9"}]`,
+	})
+
+	p, err := hosting.New(hosting.KindGitLab, srv.URL, "group/app", "secret")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	prs, err := p.PullRequests(context.Background(), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("PullRequests: %v", err)
+	}
+	if got := headers.Get("PRIVATE-TOKEN"); got != "secret" {
+		t.Errorf("got PRIVATE-TOKEN %q, want secret", got)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("got %d merge requests, want 1", len(prs))
+	}
+	pr := prs[0]
+	if pr.Number != 3 || pr.State != "open" || !pr.MergedAt.IsZero() || len(pr.Commits) != 1 {
+		t.Errorf("got %+v", pr)
+	}
+	if len(pr.Reviews) != 2 {
+		t.Fatalf("got reviews %+v, want bob's comment and carol's approval", pr.Reviews)
+	}
+	if pr.Reviews[0].Reviewer != "bob" || !pr.Reviews[0].SubmittedAt.Equal(time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("got first review %+v", pr.Reviews[0])
+	}
+	if pr.Reviews[1].Reviewer != "carol" || pr.Reviews[1].State != "approved" {
+		t.Errorf("got second review %+v", pr.Reviews[1])
+	}
+}
+
+func TestPullRequestsCanceled(t *testing.T) {
+	srv, _ := mockAPI(t, nil)
+	p, err := hosting.New(hosting.KindGitHub, srv.URL, "acme/app", "")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.PullRequests(ctx, time.Time{}); err == nil {
+		t.Error("expected error for a canceled context")
+	}
+}
+
+func TestNewUnknownProvider(t *testing.T) {
+	if _, err := hosting.New("bitbucket", "", "acme/app", ""); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
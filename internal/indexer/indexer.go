@@ -1,12 +1,29 @@
 package indexer
 
 import (
+	"context"
+	"errors"
+	"path"
+	"sync"
+	"time"
+
+	"git-analytics/internal/complexity"
 	"git-analytics/internal/git"
+	"git-analytics/internal/gitattr"
+	"git-analytics/internal/gosrc"
+	"git-analytics/internal/language"
+	"git-analytics/internal/linguist"
 	"git-analytics/internal/store"
 )
 
 const batchSize = 500
 
+// hunkLimits bound hunk extraction per file and commit. Files over 1 MiB,
+// typically generated or data files, are not diffed at all; files with more
+// than 1000 hunks, typically reformatted wholesale, get a single hunk
+// spanning the changes.
+var hunkLimits = git.HunkLimits{MaxBytes: 1 << 20, MaxHunks: 1000}
+
 // Indexer is the data pipeline that reads commits from a git repository
 // and persists them into a store.
 type Indexer struct {
@@ -32,9 +49,13 @@ func (idx *Indexer) Index() error {
 		return err
 	}
 
-	// Nothing to do if HEAD hasn't changed.
+	// Nothing to index if HEAD hasn't changed, but databases written by an
+	// older version may lack HEAD's files and unchecked reverts.
 	if sinceHash == headHash {
-		return nil
+		if err := idx.indexHeadFiles(headHash); err != nil {
+			return err
+		}
+		return idx.store.DetectReverts()
 	}
 
 	iter, err := idx.repo.Log(sinceHash)
@@ -71,5 +92,431 @@ func (idx *Indexer) Index() error {
 		}
 	}
 
+	if err := idx.indexHeadFiles(headHash); err != nil {
+		return err
+	}
+	// Reverts are detected once the whole range is stored, since log order
+	// puts a revert before the commit it reverts.
+	if err := idx.store.DetectReverts(); err != nil {
+		return err
+	}
 	return idx.store.SetLastIndexedCommit(headHash)
 }
+
+// indexHeadFiles records the files in the tree of headHash.
+func (idx *Indexer) indexHeadFiles(headHash string) error {
+	files, err := idx.repo.TreeFiles(headHash)
+	if err != nil {
+		return err
+	}
+	return idx.store.SetHeadFiles(files)
+}
+
+// IndexHunks extracts the hunks of commits not processed by an earlier call,
+// up to HEAD, and writes them to the store. Hunk extraction is much slower
+// than Index, so it runs only when a repository opts in to it or an
+// analysis needs it.
+func (idx *Indexer) IndexHunks() error {
+	sinceHash, err := idx.store.GetLastHunkCommit()
+	if err != nil {
+		return err
+	}
+	headHash, err := idx.repo.HeadHash()
+	if err != nil {
+		return err
+	}
+	if sinceHash == headHash {
+		return nil
+	}
+
+	iter, err := idx.repo.LogHunks(sinceHash, hunkLimits)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	batch := make([]git.CommitHunks, 0, batchSize)
+	for {
+		c, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			break
+		}
+		batch = append(batch, *c)
+		if len(batch) >= batchSize {
+			if err := idx.store.InsertHunks(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		if err := idx.store.InsertHunks(batch); err != nil {
+			return err
+		}
+	}
+	return idx.store.SetLastHunkCommit(headHash)
+}
+
+// IndexBlame blames the text files of the indexed HEAD whose blobs have not
+// been blamed before and caches the results, as described in blameFiles.
+func (idx *Indexer) IndexBlame(workers int) error {
+	rev, err := idx.store.GetLastIndexedCommit()
+	if err != nil || rev == "" {
+		return err
+	}
+	files, err := idx.repo.TreeFiles(rev)
+	if err != nil {
+		return err
+	}
+	_, err = idx.blameFiles(context.Background(), rev, files, workers)
+	return err
+}
+
+// IndexFunctions parses the Go files of the indexed HEAD whose blobs have not
+// been parsed before and caches their function declarations. Files that do
+// not parse are cached with the parse error instead.
+func (idx *Indexer) IndexFunctions() error {
+	rev, err := idx.store.GetLastIndexedCommit()
+	if err != nil || rev == "" {
+		return err
+	}
+	files, err := idx.repo.TreeFiles(rev)
+	if err != nil {
+		return err
+	}
+	var goFiles []git.TreeFile
+	for _, f := range files {
+		if path.Ext(f.Path) == ".go" {
+			goFiles = append(goFiles, f)
+		}
+	}
+	if goFiles, err = idx.store.UnparsedFiles(goFiles); err != nil {
+		return err
+	}
+
+	for _, f := range goFiles {
+		src, err := idx.repo.ReadBlob(f.Blob, 0)
+		if err != nil {
+			return err
+		}
+		funcs, parseErr := gosrc.Funcs(src)
+		if err := idx.store.InsertFunctions(f, funcs, parseErr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IndexLanguages detects the language of every path in the history, unless
+// it was done for the indexed HEAD already. A linguist-language attribute in
+// the .gitattributes files at HEAD takes precedence; otherwise the language
+// is judged by the path (see language.FromPath) or, for extensionless files
+// at HEAD, by their shebang line.
+func (idx *Indexer) IndexLanguages() error {
+	rev, err := idx.store.GetLastIndexedCommit()
+	if err != nil || rev == "" {
+		return err
+	}
+	if done, err := idx.store.GetLastLanguageCommit(); err != nil || done == rev {
+		return err
+	}
+	attrs, blobs, err := idx.headAttributes(rev)
+	if err != nil {
+		return err
+	}
+	paths, err := idx.store.FilePaths()
+	if err != nil {
+		return err
+	}
+
+	langs := make(map[string]string, len(paths))
+	for _, p := range paths {
+		lang := ""
+		if v, ok := attrs.Value(p, "linguist-language"); ok && v != "true" && v != "false" {
+			lang = language.Canonical(v)
+		}
+		if lang == "" {
+			lang = language.FromPath(p)
+		}
+		if blob, ok := blobs[p]; ok && lang == "" && path.Ext(p) == "" {
+			src, err := idx.repo.ReadBlob(blob, 0)
+			if err != nil {
+				return err
+			}
+			lang = language.FromShebang(src)
+		}
+		if lang != "" {
+			langs[p] = lang
+		}
+	}
+	return idx.store.SetFileLanguages(langs, rev)
+}
+
+// IndexPathKinds classifies every path in the history as generated, vendored
+// or documentation (see linguist.Classify) by the .gitattributes files at
+// the indexed HEAD, unless it was done for that commit already.
+func (idx *Indexer) IndexPathKinds() error {
+	rev, err := idx.store.GetLastIndexedCommit()
+	if err != nil || rev == "" {
+		return err
+	}
+	if done, err := idx.store.GetLastPathKindCommit(); err != nil || done == rev {
+		return err
+	}
+	attrs, _, err := idx.headAttributes(rev)
+	if err != nil {
+		return err
+	}
+	paths, err := idx.store.FilePaths()
+	if err != nil {
+		return err
+	}
+
+	kinds := make(map[string]linguist.Kinds)
+	for _, p := range paths {
+		if k := linguist.Classify(p, attrs); k.Any() {
+			kinds[p] = k
+		}
+	}
+	return idx.store.SetPathKinds(kinds, rev)
+}
+
+// headAttributes reads the .gitattributes files in the tree of rev. It also
+// returns the blobs of the tree's files by path.
+func (idx *Indexer) headAttributes(rev string) (*gitattr.Matcher, map[string]string, error) {
+	files, err := idx.repo.TreeFiles(rev)
+	if err != nil {
+		return nil, nil, err
+	}
+	blobs := make(map[string]string, len(files))
+	attrFiles := make(map[string][]byte)
+	for _, f := range files {
+		blobs[f.Path] = f.Blob
+		if path.Base(f.Path) != ".gitattributes" {
+			continue
+		}
+		if attrFiles[f.Path], err = idx.repo.ReadBlob(f.Blob, 0); err != nil {
+			return nil, nil, err
+		}
+	}
+	return gitattr.New(attrFiles), blobs, nil
+}
+
+// metricsBatch is the number of files whose metrics are stored together.
+const metricsBatch = 500
+
+// maxMeasuredBytes is the size above which files, typically generated or
+// data files, are cached as empty rather than read and measured.
+const maxMeasuredBytes = 1 << 20
+
+// IndexComplexity measures the text files of the indexed HEAD whose blobs
+// have not been measured before and caches their metrics. Canceling ctx
+// stops it between files and returns ctx.Err(), keeping the metrics stored
+// so far.
+func (idx *Indexer) IndexComplexity(ctx context.Context) error {
+	rev, err := idx.store.GetLastIndexedCommit()
+	if err != nil || rev == "" {
+		return err
+	}
+	files, err := idx.repo.TreeFiles(rev)
+	if err != nil {
+		return err
+	}
+	return idx.measureFiles(ctx, files)
+}
+
+// IndexRevisions looks up the blobs of file revisions not seen before and
+// measures those not measured before, so that their metrics can be queried
+// by commit. The blobs are recorded only once measured, so a failed run
+// leaves no revision without metrics.
+func (idx *Indexer) IndexRevisions(revs []git.FileRevision) error {
+	revs, err := idx.store.UnresolvedRevisions(revs)
+	if err != nil {
+		return err
+	}
+
+	blobs := make(map[git.FileRevision]string, len(revs))
+	var files []git.TreeFile
+	seen := make(map[git.TreeFile]bool)
+	for _, rev := range revs {
+		blob, err := idx.repo.BlobAt(rev.Commit, rev.Path)
+		if err != nil {
+			return err
+		}
+		blobs[rev] = blob
+		f := git.TreeFile{Path: rev.Path, Blob: blob}
+		if blob != "" && !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+	if err := idx.measureFiles(context.Background(), files); err != nil {
+		return err
+	}
+	if len(blobs) == 0 {
+		return nil
+	}
+	return idx.store.InsertRevisionBlobs(blobs)
+}
+
+// measureFiles measures the text files among files whose blobs have not
+// been measured before and caches their metrics, until ctx is canceled.
+func (idx *Indexer) measureFiles(ctx context.Context, files []git.TreeFile) error {
+	files, err := idx.store.UnmeasuredFiles(files)
+	if err != nil {
+		return err
+	}
+
+	batch := make(map[git.TreeFile]complexity.Metrics, metricsBatch)
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			if len(batch) > 0 {
+				if err := idx.store.InsertMetrics(batch); err != nil {
+					return err
+				}
+			}
+			return err
+		}
+		src, err := idx.repo.ReadBlob(f.Blob, maxMeasuredBytes)
+		switch {
+		case errors.Is(err, git.ErrBlobTooLarge):
+			batch[f] = complexity.Metrics{}
+		case err != nil:
+			return err
+		default:
+			batch[f] = complexity.Measure(f.Path, src)
+		}
+		if len(batch) == metricsBatch {
+			if err := idx.store.InsertMetrics(batch); err != nil {
+				return err
+			}
+			clear(batch)
+		}
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+	return idx.store.InsertMetrics(batch)
+}
+
+// IndexSurvival samples the lines alive at the end of every calendar month
+// (UTC) from the first indexed commit's month up to the last month ending
+// before now, skipping months sampled by earlier runs. Each sample blames the
+// tree of the last first-parent commit authored before the month ends,
+// reusing the cached blames of blobs blamed before, so only files changed
+// since an earlier sample are blamed again. A month with files that fail to
+// blame is not recorded, so that it is sampled again by the next run. Samples
+// are stored as they complete, so a failed or canceled run keeps the months
+// already sampled; canceling ctx stops the run between blames and returns
+// ctx.Err().
+func (idx *Indexer) IndexSurvival(ctx context.Context, workers int, now time.Time) error {
+	first, err := idx.store.FirstCommitTime()
+	if err != nil || first.IsZero() {
+		return err
+	}
+	sampled, err := idx.store.SurvivalSamples()
+	if err != nil {
+		return err
+	}
+	done := make(map[string]bool, len(sampled))
+	for _, m := range sampled {
+		done[m] = true
+	}
+
+	month := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for end := month.AddDate(0, 1, 0); !end.After(now); month, end = end, end.AddDate(0, 1, 0) {
+		label := month.Format("2006-01")
+		if done[label] {
+			continue
+		}
+		rev, err := idx.repo.FirstParentBefore(end)
+		if err != nil {
+			return err
+		}
+		var files []git.TreeFile
+		if rev != "" {
+			if files, err = idx.repo.TreeFiles(rev); err != nil {
+				return err
+			}
+			failed, err := idx.blameFiles(ctx, rev, files, workers)
+			if err != nil {
+				return err
+			}
+			// A canceled or failed blame leaves files unblamed, which must
+			// not be recorded as dead lines.
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if failed > 0 {
+				continue
+			}
+		}
+		if err := idx.store.InsertSurvivalSample(label, rev, files); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blameFiles blames those of files in the tree of rev whose blobs have not
+// been blamed before, running up to workers blames at once, and caches the
+// results. Files that fail to blame are skipped, so they are tried again by
+// the next run, and counted in failed; only errors storing the results are
+// returned. Once ctx is canceled, no further files are blamed.
+func (idx *Indexer) blameFiles(ctx context.Context, rev string, files []git.TreeFile, workers int) (failed int, err error) {
+	files, err = idx.store.UnblamedFiles(files)
+	if err != nil {
+		return 0, err
+	}
+
+	type blamed struct {
+		file    git.TreeFile
+		entries []git.BlameEntry
+		err     error
+	}
+	queue := make(chan git.TreeFile)
+	results := make(chan blamed)
+
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range queue {
+				entries, err := idx.repo.Blame(rev, f.Path)
+				results <- blamed{file: f, entries: entries, err: err}
+			}
+		}()
+	}
+	go func() {
+	feed:
+		for _, f := range files {
+			select {
+			case queue <- f:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+		close(queue)
+		wg.Wait()
+		close(results)
+	}()
+
+	// Results are stored from this goroutine only, as the store serializes
+	// writes anyway. After a store error the remaining results are drained so
+	// that the workers can finish.
+	var storeErr error
+	for r := range results {
+		if r.err != nil {
+			failed++
+		}
+		if storeErr != nil || r.err != nil {
+			continue
+		}
+		storeErr = idx.store.InsertBlame(r.file, r.entries)
+	}
+	return failed, storeErr
+}
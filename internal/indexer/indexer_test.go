@@ -1,18 +1,30 @@
 package indexer_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"git-analytics/internal/complexity"
 	"git-analytics/internal/git"
+	"git-analytics/internal/gosrc"
+	"git-analytics/internal/hosting"
 	"git-analytics/internal/indexer"
+	"git-analytics/internal/issues"
+	"git-analytics/internal/linguist"
 )
 
 // fakeRepo implements git.Repository for testing.
 type fakeRepo struct {
 	headHash string
 	commits  []git.Commit
+	trees    map[string][]git.TreeFile // by rev; one file.go by default
+	blobs    map[string]string
+	revBlobs map[git.FileRevision]string // BlobAt results
+	badBlame map[string]bool             // paths whose blame fails
 }
 
 func (r *fakeRepo) HeadHash() (string, error) {
@@ -30,6 +42,59 @@ func (r *fakeRepo) Log(sinceHash string) (git.CommitIter, error) {
 	return &fakeIter{commits: filtered}, nil
 }
 
+func (r *fakeRepo) LogHunks(sinceHash string, limits git.HunkLimits) (git.HunkIter, error) {
+	var hunks []git.CommitHunks
+	for _, c := range r.commits {
+		if sinceHash != "" && c.Hash == sinceHash {
+			break
+		}
+		ch := git.CommitHunks{Hash: c.Hash}
+		for _, f := range c.FilesChanged {
+			ch.Hunks = append(ch.Hunks, git.Hunk{Path: f.Path, NewStart: 1, NewLines: f.Additions})
+		}
+		hunks = append(hunks, ch)
+	}
+	return &fakeHunkIter{hunks: hunks}, nil
+}
+
+func (r *fakeRepo) TreeFiles(rev string) ([]git.TreeFile, error) {
+	if files, ok := r.trees[rev]; ok {
+		return files, nil
+	}
+	return []git.TreeFile{{Path: "file.go", Blob: "b1"}}, nil
+}
+
+func (r *fakeRepo) FirstParentBefore(t time.Time) (string, error) {
+	for _, c := range r.commits {
+		if c.Date.Before(t) {
+			return c.Hash, nil
+		}
+	}
+	return "", nil
+}
+
+func (r *fakeRepo) Blame(rev, path string) ([]git.BlameEntry, error) {
+	if r.badBlame[path] {
+		return nil, fmt.Errorf("blame %s failed", path)
+	}
+	return []git.BlameEntry{{Commit: rev, AuthorEmail: "test@example.com", Lines: len(path)}}, nil
+}
+
+func (r *fakeRepo) ReadBlob(hash string, maxBytes int64) ([]byte, error) {
+	src, ok := r.blobs[hash]
+	if !ok {
+		return nil, fmt.Errorf("blob %s not found", hash)
+	}
+	if maxBytes > 0 && int64(len(src)) > maxBytes {
+		return nil, git.ErrBlobTooLarge
+	}
+	return []byte(src), nil
+}
+
+func (r *fakeRepo) BlobAt(rev, path string) (string, error) {
+	return r.revBlobs[git.FileRevision{Commit: rev, Path: path}], nil
+}
+
 func (r *fakeRepo) RepoName() string      { return "fake-repo" }
 func (r *fakeRepo) CurrentBranch() string { return "main" }
 func (r *fakeRepo) Close() error          { return nil }
@@ -51,11 +116,43 @@ func (it *fakeIter) Next() (*git.Commit, error) {
 
 func (it *fakeIter) Close() {}
 
+// fakeHunkIter implements git.HunkIter for testing.
+type fakeHunkIter struct {
+	hunks []git.CommitHunks
+	pos   int
+}
+
+func (it *fakeHunkIter) Next() (*git.CommitHunks, error) {
+	if it.pos >= len(it.hunks) {
+		return nil, nil
+	}
+	c := it.hunks[it.pos]
+	it.pos++
+	return &c, nil
+}
+
+func (it *fakeHunkIter) Close() {}
+
 // fakeStore implements store.Store for testing.
 type fakeStore struct {
 	lastIndexed     string
 	insertedBatches [][]git.Commit
 	initCalled      bool
+	revertChecks    int
+	headFiles       []git.TreeFile
+	blamed          map[git.TreeFile][]git.BlameEntry
+	survival        map[string][]git.TreeFile
+	lastHunk        string
+	hunks           []git.CommitHunks
+	functions       map[git.TreeFile][]gosrc.Func
+	parseErrors     map[git.TreeFile]error
+	metrics         map[git.TreeFile]complexity.Metrics
+	revisions       map[git.FileRevision]string
+	paths           []string
+	languages       map[string]string
+	lastLanguage    string
+	kinds           map[string]linguist.Kinds
+	lastPathKind    string
 }
 
 func (s *fakeStore) Init() error {
@@ -79,6 +176,170 @@ func (s *fakeStore) SetLastIndexedCommit(hash string) error {
 	return nil
 }
 
+func (s *fakeStore) InsertHunks(commits []git.CommitHunks) error {
+	s.hunks = append(s.hunks, commits...)
+	return nil
+}
+
+func (s *fakeStore) GetLastHunkCommit() (string, error) {
+	return s.lastHunk, nil
+}
+
+func (s *fakeStore) SetLastHunkCommit(hash string) error {
+	s.lastHunk = hash
+	return nil
+}
+
+func (s *fakeStore) SetHeadFiles(files []git.TreeFile) error {
+	s.headFiles = files
+	return nil
+}
+
+func (s *fakeStore) UnblamedFiles(files []git.TreeFile) ([]git.TreeFile, error) {
+	var unblamed []git.TreeFile
+	for _, f := range files {
+		if _, ok := s.blamed[f]; !ok {
+			unblamed = append(unblamed, f)
+		}
+	}
+	return unblamed, nil
+}
+
+func (s *fakeStore) InsertBlame(file git.TreeFile, entries []git.BlameEntry) error {
+	if s.blamed == nil {
+		s.blamed = make(map[git.TreeFile][]git.BlameEntry)
+	}
+	s.blamed[file] = entries
+	return nil
+}
+
+func (s *fakeStore) UnparsedFiles(files []git.TreeFile) ([]git.TreeFile, error) {
+	var unparsed []git.TreeFile
+	for _, f := range files {
+		if _, ok := s.functions[f]; !ok {
+			unparsed = append(unparsed, f)
+		}
+	}
+	return unparsed, nil
+}
+
+func (s *fakeStore) InsertFunctions(file git.TreeFile, funcs []gosrc.Func, parseErr error) error {
+	if s.functions == nil {
+		s.functions = make(map[git.TreeFile][]gosrc.Func)
+	}
+	s.functions[file] = funcs
+	if parseErr != nil {
+		if s.parseErrors == nil {
+			s.parseErrors = make(map[git.TreeFile]error)
+		}
+		s.parseErrors[file] = parseErr
+	}
+	return nil
+}
+
+func (s *fakeStore) UnmeasuredFiles(files []git.TreeFile) ([]git.TreeFile, error) {
+	var unmeasured []git.TreeFile
+	for _, f := range files {
+		if _, ok := s.metrics[f]; !ok {
+			unmeasured = append(unmeasured, f)
+		}
+	}
+	return unmeasured, nil
+}
+
+func (s *fakeStore) InsertMetrics(metrics map[git.TreeFile]complexity.Metrics) error {
+	if s.metrics == nil {
+		s.metrics = make(map[git.TreeFile]complexity.Metrics)
+	}
+	for f, m := range metrics {
+		s.metrics[f] = m
+	}
+	return nil
+}
+
+func (s *fakeStore) UnresolvedRevisions(revs []git.FileRevision) ([]git.FileRevision, error) {
+	var unresolved []git.FileRevision
+	for _, r := range revs {
+		if _, ok := s.revisions[r]; !ok {
+			unresolved = append(unresolved, r)
+		}
+	}
+	return unresolved, nil
+}
+
+func (s *fakeStore) InsertRevisionBlobs(blobs map[git.FileRevision]string) error {
+	if s.revisions == nil {
+		s.revisions = make(map[git.FileRevision]string)
+	}
+	for r, blob := range blobs {
+		s.revisions[r] = blob
+	}
+	return nil
+}
+
+func (s *fakeStore) FilePaths() ([]string, error) {
+	return s.paths, nil
+}
+
+func (s *fakeStore) SetFileLanguages(langs map[string]string, rev string) error {
+	s.languages, s.lastLanguage = langs, rev
+	return nil
+}
+
+func (s *fakeStore) GetLastLanguageCommit() (string, error) {
+	return s.lastLanguage, nil
+}
+
+func (s *fakeStore) SetPathKinds(kinds map[string]linguist.Kinds, rev string) error {
+	s.kinds, s.lastPathKind = kinds, rev
+	return nil
+}
+
+func (s *fakeStore) GetLastPathKindCommit() (string, error) {
+	return s.lastPathKind, nil
+}
+
+func (s *fakeStore) FirstCommitTime() (time.Time, error) {
+	var first time.Time
+	for _, batch := range s.insertedBatches {
+		for _, c := range batch {
+			if first.IsZero() || c.Date.Before(first) {
+				first = c.Date
+			}
+		}
+	}
+	return first, nil
+}
+
+func (s *fakeStore) SurvivalSamples() ([]string, error) {
+	var months []string
+	for m := range s.survival {
+		months = append(months, m)
+	}
+	return months, nil
+}
+
+func (s *fakeStore) InsertSurvivalSample(month, rev string, files []git.TreeFile) error {
+	if s.survival == nil {
+		s.survival = make(map[string][]git.TreeFile)
+	}
+	s.survival[month] = files
+	return nil
+}
+
+func (s *fakeStore) DetectReverts() error {
+	s.revertChecks++
+	return nil
+}
+
+func (s *fakeStore) LinkIssues(e *issues.Extractor) error { return nil }
+
+func (s *fakeStore) ImportIssues(list []issues.Issue) error { return nil }
+
+func (s *fakeStore) ImportPullRequests(prs []hosting.PullRequest) error { return nil }
+
+func (s *fakeStore) GetLastPullRequestUpdate() (time.Time, error) { return time.Time{}, nil }
+
 func (s *fakeStore) Close() error { return nil }
 
 func TestIndexFullRepo(t *testing.T) {
@@ -107,6 +368,14 @@ func TestIndexFullRepo(t *testing.T) {
 	if store.lastIndexed != commits[0].Hash {
 		t.Errorf("expected last indexed %q, got %q", commits[0].Hash, store.lastIndexed)
 	}
+
+	if store.revertChecks != 1 {
+		t.Errorf("expected reverts detected once, got %d", store.revertChecks)
+	}
+
+	if len(store.headFiles) != 1 || store.headFiles[0].Path != "file.go" {
+		t.Errorf("expected HEAD files [file.go], got %+v", store.headFiles)
+	}
 }
 
 func TestIndexIncremental(t *testing.T) {
@@ -155,6 +424,394 @@ func TestIndexNoOp(t *testing.T) {
 	}
 }
 
+func TestIndexHunks(t *testing.T) {
+	commits := makeCommits(3)
+	repo := &fakeRepo{headHash: commits[0].Hash, commits: commits}
+	store := &fakeStore{lastHunk: commits[2].Hash}
+
+	idx := indexer.New(repo, store)
+	if err := idx.IndexHunks(); err != nil {
+		t.Fatalf("IndexHunks: %v", err)
+	}
+	if len(store.hunks) != 2 || store.hunks[0].Hash != commits[0].Hash || store.hunks[1].Hash != commits[1].Hash {
+		t.Fatalf("expected hunks of the 2 new commits, got %+v", store.hunks)
+	}
+	if store.lastHunk != commits[0].Hash {
+		t.Errorf("expected last hunk commit %s, got %s", commits[0].Hash, store.lastHunk)
+	}
+
+	// Nothing new to extract.
+	if err := idx.IndexHunks(); err != nil {
+		t.Fatalf("IndexHunks: %v", err)
+	}
+	if len(store.hunks) != 2 {
+		t.Errorf("expected no more hunks, got %d commits", len(store.hunks))
+	}
+}
+
+func TestIndexBlame(t *testing.T) {
+	commits := makeCommits(1)
+	repo := &fakeRepo{
+		headHash: commits[0].Hash,
+		commits:  commits,
+		trees:    map[string][]git.TreeFile{commits[0].Hash: {{Path: "a.go"}, {Path: "dir/b.go"}, {Path: "c.go"}}},
+	}
+	store := &fakeStore{
+		lastIndexed: commits[0].Hash,
+		blamed:      map[git.TreeFile][]git.BlameEntry{{Path: "c.go"}: nil},
+	}
+
+	idx := indexer.New(repo, store)
+	if err := idx.IndexBlame(2); err != nil {
+		t.Fatalf("IndexBlame: %v", err)
+	}
+
+	if len(store.blamed) != 3 {
+		t.Fatalf("expected 3 blamed files, got %d", len(store.blamed))
+	}
+	if got := store.blamed[git.TreeFile{Path: "dir/b.go"}]; len(got) != 1 || got[0].Commit != commits[0].Hash || got[0].Lines != 8 {
+		t.Errorf("dir/b.go: got %+v, want blame at HEAD", got)
+	}
+	if store.blamed[git.TreeFile{Path: "c.go"}] != nil {
+		t.Errorf("expected c.go to keep its cached blame")
+	}
+}
+
+func TestIndexBlame_SkipsFailedFiles(t *testing.T) {
+	commits := makeCommits(1)
+	repo := &fakeRepo{
+		headHash: commits[0].Hash,
+		commits:  commits,
+		trees:    map[string][]git.TreeFile{commits[0].Hash: {{Path: "a.go"}, {Path: "bad.go"}, {Path: "c.go"}}},
+		badBlame: map[string]bool{"bad.go": true},
+	}
+	store := &fakeStore{lastIndexed: commits[0].Hash}
+
+	idx := indexer.New(repo, store)
+	if err := idx.IndexBlame(2); err != nil {
+		t.Fatalf("IndexBlame: %v", err)
+	}
+
+	if len(store.blamed) != 2 {
+		t.Fatalf("expected 2 blamed files, got %d", len(store.blamed))
+	}
+	if _, ok := store.blamed[git.TreeFile{Path: "bad.go"}]; ok {
+		t.Errorf("expected bad.go to be left unblamed")
+	}
+}
+
+func TestIndexFunctions(t *testing.T) {
+	commits := makeCommits(1)
+	repo := &fakeRepo{
+		headHash: commits[0].Hash,
+		commits:  commits,
+		trees: map[string][]git.TreeFile{commits[0].Hash: {
+			{Path: "a.go", Blob: "a1"}, {Path: "bad.go", Blob: "x1"}, {Path: "c.go", Blob: "c1"}, {Path: "README.md", Blob: "r1"},
+		}},
+		blobs: map[string]string{
+			"a1": "package a\n\nfunc F() {\n}\n",
+			"x1": "package bad\n\nfunc {\n",
+		},
+	}
+	store := &fakeStore{
+		lastIndexed: commits[0].Hash,
+		functions:   map[git.TreeFile][]gosrc.Func{{Path: "c.go", Blob: "c1"}: nil},
+	}
+
+	idx := indexer.New(repo, store)
+	if err := idx.IndexFunctions(); err != nil {
+		t.Fatalf("IndexFunctions: %v", err)
+	}
+
+	if len(store.functions) != 3 {
+		t.Fatalf("expected 3 parsed files, got %v", store.functions)
+	}
+	want := gosrc.Func{Name: "F", StartLine: 3, EndLine: 4}
+	if got := store.functions[git.TreeFile{Path: "a.go", Blob: "a1"}]; len(got) != 1 || got[0] != want {
+		t.Errorf("a.go: got %+v, want %+v", got, want)
+	}
+	// A file that does not parse is cached without functions but with the
+	// parse error.
+	bad := git.TreeFile{Path: "bad.go", Blob: "x1"}
+	if got, ok := store.functions[bad]; !ok || len(got) != 0 {
+		t.Errorf("bad.go: got %+v, %v, want cached without functions", got, ok)
+	}
+	if store.parseErrors[bad] == nil || len(store.parseErrors) != 1 {
+		t.Errorf("got parse errors %v, want one for bad.go", store.parseErrors)
+	}
+}
+
+func TestIndexComplexity(t *testing.T) {
+	commits := makeCommits(1)
+	repo := &fakeRepo{
+		headHash: commits[0].Hash,
+		commits:  commits,
+		trees: map[string][]git.TreeFile{commits[0].Hash: {
+			{Path: "a.go", Blob: "a1"}, {Path: "notes.txt", Blob: "n1"}, {Path: "c.go", Blob: "c1"},
+			{Path: "data.json", Blob: "d1"},
+		}},
+		blobs: map[string]string{
+			"a1": "package a\n\nfunc F() {\n\treturn\n}\n",
+			"n1": "one\ntwo\n",
+			"d1": strings.Repeat("x", 1<<20+1),
+		},
+	}
+	cached := complexity.Metrics{Lines: 99}
+	store := &fakeStore{
+		lastIndexed: commits[0].Hash,
+		metrics:     map[git.TreeFile]complexity.Metrics{{Path: "c.go", Blob: "c1"}: cached},
+	}
+
+	idx := indexer.New(repo, store)
+	if err := idx.IndexComplexity(context.Background()); err != nil {
+		t.Fatalf("IndexComplexity: %v", err)
+	}
+
+	if len(store.metrics) != 4 {
+		t.Fatalf("expected 4 measured files, got %v", store.metrics)
+	}
+	// A file over the size limit is cached as empty.
+	if got, ok := store.metrics[git.TreeFile{Path: "data.json", Blob: "d1"}]; !ok || got != (complexity.Metrics{}) {
+		t.Errorf("data.json: got %+v, %v, want cached as empty", got, ok)
+	}
+	want := complexity.Metrics{Lines: 5, Code: 4, Blank: 1, Indent: 1, MaxIndent: 1, Cyclomatic: 1}
+	if got := store.metrics[git.TreeFile{Path: "a.go", Blob: "a1"}]; got != want {
+		t.Errorf("a.go: got %+v, want %+v", got, want)
+	}
+	if got := store.metrics[git.TreeFile{Path: "c.go", Blob: "c1"}]; got != cached {
+		t.Errorf("expected c.go to keep its cached metrics, got %+v", got)
+	}
+}
+
+func TestIndexRevisions(t *testing.T) {
+	repo := &fakeRepo{
+		revBlobs: map[git.FileRevision]string{
+			{Commit: "c1", Path: "a.go"}: "a1",
+			{Commit: "c2", Path: "a.go"}: "a1",
+			{Commit: "c3", Path: "b.go"}: "b1",
+		},
+		blobs: map[string]string{"a1": "package a\n", "b1": "package b\n\nfunc F() {}\n"},
+	}
+	store := &fakeStore{
+		revisions: map[git.FileRevision]string{{Commit: "c3", Path: "b.go"}: "b1"},
+	}
+
+	idx := indexer.New(repo, store)
+	revs := []git.FileRevision{
+		{Commit: "c1", Path: "a.go"}, {Commit: "c2", Path: "a.go"},
+		{Commit: "c3", Path: "b.go"}, {Commit: "c4", Path: "a.go"},
+	}
+	if err := idx.IndexRevisions(revs); err != nil {
+		t.Fatalf("IndexRevisions: %v", err)
+	}
+
+	want := map[git.FileRevision]string{
+		{Commit: "c1", Path: "a.go"}: "a1",
+		{Commit: "c2", Path: "a.go"}: "a1",
+		{Commit: "c3", Path: "b.go"}: "b1",
+		{Commit: "c4", Path: "a.go"}: "",
+	}
+	if len(store.revisions) != len(want) {
+		t.Fatalf("got revisions %v, want %v", store.revisions, want)
+	}
+	for r, blob := range want {
+		if store.revisions[r] != blob {
+			t.Errorf("%+v: got blob %q, want %q", r, store.revisions[r], blob)
+		}
+	}
+	// The blob shared by c1 and c2 is measured once; b.go's revision was
+	// resolved before.
+	if len(store.metrics) != 1 || store.metrics[git.TreeFile{Path: "a.go", Blob: "a1"}].Code != 1 {
+		t.Errorf("expected only a.go's blob measured, got %v", store.metrics)
+	}
+}
+
+func TestIndexLanguages(t *testing.T) {
+	commits := makeCommits(1)
+	repo := &fakeRepo{
+		headHash: commits[0].Hash,
+		commits:  commits,
+		trees: map[string][]git.TreeFile{commits[0].Hash: {
+			{Path: ".gitattributes", Blob: "ga"}, {Path: "main.go", Blob: "m1"},
+			{Path: "bin/tool", Blob: "t1"}, {Path: "LICENSE", Blob: "l1"}, {Path: "templates/page.tmpl", Blob: "p1"},
+		}},
+		blobs: map[string]string{
+			"ga": "*.tmpl linguist-language=html\n",
+			"t1": "#!/usr/bin/env python3\nprint()\n",
+			"l1": "MIT License\n",
+		},
+	}
+	store := &fakeStore{
+		lastIndexed: commits[0].Hash,
+		paths:       []string{".gitattributes", "main.go", "bin/tool", "LICENSE", "templates/page.tmpl", "old/script.rb", "old/tool"},
+	}
+
+	idx := indexer.New(repo, store)
+	if err := idx.IndexLanguages(); err != nil {
+		t.Fatalf("IndexLanguages: %v", err)
+	}
+
+	want := map[string]string{
+		"main.go":             "Go",
+		"bin/tool":            "Python",
+		"templates/page.tmpl": "HTML",
+		"old/script.rb":       "Ruby",
+	}
+	if len(store.languages) != len(want) {
+		t.Fatalf("got languages %v, want %v", store.languages, want)
+	}
+	for p, lang := range want {
+		if store.languages[p] != lang {
+			t.Errorf("%s: got %q, want %q", p, store.languages[p], lang)
+		}
+	}
+	if store.lastLanguage != commits[0].Hash {
+		t.Errorf("got last language commit %q, want %q", store.lastLanguage, commits[0].Hash)
+	}
+
+	// Nothing to do until HEAD moves.
+	store.languages = nil
+	if err := idx.IndexLanguages(); err != nil {
+		t.Fatalf("IndexLanguages: %v", err)
+	}
+	if store.languages != nil {
+		t.Errorf("expected languages to be kept, got %v", store.languages)
+	}
+}
+
+func TestIndexPathKinds(t *testing.T) {
+	commits := makeCommits(1)
+	repo := &fakeRepo{
+		headHash: commits[0].Hash,
+		commits:  commits,
+		trees: map[string][]git.TreeFile{commits[0].Hash: {
+			{Path: ".gitattributes", Blob: "ga"}, {Path: "main.go", Blob: "m1"},
+		}},
+		blobs: map[string]string{"ga": "gen/** linguist-generated\nvendor/ours/** -linguist-vendored\n"},
+	}
+	store := &fakeStore{
+		lastIndexed: commits[0].Hash,
+		paths:       []string{"main.go", "gen/api.go", "vendor/lib/x.go", "vendor/ours/y.go", "api.pb.go", "README.md"},
+	}
+
+	idx := indexer.New(repo, store)
+	if err := idx.IndexPathKinds(); err != nil {
+		t.Fatalf("IndexPathKinds: %v", err)
+	}
+
+	want := map[string]linguist.Kinds{
+		"gen/api.go":      {Generated: true},
+		"vendor/lib/x.go": {Vendored: true},
+		"api.pb.go":       {Generated: true},
+		"README.md":       {Documentation: true},
+	}
+	if len(store.kinds) != len(want) {
+		t.Fatalf("got kinds %v, want %v", store.kinds, want)
+	}
+	for p, k := range want {
+		if store.kinds[p] != k {
+			t.Errorf("%s: got %+v, want %+v", p, store.kinds[p], k)
+		}
+	}
+	if store.lastPathKind != commits[0].Hash {
+		t.Errorf("got last path kind commit %q, want %q", store.lastPathKind, commits[0].Hash)
+	}
+}
+
+func TestIndexSurvival(t *testing.T) {
+	commits := []git.Commit{
+		{Hash: "c3", Date: time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{Hash: "c2", Date: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{Hash: "c1", Date: time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	repo := &fakeRepo{
+		headHash: "c3",
+		commits:  commits,
+		trees: map[string][]git.TreeFile{
+			"c1": {{Path: "a.go", Blob: "a1"}},
+			"c2": {{Path: "a.go", Blob: "a2"}},
+		},
+	}
+	store := &fakeStore{
+		insertedBatches: [][]git.Commit{commits},
+		survival:        map[string][]git.TreeFile{"2024-12": {{Path: "a.go", Blob: "a1"}}},
+	}
+
+	idx := indexer.New(repo, store)
+	if err := idx.IndexSurvival(context.Background(), 2, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("IndexSurvival: %v", err)
+	}
+
+	// December was sampled before and March has not ended yet.
+	want := map[string]string{"2024-12": "a1", "2025-01": "a2", "2025-02": "a2"}
+	if len(store.survival) != len(want) {
+		t.Fatalf("got samples %v, want months %v", store.survival, want)
+	}
+	for month, blob := range want {
+		if files := store.survival[month]; len(files) != 1 || files[0].Blob != blob {
+			t.Errorf("%s: got %+v, want a.go at %s", month, files, blob)
+		}
+	}
+	// The blob shared by January and February is blamed once.
+	if len(store.blamed) != 1 {
+		t.Errorf("got %d blamed blobs, want 1", len(store.blamed))
+	}
+	if got := store.blamed[git.TreeFile{Path: "a.go", Blob: "a2"}]; len(got) != 1 || got[0].Commit != "c2" {
+		t.Errorf("a.go: got %+v, want blame at c2", got)
+	}
+}
+
+func TestIndexSurvival_Canceled(t *testing.T) {
+	commits := []git.Commit{
+		{Hash: "c2", Date: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{Hash: "c1", Date: time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	repo := &fakeRepo{headHash: "c2", commits: commits}
+	store := &fakeStore{insertedBatches: [][]git.Commit{commits}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	idx := indexer.New(repo, store)
+	if err := idx.IndexSurvival(ctx, 2, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	if len(store.survival) != 0 {
+		t.Errorf("got samples %v, want none after cancellation", store.survival)
+	}
+}
+
+func TestIndexSurvival_FailedBlame(t *testing.T) {
+	commits := []git.Commit{
+		{Hash: "c1", Date: time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	repo := &fakeRepo{
+		headHash: "c1",
+		commits:  commits,
+		trees:    map[string][]git.TreeFile{"c1": {{Path: "a.go", Blob: "a1"}, {Path: "bad.go", Blob: "b1"}}},
+		badBlame: map[string]bool{"bad.go": true},
+	}
+	store := &fakeStore{insertedBatches: [][]git.Commit{commits}}
+	now := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	idx := indexer.New(repo, store)
+	if err := idx.IndexSurvival(context.Background(), 2, now); err != nil {
+		t.Fatalf("IndexSurvival: %v", err)
+	}
+	// bad.go's lines would count as dead, so December is left unsampled.
+	if len(store.survival) != 0 {
+		t.Fatalf("got samples %v, want none while bad.go fails to blame", store.survival)
+	}
+
+	// Once bad.go blames, the next run samples December.
+	repo.badBlame = nil
+	if err := idx.IndexSurvival(context.Background(), 2, now); err != nil {
+		t.Fatalf("IndexSurvival: %v", err)
+	}
+	if files := store.survival["2024-12"]; len(files) != 2 {
+		t.Errorf("2024-12: got %+v, want a.go and bad.go", files)
+	}
+}
+
 // makeCommits creates n fake commits in reverse chronological order.
 func makeCommits(n int) []git.Commit {
 	commits := make([]git.Commit, n)
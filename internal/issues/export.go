@@ -0,0 +1,195 @@
+package issues
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Issue holds the tracker metadata of one issue. Zero times mean unknown.
+type Issue struct {
+	Key        string
+	Type       string // e.g. "Bug", "Story"
+	Title      string
+	Status     string
+	CreatedAt  time.Time
+	ResolvedAt time.Time
+}
+
+// ParseExport reads an issue export. Supported formats are a Jira CSV export,
+// a Jira search result in JSON ({"issues": [...]}) and a GitHub issue list
+// in JSON, as written by `gh issue list --json
+// number,title,state,labels,createdAt,closedAt`. GitHub issues are keyed
+// "#<number>" to match commit references.
+func ParseExport(data []byte) ([]Issue, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) == 0:
+		return nil, nil
+	case trimmed[0] == '[':
+		return parseGitHubJSON(trimmed)
+	case trimmed[0] == '{':
+		return parseJiraJSON(trimmed)
+	}
+	return parseJiraCSV(trimmed)
+}
+
+type githubIssue struct {
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	State     string `json:"state"`
+	CreatedAt string `json:"createdAt"`
+	ClosedAt  string `json:"closedAt"`
+	Labels    []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+func parseGitHubJSON(data []byte) ([]Issue, error) {
+	var raw []githubIssue
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing GitHub export: %w", err)
+	}
+	result := make([]Issue, 0, len(raw))
+	for _, r := range raw {
+		if r.Number == 0 {
+			continue
+		}
+		issue := Issue{
+			Key:        "#" + strconv.Itoa(r.Number),
+			Title:      r.Title,
+			Status:     strings.ToLower(r.State),
+			CreatedAt:  parseTime(r.CreatedAt),
+			ResolvedAt: parseTime(r.ClosedAt),
+		}
+		// GitHub has no issue types; take one from conventional labels.
+		for _, l := range r.Labels {
+			name := strings.ToLower(l.Name)
+			if strings.Contains(name, "bug") {
+				issue.Type = "Bug"
+				break
+			}
+			if name == "enhancement" || name == "feature" {
+				issue.Type = "Feature"
+			}
+		}
+		result = append(result, issue)
+	}
+	return result, nil
+}
+
+type jiraSearch struct {
+	Issues []struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary   string `json:"summary"`
+			IssueType struct {
+				Name string `json:"name"`
+			} `json:"issuetype"`
+			Status struct {
+				Name string `json:"name"`
+			} `json:"status"`
+			Created        string `json:"created"`
+			ResolutionDate string `json:"resolutiondate"`
+		} `json:"fields"`
+	} `json:"issues"`
+}
+
+func parseJiraJSON(data []byte) ([]Issue, error) {
+	var raw jiraSearch
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing Jira export: %w", err)
+	}
+	result := make([]Issue, 0, len(raw.Issues))
+	for _, r := range raw.Issues {
+		if r.Key == "" {
+			continue
+		}
+		result = append(result, Issue{
+			Key:        r.Key,
+			Type:       r.Fields.IssueType.Name,
+			Title:      r.Fields.Summary,
+			Status:     r.Fields.Status.Name,
+			CreatedAt:  parseTime(r.Fields.Created),
+			ResolvedAt: parseTime(r.Fields.ResolutionDate),
+		})
+	}
+	return result, nil
+}
+
+func parseJiraCSV(data []byte) ([]Issue, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	// Jira repeats columns such as "Labels" and "Sprint".
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parsing Jira CSV export: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	col := make(map[string]int)
+	for i, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := col[name]; !ok {
+			col[name] = i
+		}
+	}
+	keyCol, ok := col["issue key"]
+	if !ok {
+		return nil, fmt.Errorf("parsing Jira CSV export: no %q column", "Issue key")
+	}
+	field := func(record []string, name string) string {
+		i, ok := col[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	result := make([]Issue, 0, len(records)-1)
+	for _, record := range records[1:] {
+		if keyCol >= len(record) || strings.TrimSpace(record[keyCol]) == "" {
+			continue
+		}
+		result = append(result, Issue{
+			Key:        strings.TrimSpace(record[keyCol]),
+			Type:       field(record, "issue type"),
+			Title:      field(record, "summary"),
+			Status:     field(record, "status"),
+			CreatedAt:  parseTime(field(record, "created")),
+			ResolvedAt: parseTime(field(record, "resolved")),
+		})
+	}
+	return result, nil
+}
+
+// timeLayouts are the timestamp formats found in Jira and GitHub exports.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05.000-0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"02/Jan/06 3:04 PM",
+	"02/Jan/06 15:04",
+	"2006-01-02",
+}
+
+// parseTime parses s with the first matching layout, returning the zero
+// time if none matches.
+func parseTime(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
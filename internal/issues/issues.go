@@ -0,0 +1,91 @@
+// Package issues extracts issue-tracker references from commit messages and
+// reads issue exports from Jira and GitHub.
+package issues
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultPatterns match Jira-style keys ("PROJ-1234") and GitHub/GitLab
+// references ("#567").
+var DefaultPatterns = []string{
+	`\b[A-Z][A-Z0-9]+-[0-9]+\b`,
+	`(?:^|[^\w&/])(#[0-9]+)\b`,
+}
+
+// NonIssuePrefixes are prefixes of standard, encoding and license names such
+// as "UTF-8", "SHA-256" and "ISO-8601" that look like Jira keys. Keys with
+// these prefixes are ignored when DefaultPatterns are in use; configure
+// patterns naming the project keys to match them anyway.
+var NonIssuePrefixes = []string{
+	"AES", "ASCII", "BASE", "CP", "CVE", "CWE", "ECMA", "ES", "GPL", "HTTP",
+	"IEC", "IEEE", "IPV", "ISO", "LGPL", "MD", "PEP", "RFC", "RSA", "SHA",
+	"SSL", "TLS", "UCS", "UTF", "WIN", "X86",
+}
+
+// Extractor finds issue keys in text using a set of regular expressions.
+// If a pattern has a capturing group, the first group is the key; otherwise
+// the whole match is.
+type Extractor struct {
+	patterns []string
+	res      []*regexp.Regexp
+	ignore   map[string]bool // key prefixes to skip
+}
+
+// NewExtractor compiles patterns into an Extractor. An empty list selects
+// DefaultPatterns, skipping keys with NonIssuePrefixes.
+func NewExtractor(patterns []string) (*Extractor, error) {
+	e := &Extractor{patterns: patterns}
+	if len(patterns) == 0 {
+		e.patterns = DefaultPatterns
+		e.ignore = make(map[string]bool, len(NonIssuePrefixes))
+		for _, p := range NonIssuePrefixes {
+			e.ignore[p] = true
+		}
+	}
+	for _, p := range e.patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("issue pattern %q: %w", p, err)
+		}
+		e.res = append(e.res, re)
+	}
+	return e, nil
+}
+
+// Signature identifies the extractor's patterns, so that stored links can be
+// rebuilt when the patterns change.
+func (e *Extractor) Signature() string {
+	signature := strings.Join(e.patterns, "\n")
+	if e.ignore != nil {
+		signature += "\nignore:" + strings.Join(NonIssuePrefixes, ",")
+	}
+	return signature
+}
+
+// Extract returns the distinct issue keys referenced in texts, in order of
+// first appearance.
+func (e *Extractor) Extract(texts ...string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, re := range e.res {
+			for _, m := range re.FindAllStringSubmatch(text, -1) {
+				key := m[0]
+				if len(m) > 1 && m[1] != "" {
+					key = m[1]
+				}
+				if prefix, _, ok := strings.Cut(key, "-"); ok && e.ignore[prefix] {
+					continue
+				}
+				if !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,116 @@
+package issues_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"git-analytics/internal/issues"
+)
+
+func TestExtractDefaultPatterns(t *testing.T) {
+	e, err := issues.NewExtractor(nil)
+	if err != nil {
+		t.Fatalf("NewExtractor: %v", err)
+	}
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"PROJ-1234: fix login", []string{"PROJ-1234"}},
+		{"Fix crash (#567), see also #568 and PROJ-1", []string{"PROJ-1", "#567", "#568"}},
+		{"Merge pull request #12 from x/y", []string{"#12"}},
+		{"PROJ-1 and PROJ-1 again", []string{"PROJ-1"}},
+		{"see https://example.com/page#12 and &#39;", nil},
+		{"lowercase proj-12 is not a key", nil},
+		{"Read UTF-8, hash with SHA-256 and format as ISO-8601 for PROJ-3", []string{"PROJ-3"}},
+	}
+	for _, tt := range tests {
+		if got := e.Extract(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Extract(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestExtractCustomPatterns(t *testing.T) {
+	e, err := issues.NewExtractor([]string{`(?i)ticket[: ]+(\d+)`})
+	if err != nil {
+		t.Fatalf("NewExtractor: %v", err)
+	}
+	if got := e.Extract("subject", "Ticket: 42"); !reflect.DeepEqual(got, []string{"42"}) {
+		t.Errorf("got %v, want [42]", got)
+	}
+
+	// Configured patterns are taken as they are.
+	e, err = issues.NewExtractor([]string{`\bSHA-[0-9]+\b`})
+	if err != nil {
+		t.Fatalf("NewExtractor: %v", err)
+	}
+	if got := e.Extract("SHA-12: rotate keys"); !reflect.DeepEqual(got, []string{"SHA-12"}) {
+		t.Errorf("got %v, want [SHA-12]", got)
+	}
+	if _, err := issues.NewExtractor([]string{"("}); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestParseExportJiraCSV(t *testing.T) {
+	data := "\ufeffSummary,Issue key,Issue Type,Status,Created,Resolved,Labels,Labels\n" +
+		"Login fails,PROJ-1,Bug,Done,15/Jan/25 10:30 AM,17/Jan/25 4:00 PM,a,b\n" +
+		"New page,PROJ-2,Story,Open,2025-01-20 09:00,,,\n"
+	got, err := issues.ParseExport([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseExport: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d issues, want 2", len(got))
+	}
+	want := issues.Issue{
+		Key: "PROJ-1", Type: "Bug", Title: "Login fails", Status: "Done",
+		CreatedAt:  time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+		ResolvedAt: time.Date(2025, 1, 17, 16, 0, 0, 0, time.UTC),
+	}
+	if got[0] != want {
+		t.Errorf("got %+v, want %+v", got[0], want)
+	}
+	if !got[1].ResolvedAt.IsZero() {
+		t.Errorf("got resolved %v for open issue, want zero", got[1].ResolvedAt)
+	}
+}
+
+func TestParseExportJiraJSON(t *testing.T) {
+	data := `{"issues": [{"key": "PROJ-7", "fields": {"summary": "Crash", "issuetype": {"name": "Bug"},
+		"status": {"name": "Closed"}, "created": "2025-01-15T10:30:00.000+0100",
+		"resolutiondate": "2025-01-16T10:30:00.000+0100"}}]}`
+	got, err := issues.ParseExport([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseExport: %v", err)
+	}
+	if len(got) != 1 || got[0].Key != "PROJ-7" || got[0].Type != "Bug" || got[0].Status != "Closed" {
+		t.Fatalf("got %+v", got)
+	}
+	if want := time.Date(2025, 1, 16, 9, 30, 0, 0, time.UTC); !got[0].ResolvedAt.Equal(want) {
+		t.Errorf("got resolved %v, want %v", got[0].ResolvedAt, want)
+	}
+}
+
+func TestParseExportGitHubJSON(t *testing.T) {
+	data := `[{"number": 567, "title": "Crash on start", "state": "CLOSED",
+		"labels": [{"name": "priority"}, {"name": "type: bug"}],
+		"createdAt": "2025-01-15T10:30:00Z", "closedAt": "2025-01-16T10:30:00Z"},
+		{"number": 568, "title": "Dark mode", "state": "OPEN", "labels": [{"name": "enhancement"}],
+		"createdAt": "2025-01-17T10:30:00Z", "closedAt": null}]`
+	got, err := issues.ParseExport([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseExport: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d issues, want 2", len(got))
+	}
+	if got[0].Key != "#567" || got[0].Type != "Bug" || got[0].Status != "closed" || got[0].ResolvedAt.IsZero() {
+		t.Errorf("got %+v", got[0])
+	}
+	if got[1].Type != "Feature" || !got[1].ResolvedAt.IsZero() {
+		t.Errorf("got %+v", got[1])
+	}
+}
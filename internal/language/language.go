@@ -0,0 +1,168 @@
+// Package language maps file paths to the programming or markup language
+// they are written in.
+package language
+
+import (
+	"path"
+	"strings"
+)
+
+// byExtension maps lower-case file extensions (including the dot) to
+// language names.
+var byExtension = map[string]string{
+	".c":      "C",
+	".h":      "C",
+	".cc":     "C++",
+	".cpp":    "C++",
+	".cxx":    "C++",
+	".hh":     "C++",
+	".hpp":    "C++",
+	".cs":     "C#",
+	".clj":    "Clojure",
+	".css":    "CSS",
+	".dart":   "Dart",
+	".ex":     "Elixir",
+	".exs":    "Elixir",
+	".erl":    "Erlang",
+	".go":     "Go",
+	".gradle": "Gradle",
+	".groovy": "Groovy",
+	".hs":     "Haskell",
+	".html":   "HTML",
+	".htm":    "HTML",
+	".java":   "Java",
+	".js":     "JavaScript",
+	".cjs":    "JavaScript",
+	".mjs":    "JavaScript",
+	".jsx":    "JavaScript",
+	".json":   "JSON",
+	".kt":     "Kotlin",
+	".kts":    "Kotlin",
+	".less":   "Less",
+	".lua":    "Lua",
+	".md":     "Markdown",
+	".m":      "Objective-C",
+	".mm":     "Objective-C++",
+	".php":    "PHP",
+	".pl":     "Perl",
+	".pm":     "Perl",
+	".proto":  "Protocol Buffers",
+	".ps1":    "PowerShell",
+	".py":     "Python",
+	".pyi":    "Python",
+	".r":      "R",
+	".rb":     "Ruby",
+	".rs":     "Rust",
+	".scala":  "Scala",
+	".scss":   "SCSS",
+	".sass":   "Sass",
+	".sh":     "Shell",
+	".bash":   "Shell",
+	".zsh":    "Shell",
+	".sql":    "SQL",
+	".swift":  "Swift",
+	".tf":     "HCL",
+	".toml":   "TOML",
+	".ts":     "TypeScript",
+	".mts":    "TypeScript",
+	".cts":    "TypeScript",
+	".tsx":    "TypeScript",
+	".vue":    "Vue",
+	".svelte": "Svelte",
+	".xml":    "XML",
+	".yaml":   "YAML",
+	".yml":    "YAML",
+	".zig":    "Zig",
+}
+
+// byFilename maps well-known extensionless (or special) base names to
+// language names.
+var byFilename = map[string]string{
+	"Dockerfile":     "Dockerfile",
+	"Makefile":       "Makefile",
+	"GNUmakefile":    "Makefile",
+	"CMakeLists.txt": "CMake",
+	"Gemfile":        "Ruby",
+	"Rakefile":       "Ruby",
+	"go.mod":         "Go Module",
+	"go.sum":         "Go Module",
+}
+
+// FromPath returns the language of the file at p (a slash-separated
+// repository path) judged by its base name and extension, or "" if it is not
+// recognized.
+func FromPath(p string) string {
+	base := path.Base(p)
+	if lang, ok := byFilename[base]; ok {
+		return lang
+	}
+	return byExtension[strings.ToLower(path.Ext(base))]
+}
+
+// byInterpreter maps interpreter names in shebang lines, without version
+// suffixes, to language names.
+var byInterpreter = map[string]string{
+	"sh":      "Shell",
+	"bash":    "Shell",
+	"dash":    "Shell",
+	"ksh":     "Shell",
+	"zsh":     "Shell",
+	"python":  "Python",
+	"ruby":    "Ruby",
+	"perl":    "Perl",
+	"php":     "PHP",
+	"lua":     "Lua",
+	"node":    "JavaScript",
+	"nodejs":  "JavaScript",
+	"deno":    "TypeScript",
+	"ts-node": "TypeScript",
+	"Rscript": "R",
+	"pwsh":    "PowerShell",
+	"elixir":  "Elixir",
+	"escript": "Erlang",
+}
+
+// FromShebang returns the language of a script judged by the interpreter
+// named on the shebang line at the start of src, or "" if there is none or
+// it is not recognized. Interpreters run through env are recognized, and
+// version suffixes such as in python3.12 are ignored.
+func FromShebang(src []byte) string {
+	line, _, _ := strings.Cut(string(src[:min(len(src), 256)]), "\n")
+	rest, ok := strings.CutPrefix(line, "#!")
+	if !ok {
+		return ""
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, f := range fields[1:] {
+			// Skip options such as -S and variable assignments.
+			if strings.HasPrefix(f, "-") || strings.Contains(f, "=") {
+				continue
+			}
+			interpreter = path.Base(f)
+			break
+		}
+	}
+	return byInterpreter[strings.TrimRight(interpreter, "0123456789.")]
+}
+
+// Canonical returns the language name known to this package that matches
+// name, as given in a linguist-language attribute, ignoring case and
+// treating hyphens as spaces; unknown names are returned unchanged.
+func Canonical(name string) string {
+	fold := func(s string) string { return strings.ReplaceAll(strings.ToLower(s), "-", " ") }
+	key := fold(name)
+	for _, m := range []map[string]string{byExtension, byFilename, byInterpreter} {
+		for _, lang := range m {
+			if fold(lang) == key {
+				return lang
+			}
+		}
+	}
+	return name
+}
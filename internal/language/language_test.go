@@ -0,0 +1,68 @@
+package language_test
+
+import (
+	"testing"
+
+	"git-analytics/internal/language"
+)
+
+func TestFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"main.go", "Go"},
+		{"internal/query/query.go", "Go"},
+		{"frontend/src/App.vue", "Vue"},
+		{"scripts/build.SH", "Shell"},
+		{"Dockerfile", "Dockerfile"},
+		{"deploy/Makefile", "Makefile"},
+		{"go.mod", "Go Module"},
+		{"LICENSE", ""},
+		{"assets/logo.png", ""},
+	}
+	for _, tt := range tests {
+		if got := language.FromPath(tt.path); got != tt.want {
+			t.Errorf("FromPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFromShebang(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"#!/bin/sh\necho hi\n", "Shell"},
+		{"#!/usr/bin/env python3\n", "Python"},
+		{"#!/usr/bin/python3.12 -u\n", "Python"},
+		{"#!/usr/bin/env -S node --experimental\n", "JavaScript"},
+		{"#! /usr/bin/env FOO=1 ruby", "Ruby"},
+		{"#!/usr/bin/awk -f\n", ""},
+		{"echo no shebang\n", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := language.FromShebang([]byte(tt.src)); got != tt.want {
+			t.Errorf("FromShebang(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"python", "Python"},
+		{"Go", "Go"},
+		{"protocol-buffers", "Protocol Buffers"},
+		{"objective-c", "Objective-C"},
+		{"Brainfuck", "Brainfuck"},
+	}
+	for _, tt := range tests {
+		if got := language.Canonical(tt.name); got != tt.want {
+			t.Errorf("Canonical(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Package linguist classifies repository paths as generated, vendored or
+// documentation, following the .gitattributes overrides of GitHub Linguist.
+package linguist
+
+import (
+	"path"
+	"strings"
+
+	"git-analytics/internal/gitattr"
+)
+
+// Kinds describes what a path holds besides hand-written source code.
+type Kinds struct {
+	Generated     bool
+	Vendored      bool
+	Documentation bool
+}
+
+// Any reports whether any kind is set.
+func (k Kinds) Any() bool {
+	return k.Generated || k.Vendored || k.Documentation
+}
+
+// Classify returns the kinds of the file at p, a slash-separated repository
+// path. The linguist-generated, linguist-vendored and
+// linguist-documentation attributes in attrs decide when specified, either
+// way; otherwise built-in heuristics on the path do.
+func Classify(p string, attrs *gitattr.Matcher) Kinds {
+	kind := func(attr string, heuristic func(string) bool) bool {
+		if v, ok := attrs.Value(p, attr); ok {
+			return v != "false"
+		}
+		return heuristic(p)
+	}
+	return Kinds{
+		Generated:     kind("linguist-generated", isGenerated),
+		Vendored:      kind("linguist-vendored", isVendored),
+		Documentation: kind("linguist-documentation", isDocumentation),
+	}
+}
+
+// vendorDirs are directories holding third-party code.
+var vendorDirs = map[string]bool{
+	"vendor":           true,
+	"node_modules":     true,
+	"bower_components": true,
+	"third_party":      true,
+	"third-party":      true,
+	"Pods":             true,
+	"Carthage":         true,
+}
+
+// lockfiles are dependency lockfiles and checksum files written by tools.
+var lockfiles = map[string]bool{
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+	"bun.lockb":           true,
+	"go.sum":              true,
+	"Cargo.lock":          true,
+	"Gemfile.lock":        true,
+	"composer.lock":       true,
+	"poetry.lock":         true,
+	"Pipfile.lock":        true,
+	"uv.lock":             true,
+	"flake.lock":          true,
+	"mix.lock":            true,
+	"pubspec.lock":        true,
+	"Podfile.lock":        true,
+}
+
+// generatedSuffixes end the names of files written by code generators or
+// minifiers.
+var generatedSuffixes = []string{
+	".pb.go", ".pb.gw.go", "_pb2.py", "_pb2_grpc.py", ".pb.cc", ".pb.h",
+	".min.js", ".min.css", ".js.map", ".css.map",
+}
+
+// docDirs are directories holding documentation.
+var docDirs = map[string]bool{
+	"doc":           true,
+	"docs":          true,
+	"Documentation": true,
+}
+
+// docNames are the base names, without extension, of documentation files.
+var docNames = map[string]bool{
+	"readme":       true,
+	"changelog":    true,
+	"changes":      true,
+	"contributing": true,
+	"license":      true,
+	"licence":      true,
+	"copying":      true,
+	"authors":      true,
+	"notice":       true,
+}
+
+func isVendored(p string) bool {
+	dirs := strings.Split(path.Dir(p), "/")
+	for _, d := range dirs {
+		if vendorDirs[d] {
+			return true
+		}
+	}
+	return false
+}
+
+func isGenerated(p string) bool {
+	base := path.Base(p)
+	if lockfiles[base] || strings.HasPrefix(base, "zz_generated") {
+		return true
+	}
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDocumentation(p string) bool {
+	if docDirs[strings.Split(p, "/")[0]] {
+		return true
+	}
+	base := path.Base(p)
+	return docNames[strings.ToLower(strings.TrimSuffix(base, path.Ext(base)))]
+}
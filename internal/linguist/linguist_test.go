@@ -0,0 +1,44 @@
+package linguist_test
+
+import (
+	"testing"
+
+	"git-analytics/internal/gitattr"
+	"git-analytics/internal/linguist"
+)
+
+func TestClassify(t *testing.T) {
+	attrs := gitattr.New(map[string][]byte{
+		".gitattributes": []byte(`frontend/wailsjs/** linguist-generated
+vendor/patched/** -linguist-vendored
+docs/api.go -linguist-documentation
+examples/** linguist-documentation
+`),
+	})
+
+	tests := []struct {
+		path string
+		want linguist.Kinds
+	}{
+		{"main.go", linguist.Kinds{}},
+		{"api/v1/service.pb.go", linguist.Kinds{Generated: true}},
+		{"frontend/package-lock.json", linguist.Kinds{Generated: true}},
+		{"go.sum", linguist.Kinds{Generated: true}},
+		{"web/app.min.js", linguist.Kinds{Generated: true}},
+		{"frontend/wailsjs/go/main/App.js", linguist.Kinds{Generated: true}},
+		{"vendor/github.com/x/y.go", linguist.Kinds{Vendored: true}},
+		{"frontend/node_modules/vue/index.js", linguist.Kinds{Vendored: true}},
+		{"vendor/patched/lib.go", linguist.Kinds{}},
+		{"docs/guide.md", linguist.Kinds{Documentation: true}},
+		{"docs/api.go", linguist.Kinds{}},
+		{"pkg/README.md", linguist.Kinds{Documentation: true}},
+		{"LICENSE", linguist.Kinds{Documentation: true}},
+		{"examples/demo/main.go", linguist.Kinds{Documentation: true}},
+		{"pkg/docs/util.go", linguist.Kinds{}},
+	}
+	for _, tt := range tests {
+		if got := linguist.Classify(tt.path, attrs); got != tt.want {
+			t.Errorf("Classify(%q) = %+v, want %+v", tt.path, got, tt.want)
+		}
+	}
+}
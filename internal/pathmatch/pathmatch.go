@@ -0,0 +1,165 @@
+// Package pathmatch matches slash-separated repository paths against
+// patterns with .gitignore semantics.
+package pathmatch
+
+import (
+	"path"
+	"strings"
+)
+
+// Pattern is one parsed .gitignore-style pattern.
+type Pattern struct {
+	// Negate is set for patterns prefixed with "!", which re-include paths
+	// matched by earlier patterns.
+	Negate bool
+	// DirOnly is set for patterns ending in "/", which match only
+	// directories.
+	DirOnly bool
+
+	segments []string // unanchored patterns start with "**"
+}
+
+// Parse parses a single pattern. As in .gitignore, a pattern containing a
+// slash other than a trailing one is anchored to the root, otherwise it
+// matches at any depth; "*", "?" and "[...]" do not match "/", while a "**"
+// segment matches any number of directories. A leading backslash escapes a
+// literal "!" or "#". Trailing whitespace is ignored unless escaped with a
+// backslash; leading whitespace is part of the pattern. ok is false for
+// blank lines and comments.
+func Parse(line string) (p Pattern, ok bool) {
+	line = trimTrailingSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return Pattern{}, false
+	}
+	if rest, neg := strings.CutPrefix(line, "!"); neg {
+		p.Negate, line = true, rest
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if rest, dir := strings.CutSuffix(line, "/"); dir {
+		p.DirOnly, line = true, rest
+	}
+	if line == "" {
+		return Pattern{}, false
+	}
+
+	anchored := strings.Contains(line, "/")
+	p.segments = strings.Split(strings.TrimPrefix(line, "/"), "/")
+	for i, s := range p.segments {
+		// path.Match spells a negated class [^...] rather than [!...].
+		p.segments[i] = strings.ReplaceAll(s, "[!", "[^")
+	}
+	if !anchored {
+		p.segments = append([]string{"**"}, p.segments...)
+	}
+	return p, true
+}
+
+// trimTrailingSpace strips the trailing spaces, tabs and carriage returns of
+// line that are not escaped by a backslash.
+func trimTrailingSpace(line string) string {
+	end := len(line)
+	for end > 0 && strings.IndexByte(" \t\r", line[end-1]) >= 0 {
+		// The character is escaped if preceded by an odd number of
+		// backslashes.
+		backslashes := 0
+		for i := end - 2; i >= 0 && line[i] == '\\'; i-- {
+			backslashes++
+		}
+		if backslashes%2 == 1 {
+			break
+		}
+		end--
+	}
+	return line[:end]
+}
+
+// Match reports whether the pattern matches the file or, if dir is set, the
+// directory at name, a slash-separated path relative to the pattern's root.
+// Negate is ignored.
+func (p Pattern) Match(name string, dir bool) bool {
+	if p.DirOnly && !dir {
+		return false
+	}
+	return matchSegments(p.segments, strings.Split(name, "/"))
+}
+
+// matchSegments matches the segments of a path against those of a pattern,
+// where a "**" segment matches any number of path segments and others are
+// matched as by path.Match.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			if len(pattern) == 1 {
+				return len(name) > 0
+			}
+			for i := range len(name) {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// Matcher matches paths against an ordered list of patterns, as git does
+// for the lines of a .gitignore file.
+type Matcher struct {
+	patterns []Pattern
+}
+
+// Compile parses patterns into a Matcher, skipping blank lines and
+// comments.
+func Compile(patterns []string) *Matcher {
+	m := &Matcher{}
+	for _, line := range patterns {
+		if p, ok := Parse(line); ok {
+			m.patterns = append(m.patterns, p)
+		}
+	}
+	return m
+}
+
+// Empty reports whether the matcher has no patterns and so matches nothing.
+func (m *Matcher) Empty() bool {
+	return len(m.patterns) == 0
+}
+
+// Match reports whether the file at p is matched. The last pattern matching
+// it decides, except that, as in git, a file cannot be re-included by a
+// negated pattern once one of its parent directories is matched.
+func (m *Matcher) Match(p string) bool {
+	if m.Empty() {
+		return false
+	}
+	for i := strings.IndexByte(p, '/'); i >= 0; {
+		if m.decide(p[:i], true) {
+			return true
+		}
+		next := strings.IndexByte(p[i+1:], '/')
+		if next < 0 {
+			break
+		}
+		i += 1 + next
+	}
+	return m.decide(p, false)
+}
+
+// decide returns whether the last pattern matching name excludes it.
+func (m *Matcher) decide(name string, dir bool) bool {
+	for i := len(m.patterns) - 1; i >= 0; i-- {
+		if m.patterns[i].Match(name, dir) {
+			return !m.patterns[i].Negate
+		}
+	}
+	return false
+}
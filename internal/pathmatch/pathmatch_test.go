@@ -0,0 +1,78 @@
+package pathmatch_test
+
+import (
+	"testing"
+
+	"git-analytics/internal/pathmatch"
+)
+
+func TestMatcher(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		path     string
+		want     bool
+	}{
+		// Unanchored patterns match at any depth, but * stops at "/".
+		{[]string{"*.pb.go"}, "api.pb.go", true},
+		{[]string{"*.pb.go"}, "pkg/api/v1.pb.go", true},
+		{[]string{"src/*.go"}, "src/a.go", true},
+		{[]string{"src/*.go"}, "src/sub/a.go", false},
+		{[]string{"src/*.go"}, "lib/src/a.go", false},
+		// Leading slash anchors a pattern to the root.
+		{[]string{"/README.md"}, "README.md", true},
+		{[]string{"/README.md"}, "docs/README.md", false},
+		{[]string{"README.md"}, "docs/README.md", true},
+		// ** matches any number of directories.
+		{[]string{"**/testdata/**"}, "testdata/a.txt", true},
+		{[]string{"**/testdata/**"}, "pkg/testdata/x/a.txt", true},
+		{[]string{"docs/**/*.md"}, "docs/a.md", true},
+		{[]string{"docs/**/*.md"}, "docs/x/y/a.md", true},
+		{[]string{"docs/**"}, "docs", false},
+		// Directory patterns match everything below a directory.
+		{[]string{"vendor/"}, "vendor/a/b.go", true},
+		{[]string{"vendor/"}, "pkg/vendor/b.go", true},
+		{[]string{"vendor/"}, "vendor", false},
+		{[]string{"/vendor/*"}, "vendor/a/b.go", true},
+		{[]string{"build"}, "build/out.bin", true},
+		// Negation: the last matching pattern wins.
+		{[]string{"*.lock", "!Cargo.lock"}, "Cargo.lock", false},
+		{[]string{"*.lock", "!Cargo.lock"}, "yarn.lock", true},
+		{[]string{"!Cargo.lock", "*.lock"}, "Cargo.lock", true},
+		// A file in an excluded directory cannot be re-included...
+		{[]string{"vendor/", "!vendor/keep.go"}, "vendor/keep.go", true},
+		// ...but one matched by a file pattern can.
+		{[]string{"vendor/**", "!vendor/keep.go"}, "vendor/keep.go", false},
+		// Escapes, comments, blank lines and classes.
+		{[]string{`\!important`}, "!important", true},
+		{[]string{"# *.go", "", "  "}, "a.go", false},
+		// Trailing whitespace is ignored unless escaped; leading whitespace
+		// is kept.
+		{[]string{"*.go  "}, "a.go", true},
+		{[]string{`notes\ `}, "notes ", true},
+		{[]string{`notes\ `}, "notes", false},
+		{[]string{`notes\\ `}, `notes\`, true},
+		{[]string{" a.go"}, " a.go", true},
+		{[]string{" a.go"}, "a.go", false},
+		{[]string{"file[!0-9].txt"}, "filea.txt", true},
+		{[]string{"file[!0-9].txt"}, "file1.txt", false},
+		{nil, "a.go", false},
+	}
+	for _, tt := range tests {
+		if got := pathmatch.Compile(tt.patterns).Match(tt.path); got != tt.want {
+			t.Errorf("Compile(%q).Match(%q): got %v, want %v", tt.patterns, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	p, ok := pathmatch.Parse("!build/")
+	if !ok || !p.Negate || !p.DirOnly {
+		t.Fatalf("Parse(%q): got %+v, %v; want a negated directory pattern", "!build/", p, ok)
+	}
+	if !p.Match("build", true) || p.Match("build", false) {
+		t.Errorf("directory pattern should match only the directory build")
+	}
+	if _, ok := pathmatch.Parse("# comment"); ok {
+		t.Errorf("Parse(comment): got ok, want skipped")
+	}
+}
@@ -0,0 +1,206 @@
+package query
+
+import (
+	"database/sql"
+	"path"
+	"sort"
+	"time"
+
+	"git-analytics/internal/language"
+)
+
+// LanguageShare summarizes changes to files of one language.
+type LanguageShare struct {
+	Language     string  `json:"language"`
+	Files        int     `json:"files"`
+	LinesChanged int     `json:"lines_changed"`
+	Pct          float64 `json:"pct"` // share of all lines changed
+}
+
+// AuthorProfile gathers everything known about a single contributor.
+type AuthorProfile struct {
+	AuthorName        string          `json:"author_name"`
+	AuthorEmail       string          `json:"author_email"`
+	Commits           int             `json:"commits"`
+	FirstCommit       string          `json:"first_commit"`
+	LastCommit        string          `json:"last_commit"`
+	AvgLinesPerCommit float64         `json:"avg_lines_per_commit"`
+	AvgFilesPerCommit float64         `json:"avg_files_per_commit"`
+	Series            []SeriesPoint   `json:"series"`
+	Punchcard         *Punchcard      `json:"punchcard"`
+	TopFiles          []FileHotspot   `json:"top_files"`
+	TopDirectories    []FileHotspot   `json:"top_directories"`
+	OwnedFiles        []FileOwnership `json:"owned_files"`
+	Languages         []LanguageShare `json:"languages"`
+	CoChanges         []CoChangePair  `json:"co_changes"`
+}
+
+// GetAuthorProfile returns the activity of the author with the given email
+// for commits between from (inclusive) and to (exclusive): totals, activity
+// series at the given granularity, punchcard in the author's local time, the
+// limit most changed files and directories, files where they are the top
+// owner by churn, languages touched and files they tend to change together.
+// Only commits kept by filter count, and files it excludes are omitted from
+// file-level metrics.
+func GetAuthorProfile(db *sql.DB, email string, from, to time.Time, g Granularity, limit int, filter Filter) (*AuthorProfile, error) {
+	p := &AuthorProfile{AuthorEmail: email}
+
+	commitSQL, commitArgs := filter.commitClauses()
+	args := append([]any{wallClock(from), wallClock(to), email}, commitArgs...)
+	var first, last sql.NullString
+	err := db.QueryRow(
+		`SELECT COUNT(*), COALESCE(MAX(c.author_name), ''),
+		        date(MIN(`+commitLocalTime+`), 'unixepoch'),
+		        date(MAX(`+commitLocalTime+`), 'unixepoch')
+		 FROM commits c
+		 WHERE `+commitInRange+` AND c.author_email = ?`+commitSQL,
+		args...,
+	).Scan(&p.Commits, &p.AuthorName, &first, &last)
+	if err != nil {
+		return nil, err
+	}
+	p.FirstCommit = first.String
+	p.LastCommit = last.String
+
+	if p.Series, err = activitySeries(db, from, to, g, seriesScope{email: email}, filter); err != nil {
+		return nil, err
+	}
+	if p.Punchcard, err = CommitPunchcard(db, from, to, email, nil, filter); err != nil {
+		return nil, err
+	}
+	if err := p.addFileBreakdowns(db, from, to, limit, filter); err != nil {
+		return nil, err
+	}
+
+	ownerships, err := FileOwnerships(db, from, to, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range ownerships {
+		if o.TopAuthorEmail == email {
+			p.OwnedFiles = append(p.OwnedFiles, o)
+		}
+	}
+
+	if p.CoChanges, err = coChanges(db, from, to, 2, limit, filter, email); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// addFileBreakdowns fills in the per-file, per-directory and per-language
+// aggregates and average commit size from the author's file changes.
+func (p *AuthorProfile) addFileBreakdowns(db *sql.DB, from, to time.Time, limit int, filter Filter) error {
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT fs.commit_hash, fs.file_path, fs.additions, fs.deletions, COALESCE(fl.language, '')
+	 FROM file_stats fs
+	 JOIN commits c ON c.hash = fs.commit_hash
+	 LEFT JOIN file_languages fl ON fl.path = fs.file_path
+	 WHERE ` + commitInRange + ` AND c.author_email = ?` + commitSQL + excludeSQL
+
+	args := make([]any, 0, len(commitArgs)+len(excludeArgs)+3)
+	args = append(args, wallClock(from), wallClock(to), p.AuthorEmail)
+	args = append(args, commitArgs...)
+	args = append(args, excludeArgs...)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type aggregate struct {
+		h       FileHotspot
+		commits map[string]bool
+	}
+	files := make(map[string]*aggregate)
+	dirs := make(map[string]*aggregate)
+	add := func(m map[string]*aggregate, key, hash string, additions, deletions int) {
+		a, ok := m[key]
+		if !ok {
+			a = &aggregate{h: FileHotspot{Path: key}, commits: make(map[string]bool)}
+			m[key] = a
+		}
+		a.h.Additions += additions
+		a.h.Deletions += deletions
+		a.h.LinesChanged += additions + deletions
+		a.commits[hash] = true
+	}
+
+	// Languages detected by indexer.IndexLanguages, by path.
+	detected := make(map[string]string)
+	changes, totalLines := 0, 0
+	for rows.Next() {
+		var hash, filePath, lang string
+		var additions, deletions int
+		if err := rows.Scan(&hash, &filePath, &additions, &deletions, &lang); err != nil {
+			return err
+		}
+		detected[filePath] = lang
+		add(files, filePath, hash, additions, deletions)
+		add(dirs, path.Dir(filePath), hash, additions, deletions)
+		changes++
+		totalLines += additions + deletions
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if p.Commits > 0 {
+		p.AvgLinesPerCommit = float64(totalLines) / float64(p.Commits)
+		p.AvgFilesPerCommit = float64(changes) / float64(p.Commits)
+	}
+
+	ranked := func(m map[string]*aggregate) []FileHotspot {
+		result := make([]FileHotspot, 0, len(m))
+		for _, a := range m {
+			a.h.Commits = len(a.commits)
+			result = append(result, a.h)
+		}
+		sort.Slice(result, func(i, j int) bool {
+			if result[i].LinesChanged != result[j].LinesChanged {
+				return result[i].LinesChanged > result[j].LinesChanged
+			}
+			return result[i].Path < result[j].Path
+		})
+		if len(result) > limit {
+			result = result[:limit]
+		}
+		return result
+	}
+	p.TopFiles = ranked(files)
+	p.TopDirectories = ranked(dirs)
+
+	langs := make(map[string]*LanguageShare)
+	for filePath, a := range files {
+		name := detected[filePath]
+		if name == "" {
+			name = language.FromPath(filePath)
+		}
+		if name == "" {
+			name = "Other"
+		}
+		l, ok := langs[name]
+		if !ok {
+			l = &LanguageShare{Language: name}
+			langs[name] = l
+		}
+		l.Files++
+		l.LinesChanged += a.h.LinesChanged
+	}
+	for _, l := range langs {
+		if totalLines > 0 {
+			l.Pct = float64(l.LinesChanged) / float64(totalLines) * 100
+		}
+		p.Languages = append(p.Languages, *l)
+	}
+	sort.Slice(p.Languages, func(i, j int) bool {
+		if p.Languages[i].LinesChanged != p.Languages[j].LinesChanged {
+			return p.Languages[i].LinesChanged > p.Languages[j].LinesChanged
+		}
+		return p.Languages[i].Language < p.Languages[j].Language
+	})
+	return nil
+}
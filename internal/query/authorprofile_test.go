@@ -0,0 +1,100 @@
+package query_test
+
+import (
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func TestGetAuthorProfile_Basic(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 13, 9, 0, 0, 0, time.UTC), "api server")
+	insertCommit(t, db, "aaa2", "Alice", "alice@example.com",
+		time.Date(2025, 2, 3, 9, 0, 0, 0, time.UTC), "api client")
+	insertCommit(t, db, "bbb1", "Bob", "bob@example.com",
+		time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC), "bob owns util")
+
+	insertFileStat(t, db, "aaa1", "api/server.go", 40, 0)
+	insertFileStat(t, db, "aaa1", "api/server_test.go", 20, 0)
+	insertFileStat(t, db, "aaa2", "api/server.go", 5, 5)
+	insertFileStat(t, db, "aaa2", "api/server_test.go", 5, 0)
+	insertFileStat(t, db, "aaa2", "README.md", 3, 2)
+	insertFileStat(t, db, "bbb1", "README.md", 50, 0)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	p, err := query.GetAuthorProfile(db, "alice@example.com", from, to, query.GranularityMonth, 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("GetAuthorProfile: %v", err)
+	}
+
+	if p.AuthorName != "Alice" || p.Commits != 2 {
+		t.Errorf("totals: got name %q, %d commits", p.AuthorName, p.Commits)
+	}
+	if p.FirstCommit != "2025-01-13" || p.LastCommit != "2025-02-03" {
+		t.Errorf("first/last: got %s / %s", p.FirstCommit, p.LastCommit)
+	}
+	// 80 lines over 2 commits, 5 file changes over 2 commits.
+	if p.AvgLinesPerCommit != 40 || p.AvgFilesPerCommit != 2.5 {
+		t.Errorf("averages: got %f lines, %f files", p.AvgLinesPerCommit, p.AvgFilesPerCommit)
+	}
+
+	if len(p.Series) != 2 || p.Series[0].Commits != 1 || p.Series[1].Commits != 1 {
+		t.Errorf("series: got %+v", p.Series)
+	}
+	if p.Punchcard.Total != 2 || p.Punchcard.Counts[time.Monday][9] != 2 {
+		t.Errorf("punchcard: expected 2 commits on Monday 09:00, got total %d", p.Punchcard.Total)
+	}
+
+	if len(p.TopFiles) != 3 || p.TopFiles[0].Path != "api/server.go" || p.TopFiles[0].LinesChanged != 50 || p.TopFiles[0].Commits != 2 {
+		t.Errorf("top files: got %+v", p.TopFiles)
+	}
+	if len(p.TopDirectories) != 2 || p.TopDirectories[0].Path != "api" || p.TopDirectories[0].LinesChanged != 75 || p.TopDirectories[0].Commits != 2 {
+		t.Errorf("top directories: got %+v", p.TopDirectories)
+	}
+
+	// Bob dominates README.md, so Alice owns only the api files.
+	if len(p.OwnedFiles) != 2 {
+		t.Errorf("owned files: got %+v", p.OwnedFiles)
+	}
+	for _, o := range p.OwnedFiles {
+		if o.Path == "README.md" {
+			t.Errorf("README.md should be owned by Bob")
+		}
+	}
+
+	if len(p.Languages) != 2 || p.Languages[0].Language != "Go" || p.Languages[0].Files != 2 || p.Languages[0].LinesChanged != 75 {
+		t.Errorf("languages: got %+v", p.Languages)
+	}
+	if p.Languages[1].Language != "Markdown" || p.Languages[1].LinesChanged != 5 {
+		t.Errorf("languages[1]: got %+v", p.Languages[1])
+	}
+
+	if len(p.CoChanges) != 1 {
+		t.Fatalf("expected 1 co-change pair, got %+v", p.CoChanges)
+	}
+	cc := p.CoChanges[0]
+	if cc.FileA != "api/server.go" || cc.FileB != "api/server_test.go" || cc.CoChangeCount != 2 {
+		t.Errorf("co-change: got %+v", cc)
+	}
+}
+
+func TestGetAuthorProfile_Unknown(t *testing.T) {
+	db := setupDB(t)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	p, err := query.GetAuthorProfile(db, "nobody@example.com", from, to, query.GranularityMonth, 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("GetAuthorProfile: %v", err)
+	}
+
+	if p.Commits != 0 || p.FirstCommit != "" || len(p.TopFiles) != 0 || len(p.Languages) != 0 {
+		t.Errorf("expected empty profile, got %+v", p)
+	}
+}
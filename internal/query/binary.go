@@ -0,0 +1,56 @@
+package query
+
+import (
+	"database/sql"
+	"time"
+)
+
+// BinaryFile summarizes the changes to a binary file. Binary changes have no
+// line counts, so churn is measured in changes and bytes.
+type BinaryFile struct {
+	Path         string `json:"path"`
+	Changes      int    `json:"changes"`
+	SizeDelta    int64  `json:"size_delta"`    // net change in size in bytes
+	BytesChanged int64  `json:"bytes_changed"` // sum of absolute size changes
+	LastChanged  string `json:"last_changed"`
+}
+
+// BinaryChurn returns the binary files changed between from (inclusive) and
+// to (exclusive), ordered by number of changes descending, then by bytes
+// changed. Commits and files are narrowed by filter.
+func BinaryChurn(db *sql.DB, from, to time.Time, filter Filter) ([]BinaryFile, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT fs.file_path,
+	        COUNT(*) AS changes,
+	        SUM(fs.size_delta),
+	        SUM(ABS(fs.size_delta)) AS bytes_changed,
+	        date(MAX(` + commitLocalTime + `), 'unixepoch')
+	 FROM file_stats fs
+	 JOIN commits c ON c.hash = fs.commit_hash
+	 WHERE fs.binary AND ` + commitInRange + commitSQL + excludeSQL + `
+	 GROUP BY fs.file_path
+	 ORDER BY changes DESC, bytes_changed DESC, fs.file_path`
+
+	args := make([]any, 0, len(commitArgs)+len(excludeArgs)+2)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	args = append(args, excludeArgs...)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []BinaryFile
+	for rows.Next() {
+		var f BinaryFile
+		if err := rows.Scan(&f.Path, &f.Changes, &f.SizeDelta, &f.BytesChanged, &f.LastChanged); err != nil {
+			return nil, err
+		}
+		result = append(result, f)
+	}
+	return result, rows.Err()
+}
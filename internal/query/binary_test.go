@@ -0,0 +1,76 @@
+package query_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func insertBinaryStat(t *testing.T, db *sql.DB, commitHash, filePath string, sizeDelta int64) {
+	t.Helper()
+	_, err := db.Exec(
+		`INSERT INTO file_stats (commit_hash, file_path, additions, deletions, binary, size_delta) VALUES (?, ?, 0, 0, 1, ?)`,
+		commitHash, filePath, sizeDelta,
+	)
+	if err != nil {
+		t.Fatalf("insert binary stat: %v", err)
+	}
+}
+
+func setupBinaryDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := setupDB(t)
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC), "add assets")
+	insertCommit(t, db, "aaa2", "Alice", "alice@example.com",
+		time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC), "shrink logo")
+	insertBinaryStat(t, db, "aaa1", "logo.png", 5000)
+	insertBinaryStat(t, db, "aaa1", "font.woff", 800)
+	insertFileStat(t, db, "aaa1", "main.go", 10, 0)
+	insertBinaryStat(t, db, "aaa2", "logo.png", -2000)
+	return db
+}
+
+func TestBinaryChurn(t *testing.T) {
+	db := setupBinaryDB(t)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	files, err := query.BinaryChurn(db, from, to, query.Filter{})
+	if err != nil {
+		t.Fatalf("BinaryChurn: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 binary files, got %+v", files)
+	}
+	logo := files[0]
+	if logo.Path != "logo.png" || logo.Changes != 2 || logo.SizeDelta != 3000 || logo.BytesChanged != 7000 || logo.LastChanged != "2025-01-20" {
+		t.Errorf("got %+v", logo)
+	}
+}
+
+func TestFilter_Binary(t *testing.T) {
+	db := setupBinaryDB(t)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mode query.BinaryMode
+		want int
+	}{
+		{query.BinaryInclude, 3},
+		{query.BinaryExclude, 1},
+		{query.BinaryOnly, 2},
+	}
+	for _, tt := range tests {
+		hotspots, err := query.FileHotspots(db, from, to, query.Filter{Binary: tt.mode})
+		if err != nil {
+			t.Fatalf("FileHotspots: %v", err)
+		}
+		if len(hotspots) != tt.want {
+			t.Errorf("mode %q: got %d files, want %d", tt.mode, len(hotspots), tt.want)
+		}
+	}
+}
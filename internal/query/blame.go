@@ -0,0 +1,164 @@
+package query
+
+import (
+	"database/sql"
+	"path"
+	"sort"
+	"time"
+)
+
+// BlameOwnership shows who owns the lines of a file or directory that survive
+// at HEAD, next to who changed it most over a period.
+type BlameOwnership struct {
+	Path              string  `json:"path"`
+	Lines             int     `json:"lines"` // lines at HEAD
+	TopAuthorName     string  `json:"top_author_name"`
+	TopAuthorEmail    string  `json:"top_author_email"`
+	TopAuthorPct      float64 `json:"top_author_pct"`
+	SecondAuthorName  string  `json:"second_author_name"`
+	SecondAuthorEmail string  `json:"second_author_email"`
+	SecondAuthorPct   float64 `json:"second_author_pct"`
+	AuthorCount       int     `json:"author_count"`
+
+	// Churn-based ownership as in FileOwnerships.
+	ChurnTopAuthorName  string  `json:"churn_top_author_name"`
+	ChurnTopAuthorEmail string  `json:"churn_top_author_email"`
+	ChurnTopAuthorPct   float64 `json:"churn_top_author_pct"`
+	ChurnLines          int     `json:"churn_lines"`
+}
+
+// authorTally sums lines per author email for one file or directory.
+type authorTally struct {
+	names map[string]string
+	lines map[string]int
+	total int
+}
+
+func (t *authorTally) add(email, name string, lines int) {
+	if t.lines == nil {
+		t.names = make(map[string]string)
+		t.lines = make(map[string]int)
+	}
+	t.names[email] = name
+	t.lines[email] += lines
+	t.total += lines
+}
+
+// ranked returns the tally's author emails by lines descending.
+func (t *authorTally) ranked() []string {
+	emails := make([]string, 0, len(t.lines))
+	for email := range t.lines {
+		emails = append(emails, email)
+	}
+	sort.Slice(emails, func(i, j int) bool {
+		if t.lines[emails[i]] != t.lines[emails[j]] {
+			return t.lines[emails[i]] > t.lines[emails[j]]
+		}
+		return emails[i] < emails[j]
+	})
+	return emails
+}
+
+// pct returns email's share of the tally's lines as a percentage.
+func (t *authorTally) pct(email string) float64 {
+	if t.total == 0 {
+		return 0
+	}
+	return float64(t.lines[email]) / float64(t.total) * 100
+}
+
+// BlameOwnerships returns, for each file of the indexed HEAD that has been
+// blamed (see indexer.IndexBlame), the authors of its surviving lines, with
+// the churn-based top author for commits between from (inclusive) and to
+// (exclusive). If byDirectory is true, files are aggregated into their
+// parent directories. Results are sorted by top_author_pct descending. Only
+// lines from commits kept by filter count, and files it excludes are
+// omitted.
+func BlameOwnerships(db *sql.DB, from, to time.Time, byDirectory bool, filter Filter) ([]BlameOwnership, error) {
+	keyOf := func(p string) string {
+		if byDirectory {
+			return path.Dir(p)
+		}
+		return p
+	}
+
+	excludeSQL, excludeArgs := filter.fileClauses("h.path")
+	commitSQL, commitArgs := filter.commitClauses()
+	q := `SELECT h.path, b.author_email, b.author_name, b.lines
+	 FROM head_files h
+	 JOIN blame_entries b ON b.path = h.path AND b.blob_hash = h.blob_hash
+	 JOIN commits c ON c.hash = b.commit_hash
+	 WHERE 1 = 1` + commitSQL + excludeSQL
+	args := append(append([]any{}, commitArgs...), excludeArgs...)
+
+	blame, err := tallyAuthors(db, q, args, keyOf)
+	if err != nil {
+		return nil, err
+	}
+
+	excludeSQL, excludeArgs = filter.fileClauses("fs.file_path")
+	q = `SELECT fs.file_path, c.author_email, c.author_name, fs.additions + fs.deletions
+	 FROM file_stats fs
+	 JOIN commits c ON c.hash = fs.commit_hash
+	 WHERE ` + commitInRange + commitSQL + excludeSQL
+	args = make([]any, 0, len(commitArgs)+len(excludeArgs)+2)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	args = append(args, excludeArgs...)
+
+	churn, err := tallyAuthors(db, q, args, keyOf)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]BlameOwnership, 0, len(blame))
+	for key, t := range blame {
+		o := BlameOwnership{Path: key, Lines: t.total, AuthorCount: len(t.lines)}
+		authors := t.ranked()
+		o.TopAuthorEmail, o.TopAuthorName, o.TopAuthorPct = authors[0], t.names[authors[0]], t.pct(authors[0])
+		if len(authors) > 1 {
+			o.SecondAuthorEmail, o.SecondAuthorName, o.SecondAuthorPct = authors[1], t.names[authors[1]], t.pct(authors[1])
+		}
+		if c, ok := churn[key]; ok {
+			top := c.ranked()[0]
+			o.ChurnTopAuthorEmail, o.ChurnTopAuthorName, o.ChurnTopAuthorPct = top, c.names[top], c.pct(top)
+			o.ChurnLines = c.total
+		}
+		result = append(result, o)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TopAuthorPct != result[j].TopAuthorPct {
+			return result[i].TopAuthorPct > result[j].TopAuthorPct
+		}
+		return result[i].Path < result[j].Path
+	})
+	return result, nil
+}
+
+// tallyAuthors runs q, which selects path, author email, author name and
+// lines, and sums the lines per author for each key(path).
+func tallyAuthors(db *sql.DB, q string, args []any, key func(string) string) (map[string]*authorTally, error) {
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tallies := make(map[string]*authorTally)
+	for rows.Next() {
+		var p, email, name string
+		var lines int
+		if err := rows.Scan(&p, &email, &name, &lines); err != nil {
+			return nil, err
+		}
+		k := key(p)
+		t, ok := tallies[k]
+		if !ok {
+			t = &authorTally{}
+			tallies[k] = t
+		}
+		t.add(email, name, lines)
+	}
+	return tallies, rows.Err()
+}
@@ -0,0 +1,69 @@
+package query_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func insertBlame(t *testing.T, db *sql.DB, path, commitHash, name, email string, lines int) {
+	t.Helper()
+	_, err := db.Exec(
+		`INSERT INTO blame_entries (path, blob_hash, commit_hash, author_name, author_email, committed_at, lines)
+		 VALUES (?, ?, ?, ?, ?, 0, ?)`,
+		path, "blob-"+path, commitHash, name, email, lines,
+	)
+	if err != nil {
+		t.Fatalf("insert blame: %v", err)
+	}
+}
+
+func TestBlameOwnerships(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC), "write main")
+	insertCommit(t, db, "bbb1", "Bob", "bob@example.com",
+		time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC), "rewrite most of main")
+	insertFileStat(t, db, "aaa1", "src/main.go", 100, 0)
+	insertFileStat(t, db, "bbb1", "src/main.go", 30, 20)
+	insertFileStat(t, db, "aaa1", "src/util.go", 10, 0)
+
+	// Alice wrote more, but Bob's rewrite survives in most lines.
+	insertHeadFile(t, db, "src/main.go")
+	insertHeadFile(t, db, "src/util.go")
+	insertBlame(t, db, "src/main.go", "aaa1", "Alice", "alice@example.com", 20)
+	insertBlame(t, db, "src/main.go", "bbb1", "Bob", "bob@example.com", 80)
+	insertBlame(t, db, "src/util.go", "aaa1", "Alice", "alice@example.com", 10)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	files, err := query.BlameOwnerships(db, from, to, false, query.Filter{})
+	if err != nil {
+		t.Fatalf("BlameOwnerships: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %+v", files)
+	}
+	main := files[1]
+	if main.Path != "src/main.go" || main.Lines != 100 || main.TopAuthorEmail != "bob@example.com" || main.TopAuthorPct != 80 {
+		t.Errorf("got %+v, want Bob owning 80%% of src/main.go", main)
+	}
+	if main.SecondAuthorEmail != "alice@example.com" || main.AuthorCount != 2 {
+		t.Errorf("got %+v, want Alice second", main)
+	}
+	if main.ChurnTopAuthorEmail != "alice@example.com" || main.ChurnLines != 150 {
+		t.Errorf("got %+v, want Alice top by churn", main)
+	}
+
+	dirs, err := query.BlameOwnerships(db, from, to, true, query.Filter{})
+	if err != nil {
+		t.Fatalf("BlameOwnerships: %v", err)
+	}
+	if len(dirs) != 1 || dirs[0].Path != "src" || dirs[0].Lines != 110 || dirs[0].TopAuthorEmail != "bob@example.com" {
+		t.Errorf("got %+v, want src owned by Bob", dirs)
+	}
+}
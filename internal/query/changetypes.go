@@ -0,0 +1,79 @@
+package query
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ChangeTypePoint holds commit counts per change type for one time bucket.
+type ChangeTypePoint struct {
+	Period string         `json:"period"`
+	Counts map[string]int `json:"counts"` // change type → commits
+	Total  int            `json:"total"`
+}
+
+// ChangeTypeSeries returns the number of commits of each change type per
+// bucket of the given granularity for commits between from (inclusive) and
+// to (exclusive), e.g. to chart the ratio of features to fixes over time.
+// Every bucket in the range is returned in chronological order, including
+// empty ones. If pathPrefix is non-empty, only commits touching files under
+// it count. Only commits kept by filter count; files it excludes do not make
+// a commit match pathPrefix.
+func ChangeTypeSeries(db *sql.DB, from, to time.Time, g Granularity, pathPrefix string, filter Filter) ([]ChangeTypePoint, error) {
+	period, err := periodExpr(g)
+	if err != nil {
+		return nil, err
+	}
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT ` + period + ` AS period, c.change_type, COUNT(*)
+	 FROM commits c
+	 WHERE ` + commitInRange + commitSQL
+	args := append([]any{wallClock(from), wallClock(to)}, commitArgs...)
+	if pathPrefix != "" {
+		underSQL, underArgs := underPath("fs.file_path", pathPrefix)
+		excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+		q += ` AND EXISTS (SELECT 1 FROM file_stats fs
+		                   WHERE fs.commit_hash = c.hash AND ` + underSQL + excludeSQL + `)`
+		args = append(args, underArgs...)
+		args = append(args, excludeArgs...)
+	}
+	q += `
+	 GROUP BY period, c.change_type`
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byPeriod := make(map[string]map[string]int)
+	for rows.Next() {
+		var label, changeType string
+		var count int
+		if err := rows.Scan(&label, &changeType, &count); err != nil {
+			return nil, err
+		}
+		if byPeriod[label] == nil {
+			byPeriod[label] = make(map[string]int)
+		}
+		byPeriod[label][changeType] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	labels := periodLabels(from, to, g)
+	result := make([]ChangeTypePoint, len(labels))
+	for i, label := range labels {
+		p := ChangeTypePoint{Period: label, Counts: byPeriod[label]}
+		if p.Counts == nil {
+			p.Counts = map[string]int{}
+		}
+		for _, n := range p.Counts {
+			p.Total += n
+		}
+		result[i] = p
+	}
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package query_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func setChangeType(t *testing.T, db *sql.DB, hash, changeType string) {
+	t.Helper()
+	if _, err := db.Exec(`UPDATE commits SET change_type = ? WHERE hash = ?`, changeType, hash); err != nil {
+		t.Fatalf("set change type: %v", err)
+	}
+}
+
+func TestChangeTypeSeries(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "a1", "Alice", "alice@example.com", time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC), "feat: a")
+	insertCommit(t, db, "a2", "Alice", "alice@example.com", time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), "fix: b")
+	insertCommit(t, db, "a3", "Alice", "alice@example.com", time.Date(2025, 1, 7, 10, 0, 0, 0, time.UTC), "fix: c")
+	insertCommit(t, db, "a4", "Alice", "alice@example.com", time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC), "feat: d")
+	setChangeType(t, db, "a1", "feat")
+	setChangeType(t, db, "a2", "fix")
+	setChangeType(t, db, "a3", "fix")
+	setChangeType(t, db, "a4", "feat")
+	insertFileStat(t, db, "a2", "api/x.go", 1, 0)
+	insertFileStat(t, db, "a3", "web/y.ts", 1, 0)
+	insertFileStat(t, db, "a3", "apidocs/z.md", 1, 0)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	series, err := query.ChangeTypeSeries(db, from, to, query.GranularityMonth, "", query.Filter{})
+	if err != nil {
+		t.Fatalf("ChangeTypeSeries: %v", err)
+	}
+	if len(series) != 3 {
+		t.Fatalf("got %d points, want 3", len(series))
+	}
+	jan := series[0]
+	if jan.Counts["feat"] != 1 || jan.Counts["fix"] != 2 || jan.Total != 3 {
+		t.Errorf("January = %+v, want feat 1, fix 2, total 3", jan)
+	}
+	if series[1].Total != 0 || series[1].Counts == nil {
+		t.Errorf("February = %+v, want empty counts", series[1])
+	}
+	if series[2].Counts["feat"] != 1 {
+		t.Errorf("March = %+v, want feat 1", series[2])
+	}
+
+	series, err = query.ChangeTypeSeries(db, from, to, query.GranularityMonth, "api/", query.Filter{})
+	if err != nil {
+		t.Fatalf("ChangeTypeSeries with prefix: %v", err)
+	}
+	if series[0].Total != 1 || series[0].Counts["fix"] != 1 {
+		t.Errorf("January under api/ = %+v, want fix 1", series[0])
+	}
+}
+
+func TestFilter_ChangeTypes(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "a1", "Alice", "alice@example.com", time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC), "feat: a")
+	insertCommit(t, db, "a2", "Bob", "bob@example.com", time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), "fix: b")
+	setChangeType(t, db, "a1", "feat")
+	setChangeType(t, db, "a2", "fix")
+	insertFileStat(t, db, "a1", "main.go", 100, 0)
+	insertFileStat(t, db, "a2", "util.go", 5, 5)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	fixes := query.Filter{ChangeTypes: []string{"fix"}}
+
+	hotspots, err := query.FileHotspots(db, from, to, fixes)
+	if err != nil {
+		t.Fatalf("FileHotspots: %v", err)
+	}
+	if len(hotspots) != 1 || hotspots[0].Path != "util.go" {
+		t.Errorf("got %+v, want only util.go", hotspots)
+	}
+
+	contributors, err := query.Contributors(db, from, to, fixes)
+	if err != nil {
+		t.Fatalf("Contributors: %v", err)
+	}
+	if len(contributors) != 1 || contributors[0].AuthorEmail != "bob@example.com" {
+		t.Errorf("got %+v, want only bob", contributors)
+	}
+
+	stats, err := query.GetDashboardStats(db, from, to, query.Filter{ChangeTypes: []string{"feat", "fix"}})
+	if err != nil {
+		t.Fatalf("GetDashboardStats: %v", err)
+	}
+	if stats.Commits != 2 || stats.Additions != 105 {
+		t.Errorf("got %+v, want 2 commits and 105 additions", stats)
+	}
+}
@@ -0,0 +1,261 @@
+package query
+
+import (
+	"database/sql"
+	"path"
+	"sort"
+	"time"
+
+	"git-analytics/internal/git"
+)
+
+// FileAge describes how old a file present at HEAD is.
+type FileAge struct {
+	Path         string `json:"path"`
+	Created      string `json:"created"`       // date the file was last added
+	LastModified string `json:"last_modified"` // date of the most recent commit touching it
+	AgeDays      int    `json:"age_days"`      // days since last modification
+	LifetimeDays int    `json:"lifetime_days"` // days since creation
+	Commits      int    `json:"commits"`
+}
+
+// AgeBucket counts files whose age since last modification is in
+// [MinDays, MaxDays]. MaxDays is 0 for the open-ended last bucket.
+type AgeBucket struct {
+	Label   string `json:"label"`
+	MinDays int    `json:"min_days"`
+	MaxDays int    `json:"max_days"`
+	Count   int    `json:"count"`
+}
+
+// DirectoryAge summarizes the ages of the files directly in a directory.
+type DirectoryAge struct {
+	Directory     string      `json:"directory"`
+	Files         int         `json:"files"`
+	MedianAgeDays float64     `json:"median_age_days"`
+	Buckets       []AgeBucket `json:"buckets"`
+}
+
+// CodeAgeReport describes the age of the code at HEAD.
+type CodeAgeReport struct {
+	Files       []FileAge      `json:"files"`
+	Directories []DirectoryAge `json:"directories"`
+	Buckets     []AgeBucket    `json:"buckets"`
+}
+
+// ageBuckets are the bucket lower bounds, in days, with their labels.
+var ageBuckets = []struct {
+	minDays int
+	label   string
+}{
+	{0, "< 1 month"},
+	{30, "1-3 months"},
+	{90, "3-6 months"},
+	{182, "6-12 months"},
+	{365, "1-2 years"},
+	{730, "2+ years"},
+}
+
+// CodeAge returns, for each file in the indexed HEAD tree, when it was
+// created and last modified, and how many days before asOf that was.
+// Creation follows renames back to the commit that last added the file, or
+// its earliest commit if no addition is recorded; commits to an earlier,
+// deleted file at the same path are ignored. Files are
+// ordered oldest modification first and also summarized as an age histogram,
+// overall and per parent directory. Commits and files are narrowed by
+// filter; files with no kept commits are omitted.
+func CodeAge(db *sql.DB, asOf time.Time, filter Filter) (*CodeAgeReport, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("path")
+	headFiles, err := queryStrings(db, `SELECT path FROM head_files WHERE 1 = 1`+excludeSQL+` ORDER BY path`, excludeArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	renames, err := renameEdges(db)
+	if err != nil {
+		return nil, err
+	}
+
+	commitSQL, commitArgs := filter.commitClauses()
+	rows, err := db.Query(
+		`SELECT fs.file_path, fs.status, c.hash, c.committed_at, c.tz_offset
+		 FROM file_stats fs
+		 JOIN commits c ON c.hash = fs.commit_hash
+		 WHERE 1 = 1`+commitSQL,
+		commitArgs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type touch struct {
+		hash  string
+		added bool
+		at    time.Time // in the author's zone
+	}
+	touches := make(map[string][]touch)
+	for rows.Next() {
+		var filePath, status, hash string
+		var committedAt int64
+		var offsetMinutes int
+		if err := rows.Scan(&filePath, &status, &hash, &committedAt, &offsetMinutes); err != nil {
+			return nil, err
+		}
+		at := time.Unix(committedAt, 0).In(time.FixedZone("", offsetMinutes*60))
+		touches[filePath] = append(touches[filePath], touch{hash: hash, added: status == git.StatusAdded, at: at})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	daysBefore := func(t time.Time) int {
+		return max(int(asOf.Sub(t).Hours()/24), 0)
+	}
+
+	report := &CodeAgeReport{Files: []FileAge{}, Buckets: newAgeBuckets()}
+	byDir := make(map[string][]float64)
+	for _, p := range headFiles {
+		var history []touch
+		for _, name := range lineageOf(p, renames) {
+			history = append(history, touches[name]...)
+		}
+		var first, added, last time.Time
+		for _, t := range history {
+			if first.IsZero() || t.at.Before(first) {
+				first = t.at
+			}
+			if t.added && t.at.After(added) {
+				added = t.at
+			}
+			if t.at.After(last) {
+				last = t.at
+			}
+		}
+		if !added.IsZero() {
+			first = added
+		}
+		commits := make(map[string]bool)
+		for _, t := range history {
+			if !t.at.Before(first) {
+				commits[t.hash] = true
+			}
+		}
+		if len(commits) == 0 {
+			continue
+		}
+
+		f := FileAge{
+			Path:         p,
+			Created:      first.Format("2006-01-02"),
+			LastModified: last.Format("2006-01-02"),
+			AgeDays:      daysBefore(last),
+			LifetimeDays: daysBefore(first),
+			Commits:      len(commits),
+		}
+		report.Files = append(report.Files, f)
+		countAge(report.Buckets, f.AgeDays)
+		dir := path.Dir(p)
+		byDir[dir] = append(byDir[dir], float64(f.AgeDays))
+	}
+
+	sort.SliceStable(report.Files, func(i, j int) bool {
+		return report.Files[i].AgeDays > report.Files[j].AgeDays
+	})
+
+	report.Directories = make([]DirectoryAge, 0, len(byDir))
+	for dir, ages := range byDir {
+		d := DirectoryAge{
+			Directory:     dir,
+			Files:         len(ages),
+			MedianAgeDays: median(ages),
+			Buckets:       newAgeBuckets(),
+		}
+		for _, age := range ages {
+			countAge(d.Buckets, int(age))
+		}
+		report.Directories = append(report.Directories, d)
+	}
+	sort.Slice(report.Directories, func(i, j int) bool {
+		return report.Directories[i].Directory < report.Directories[j].Directory
+	})
+
+	return report, nil
+}
+
+// newAgeBuckets returns empty buckets for ageBuckets.
+func newAgeBuckets() []AgeBucket {
+	buckets := make([]AgeBucket, len(ageBuckets))
+	for i, b := range ageBuckets {
+		buckets[i] = AgeBucket{Label: b.label, MinDays: b.minDays}
+		if i+1 < len(ageBuckets) {
+			buckets[i].MaxDays = ageBuckets[i+1].minDays - 1
+		}
+	}
+	return buckets
+}
+
+// countAge increments the bucket containing ageDays.
+func countAge(buckets []AgeBucket, ageDays int) {
+	for i := len(buckets) - 1; i >= 0; i-- {
+		if ageDays >= buckets[i].MinDays {
+			buckets[i].Count++
+			return
+		}
+	}
+}
+
+// renameEdges returns, for every path that a file was renamed to, the paths
+// it was renamed from.
+func renameEdges(db *sql.DB) (map[string][]string, error) {
+	rows, err := db.Query(`SELECT DISTINCT file_path, old_path FROM file_stats WHERE old_path != ''`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	edges := make(map[string][]string)
+	for rows.Next() {
+		var newPath, oldPath string
+		if err := rows.Scan(&newPath, &oldPath); err != nil {
+			return nil, err
+		}
+		edges[newPath] = append(edges[newPath], oldPath)
+	}
+	return edges, rows.Err()
+}
+
+// lineageOf is fileLineage over preloaded rename edges: p followed by every
+// path it was previously known as.
+func lineageOf(p string, renames map[string][]string) []string {
+	lineage := []string{p}
+	seen := map[string]bool{p: true}
+	for i := 0; i < len(lineage); i++ {
+		for _, oldPath := range renames[lineage[i]] {
+			if !seen[oldPath] {
+				seen[oldPath] = true
+				lineage = append(lineage, oldPath)
+			}
+		}
+	}
+	return lineage
+}
+
+// queryStrings runs q and returns its single string column.
+func queryStrings(db *sql.DB, q string, args ...any) ([]string, error) {
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,87 @@
+package query_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func insertHeadFile(t *testing.T, db *sql.DB, path string) {
+	t.Helper()
+	if _, err := db.Exec(`INSERT INTO head_files (path, blob_hash) VALUES (?, ?)`, path, "blob-"+path); err != nil {
+		t.Fatalf("insert head file: %v", err)
+	}
+}
+
+func TestCodeAge(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "c1", "Alice", "alice@example.com", time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC), "Add old")
+	insertFileStat(t, db, "c1", "src/old.go", 10, 0)
+	insertFileStat(t, db, "c1", "src/stable.go", 10, 0)
+	insertFileStat(t, db, "c1", "gone.go", 10, 0)
+	insertCommit(t, db, "c2", "Alice", "alice@example.com", time.Date(2025, 5, 20, 10, 0, 0, 0, time.UTC), "Rename")
+	if _, err := db.Exec(
+		`INSERT INTO file_stats (commit_hash, file_path, old_path, additions, deletions) VALUES ('c2', 'src/new.go', 'src/old.go', 1, 1)`,
+	); err != nil {
+		t.Fatalf("insert rename: %v", err)
+	}
+	insertCommit(t, db, "c3", "Bob", "bob@example.com", time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), "Add docs")
+	insertFileStat(t, db, "c3", "README.md", 3, 0)
+
+	insertHeadFile(t, db, "src/new.go")
+	insertHeadFile(t, db, "src/stable.go")
+	insertHeadFile(t, db, "README.md")
+
+	asOf := time.Date(2025, 6, 11, 0, 0, 0, 0, time.UTC)
+	r, err := query.CodeAge(db, asOf, query.Filter{})
+	if err != nil {
+		t.Fatalf("CodeAge: %v", err)
+	}
+
+	if len(r.Files) != 3 {
+		t.Fatalf("got %+v, want the 3 files at HEAD", r.Files)
+	}
+	stable, renamed, readme := r.Files[0], r.Files[1], r.Files[2]
+	if stable.Path != "src/stable.go" || stable.Created != "2024-01-10" || stable.AgeDays != 517 {
+		t.Errorf("got %+v, want src/stable.go created 2024-01-10, 517 days old", stable)
+	}
+	// The renamed file keeps the creation date of its former path.
+	if renamed.Path != "src/new.go" || renamed.Created != "2024-01-10" || renamed.LastModified != "2025-05-20" || renamed.Commits != 2 {
+		t.Errorf("got %+v, want src/new.go created 2024-01-10 with 2 commits", renamed)
+	}
+	if readme.Path != "README.md" || readme.AgeDays != 9 {
+		t.Errorf("got %+v, want README.md 9 days old", readme)
+	}
+
+	if r.Buckets[0].Count != 2 || r.Buckets[4].Count != 1 {
+		t.Errorf("got buckets %+v", r.Buckets)
+	}
+	if len(r.Directories) != 2 || r.Directories[1].Directory != "src" || r.Directories[1].Files != 2 {
+		t.Errorf("got directories %+v", r.Directories)
+	}
+
+	// A file deleted and added again dates from its latest addition.
+	insertCommit(t, db, "c4", "Bob", "bob@example.com", time.Date(2025, 6, 5, 10, 0, 0, 0, time.UTC), "Re-add gone")
+	insertFileStat(t, db, "c4", "gone.go", 10, 0)
+	setFileStatus(t, db, "c1", "gone.go", "A")
+	setFileStatus(t, db, "c4", "gone.go", "A")
+	insertHeadFile(t, db, "gone.go")
+	r, err = query.CodeAge(db, asOf, query.Filter{ExcludeGlobs: []string{"src/*", "README.md"}})
+	if err != nil {
+		t.Fatalf("CodeAge: %v", err)
+	}
+	if len(r.Files) != 1 || r.Files[0].Created != "2025-06-05" || r.Files[0].Commits != 1 {
+		t.Errorf("got %+v, want gone.go created 2025-06-05 with 1 commit", r.Files)
+	}
+
+	r, err = query.CodeAge(db, asOf, query.Filter{ExcludeGlobs: []string{"src/*", "gone.go"}})
+	if err != nil {
+		t.Fatalf("CodeAge: %v", err)
+	}
+	if len(r.Files) != 1 || r.Files[0].Path != "README.md" {
+		t.Errorf("got %+v, want only README.md", r.Files)
+	}
+}
@@ -0,0 +1,271 @@
+package query
+
+import (
+	"database/sql"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SizeBucket counts commits whose size falls in [Min, Max]. Max is -1 for
+// the open-ended last bucket.
+type SizeBucket struct {
+	Label string `json:"label"`
+	Min   int    `json:"min"`
+	Max   int    `json:"max"`
+	Count int    `json:"count"`
+}
+
+// SizeStats summarizes one commit size measure as a histogram and
+// percentiles.
+type SizeStats struct {
+	Buckets []SizeBucket `json:"buckets"`
+	P50     float64      `json:"p50"`
+	P75     float64      `json:"p75"`
+	P90     float64      `json:"p90"`
+	P95     float64      `json:"p95"`
+	Max     int          `json:"max"`
+}
+
+// AuthorCommitSize summarizes the commit sizes of one author.
+type AuthorCommitSize struct {
+	AuthorName   string  `json:"author_name"`
+	AuthorEmail  string  `json:"author_email"`
+	Commits      int     `json:"commits"`
+	MedianLines  float64 `json:"median_lines"`
+	P90Lines     float64 `json:"p90_lines"`
+	MedianFiles  float64 `json:"median_files"`
+	LargeCommits int     `json:"large_commits"` // commits above the outlier threshold
+}
+
+// CommitSizePoint summarizes commit sizes for one time bucket.
+type CommitSizePoint struct {
+	Period      string  `json:"period"`
+	Commits     int     `json:"commits"`
+	MedianLines float64 `json:"median_lines"`
+	P90Lines    float64 `json:"p90_lines"`
+	MedianFiles float64 `json:"median_files"`
+}
+
+// CommitSize is the size of a single commit.
+type CommitSize struct {
+	Hash        string `json:"hash"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	Date        string `json:"date"`
+	Subject     string `json:"subject"`
+	Files       int    `json:"files"`
+	Lines       int    `json:"lines"`
+}
+
+// CommitSizeReport describes the distribution of commit sizes in a date range.
+type CommitSizeReport struct {
+	Commits          int                `json:"commits"`
+	Lines            SizeStats          `json:"lines"`
+	Files            SizeStats          `json:"files"`
+	Authors          []AuthorCommitSize `json:"authors"`
+	Series           []CommitSizePoint  `json:"series"`
+	OutlierThreshold float64            `json:"outlier_threshold"` // lines changed
+	Outliers         []CommitSize       `json:"outliers"`
+}
+
+// lineBuckets and fileBuckets are the histogram bucket lower bounds for
+// lines changed and files touched per commit. Commits changing no lines,
+// such as binary-only or mode-only ones, get a bucket of their own; every
+// counted commit touches at least one file.
+var (
+	lineBuckets = []int{0, 1, 11, 51, 201, 501, 1001}
+	fileBuckets = []int{1, 2, 4, 11, 26}
+)
+
+// CommitSizes returns the distribution of commit sizes, in lines changed
+// (additions + deletions) and files touched, between from (inclusive) and to
+// (exclusive): overall, per author (most commits first) and per bucket of the
+// given granularity. Commits whose lines changed lie above the upper Tukey
+// fence (Q3 + 1.5·IQR) are outliers; up to outlierLimit of them are returned,
+// largest first. Commits and files are narrowed by filter; commits that touch
+// no kept file, such as merges, are ignored.
+func CommitSizes(db *sql.DB, from, to time.Time, g Granularity, outlierLimit int, filter Filter) (*CommitSizeReport, error) {
+	period, err := periodExpr(g)
+	if err != nil {
+		return nil, err
+	}
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT c.hash, c.author_name, c.author_email,
+	        date(` + commitLocalTime + `, 'unixepoch'), c.message, ` + period + `,
+	        COUNT(fs.file_path), COALESCE(SUM(fs.additions + fs.deletions), 0) AS lines
+	 FROM commits c
+	 JOIN file_stats fs ON fs.commit_hash = c.hash` + excludeSQL + `
+	 WHERE ` + commitInRange + commitSQL + `
+	 GROUP BY c.hash
+	 ORDER BY lines DESC, c.committed_at DESC`
+
+	args := make([]any, 0, len(excludeArgs)+len(commitArgs)+2)
+	args = append(args, excludeArgs...)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type sizedCommit struct {
+		CommitSize
+		period string
+	}
+	var commits []sizedCommit
+	for rows.Next() {
+		var c sizedCommit
+		if err := rows.Scan(&c.Hash, &c.AuthorName, &c.AuthorEmail, &c.Date, &c.Subject, &c.period,
+			&c.Files, &c.Lines); err != nil {
+			return nil, err
+		}
+		commits = append(commits, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	report := &CommitSizeReport{Commits: len(commits), Outliers: []CommitSize{}}
+	lines := make([]float64, len(commits))
+	files := make([]float64, len(commits))
+	for i, c := range commits {
+		lines[i] = float64(c.Lines)
+		files[i] = float64(c.Files)
+	}
+	report.Lines = sizeStats(lines, lineBuckets)
+	report.Files = sizeStats(files, fileBuckets)
+
+	q1, q3 := percentile(lines, 25), percentile(lines, 75)
+	report.OutlierThreshold = q3 + 1.5*(q3-q1)
+
+	type group struct {
+		lines, files []float64
+		large        int
+	}
+	authors := make(map[string]*AuthorCommitSize)
+	byAuthor := make(map[string]*group)
+	byPeriod := make(map[string]*group)
+	for _, c := range commits {
+		large := float64(c.Lines) > report.OutlierThreshold
+		if large && len(report.Outliers) < outlierLimit {
+			report.Outliers = append(report.Outliers, c.CommitSize)
+		}
+
+		a, ok := authors[c.AuthorEmail]
+		if !ok {
+			a = &AuthorCommitSize{AuthorName: c.AuthorName, AuthorEmail: c.AuthorEmail}
+			authors[c.AuthorEmail] = a
+			byAuthor[c.AuthorEmail] = &group{}
+		}
+		a.Commits++
+		ag := byAuthor[c.AuthorEmail]
+		ag.lines = append(ag.lines, float64(c.Lines))
+		ag.files = append(ag.files, float64(c.Files))
+		if large {
+			ag.large++
+		}
+
+		pg, ok := byPeriod[c.period]
+		if !ok {
+			pg = &group{}
+			byPeriod[c.period] = pg
+		}
+		pg.lines = append(pg.lines, float64(c.Lines))
+		pg.files = append(pg.files, float64(c.Files))
+	}
+
+	report.Authors = make([]AuthorCommitSize, 0, len(authors))
+	for email, a := range authors {
+		ag := byAuthor[email]
+		a.MedianLines = median(ag.lines)
+		a.P90Lines = percentile(ag.lines, 90)
+		a.MedianFiles = median(ag.files)
+		a.LargeCommits = ag.large
+		report.Authors = append(report.Authors, *a)
+	}
+	sort.Slice(report.Authors, func(i, j int) bool {
+		if report.Authors[i].Commits != report.Authors[j].Commits {
+			return report.Authors[i].Commits > report.Authors[j].Commits
+		}
+		return report.Authors[i].AuthorEmail < report.Authors[j].AuthorEmail
+	})
+
+	labels := periodLabels(from, to, g)
+	report.Series = make([]CommitSizePoint, len(labels))
+	for i, label := range labels {
+		p := CommitSizePoint{Period: label}
+		if pg, ok := byPeriod[label]; ok {
+			p.Commits = len(pg.lines)
+			p.MedianLines = median(pg.lines)
+			p.P90Lines = percentile(pg.lines, 90)
+			p.MedianFiles = median(pg.files)
+		}
+		report.Series[i] = p
+	}
+
+	return report, nil
+}
+
+// sizeStats buckets values by the given ascending lower bounds and computes
+// their percentiles. Values below the first bound are not bucketed.
+func sizeStats(values []float64, bounds []int) SizeStats {
+	s := SizeStats{
+		Buckets: make([]SizeBucket, len(bounds)),
+		P50:     percentile(values, 50),
+		P75:     percentile(values, 75),
+		P90:     percentile(values, 90),
+		P95:     percentile(values, 95),
+	}
+	for i, lo := range bounds {
+		b := SizeBucket{Min: lo, Max: -1}
+		if i+1 < len(bounds) {
+			b.Max = bounds[i+1] - 1
+		}
+		b.Label = bucketLabel(b.Min, b.Max)
+		s.Buckets[i] = b
+	}
+	for _, v := range values {
+		if int(v) > s.Max {
+			s.Max = int(v)
+		}
+		for i := len(bounds) - 1; i >= 0; i-- {
+			if int(v) >= bounds[i] {
+				s.Buckets[i].Count++
+				break
+			}
+		}
+	}
+	return s
+}
+
+// bucketLabel formats a histogram bucket range, e.g. "11-50" or "1001+".
+func bucketLabel(lo, hi int) string {
+	switch {
+	case hi < 0:
+		return strconv.Itoa(lo) + "+"
+	case hi == lo:
+		return strconv.Itoa(lo)
+	default:
+		return strconv.Itoa(lo) + "-" + strconv.Itoa(hi)
+	}
+}
+
+// percentile returns the p-th percentile (0-100) of values, interpolating
+// linearly between the closest ranks, or 0 if values is empty.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
@@ -0,0 +1,91 @@
+package query_test
+
+import (
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func TestCommitSizes_NoLinesChanged(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com", time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), "add image")
+	insertFileStat(t, db, "aaa1", "logo.png", 0, 0)
+	insertCommit(t, db, "bbb2", "Alice", "alice@example.com", time.Date(2025, 1, 7, 10, 0, 0, 0, time.UTC), "edit")
+	insertFileStat(t, db, "bbb2", "a.go", 3, 2)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	r, err := query.CommitSizes(db, from, to, query.GranularityMonth, 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("CommitSizes: %v", err)
+	}
+
+	if got := r.Lines.Buckets[0]; got.Label != "0" || got.Count != 1 {
+		t.Errorf("got bucket %+v, want 0 with 1", got)
+	}
+	total := 0
+	for _, b := range r.Lines.Buckets {
+		total += b.Count
+	}
+	if total != r.Commits {
+		t.Errorf("got %d commits in buckets, want %d", total, r.Commits)
+	}
+}
+
+func TestCommitSizes(t *testing.T) {
+	db := setupDB(t)
+
+	day := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	for i, lines := range []int{2, 4, 6, 8, 10, 12, 14, 16} {
+		hash := string(rune('a' + i))
+		insertCommit(t, db, hash, "Alice", "alice@example.com", day.Add(time.Duration(i)*time.Hour), "small")
+		insertFileStat(t, db, hash, "a.go", lines, 0)
+	}
+	insertCommit(t, db, "big", "Bob", "bob@example.com", time.Date(2025, 2, 3, 10, 0, 0, 0, time.UTC), "huge")
+	for _, path := range []string{"x.go", "y.go", "z.go"} {
+		insertFileStat(t, db, "big", path, 400, 100)
+	}
+	insertCommit(t, db, "merge", "Bob", "bob@example.com", time.Date(2025, 2, 4, 10, 0, 0, 0, time.UTC), "Merge branch")
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	r, err := query.CommitSizes(db, from, to, query.GranularityMonth, 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("CommitSizes: %v", err)
+	}
+
+	if r.Commits != 9 {
+		t.Errorf("got %d commits, want 9 (merge without files ignored)", r.Commits)
+	}
+	if r.Lines.Max != 1500 || r.Lines.P50 != 10 {
+		t.Errorf("got lines %+v, want max 1500, p50 10", r.Lines)
+	}
+	// 1-10: 5 commits, 11-50: 3 commits, 1001+: 1 commit.
+	if got := r.Lines.Buckets[1]; got.Label != "1-10" || got.Count != 5 {
+		t.Errorf("got bucket %+v, want 1-10 with 5", got)
+	}
+	if got := r.Lines.Buckets[len(r.Lines.Buckets)-1]; got.Label != "1001+" || got.Count != 1 {
+		t.Errorf("got bucket %+v, want 1001+ with 1", got)
+	}
+	if got := r.Files.Buckets[1]; got.Label != "2-3" || got.Count != 1 {
+		t.Errorf("got files bucket %+v, want 2-3 with 1", got)
+	}
+
+	if len(r.Outliers) != 1 || r.Outliers[0].Hash != "big" || r.Outliers[0].Files != 3 {
+		t.Errorf("got outliers %+v, want only big", r.Outliers)
+	}
+
+	if len(r.Authors) != 2 || r.Authors[0].AuthorEmail != "alice@example.com" {
+		t.Fatalf("got authors %+v", r.Authors)
+	}
+	if r.Authors[0].MedianLines != 9 || r.Authors[1].LargeCommits != 1 {
+		t.Errorf("got authors %+v", r.Authors)
+	}
+
+	if len(r.Series) != 2 || r.Series[0].Commits != 8 || r.Series[1].MedianLines != 1500 {
+		t.Errorf("got series %+v", r.Series)
+	}
+}
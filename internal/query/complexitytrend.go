@@ -0,0 +1,125 @@
+package query
+
+import (
+	"database/sql"
+
+	"git-analytics/internal/git"
+)
+
+// ComplexityPoint is the size and complexity of a file after one commit.
+type ComplexityPoint struct {
+	Hash       string `json:"hash"`
+	Date       string `json:"date"`
+	Period     string `json:"period"` // bucket label, or the date when sampling every commit
+	Path       string `json:"path"`   // the file's path as of this commit
+	Lines      int    `json:"lines"`
+	Code       int    `json:"code"`
+	Comment    int    `json:"comment"`
+	Blank      int    `json:"blank"`
+	Indent     int    `json:"indent"`
+	MaxIndent  int    `json:"max_indent"`
+	Cyclomatic int    `json:"cyclomatic"`
+}
+
+// trendSample is a commit sampled by ComplexityTrend.
+type trendSample struct {
+	hash, date, period, path string
+}
+
+// trendSamples returns the commits that changed path, or one of the paths it
+// was renamed from, without deleting it, oldest first. With a granularity,
+// only the last commit of each bucket is kept; with g empty, every commit
+// is. Only commits kept by filter are sampled.
+func trendSamples(db *sql.DB, path string, g Granularity, filter Filter) ([]trendSample, error) {
+	paths, err := fileLineage(db, path)
+	if err != nil {
+		return nil, err
+	}
+	period := `date(` + commitLocalTime + `, 'unixepoch')`
+	if g != "" {
+		if period, err = periodExpr(g); err != nil {
+			return nil, err
+		}
+	}
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT c.hash, date(` + commitLocalTime + `, 'unixepoch'), ` + period + `, fs.file_path
+	 FROM file_stats fs
+	 JOIN commits c ON c.hash = fs.commit_hash
+	 WHERE fs.file_path IN (` + placeholders(len(paths)) + `) AND fs.status != ?` + commitSQL + `
+	 ORDER BY c.committed_at, c.hash`
+	args := make([]any, 0, len(paths)+len(commitArgs)+1)
+	args = append(args, pathArgs(paths)...)
+	args = append(args, git.StatusDeleted)
+	args = append(args, commitArgs...)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []trendSample
+	for rows.Next() {
+		var s trendSample
+		if err := rows.Scan(&s.hash, &s.date, &s.period, &s.path); err != nil {
+			return nil, err
+		}
+		if n := len(samples); g != "" && n > 0 && samples[n-1].period == s.period {
+			samples[n-1] = s
+			continue
+		}
+		samples = append(samples, s)
+	}
+	return samples, rows.Err()
+}
+
+// FileRevisions returns the file revisions ComplexityTrend samples for the
+// same arguments, to be measured with indexer.IndexRevisions.
+func FileRevisions(db *sql.DB, path string, g Granularity, filter Filter) ([]git.FileRevision, error) {
+	samples, err := trendSamples(db, path, g, filter)
+	if err != nil {
+		return nil, err
+	}
+	revs := make([]git.FileRevision, len(samples))
+	for i, s := range samples {
+		revs[i] = git.FileRevision{Commit: s.hash, Path: s.path}
+	}
+	return revs, nil
+}
+
+// ComplexityTrend returns the size and complexity of path after each commit
+// that changed it, oldest first, following renames. With a granularity,
+// only the file as of the last commit of each bucket is reported. Only
+// commits kept by filter are sampled, and samples not measured by
+// indexer.IndexRevisions are omitted.
+func ComplexityTrend(db *sql.DB, path string, g Granularity, filter Filter) ([]ComplexityPoint, error) {
+	samples, err := trendSamples(db, path, g, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := db.Prepare(
+		`SELECT m.lines, m.code, m.comment, m.blank, m.indent, m.max_indent, m.cyclomatic
+		 FROM revision_blobs rb
+		 JOIN file_metrics m ON m.path = rb.path AND m.blob_hash = rb.blob_hash
+		 WHERE rb.commit_hash = ? AND rb.path = ?`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	result := []ComplexityPoint{}
+	for _, s := range samples {
+		p := ComplexityPoint{Hash: s.hash, Date: s.date, Period: s.period, Path: s.path}
+		err := stmt.QueryRow(s.hash, s.path).Scan(&p.Lines, &p.Code, &p.Comment, &p.Blank, &p.Indent, &p.MaxIndent, &p.Cyclomatic)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package query_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func insertRevisionMetrics(t *testing.T, db *sql.DB, commitHash, path, blob string, code, cyclomatic int) {
+	t.Helper()
+	if _, err := db.Exec(
+		`INSERT INTO revision_blobs (commit_hash, path, blob_hash) VALUES (?, ?, ?)`,
+		commitHash, path, blob,
+	); err != nil {
+		t.Fatalf("insert revision blob: %v", err)
+	}
+	if _, err := db.Exec(
+		`INSERT OR IGNORE INTO file_metrics (path, blob_hash, lines, code, comment, blank, indent, max_indent, cyclomatic)
+		 VALUES (?, ?, ?, ?, 0, 0, 0, 0, ?)`,
+		path, blob, code, code, cyclomatic,
+	); err != nil {
+		t.Fatalf("insert metrics: %v", err)
+	}
+}
+
+func TestComplexityTrend(t *testing.T) {
+	db := setupDB(t)
+
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 10, 0, 0, 0, time.UTC) }
+	insertCommit(t, db, "ccc1", "Alice", "alice@example.com", day(1, 5), "add")
+	insertFileStat(t, db, "ccc1", "old.go", 10, 0)
+	insertRevisionMetrics(t, db, "ccc1", "old.go", "o1", 10, 2)
+	insertCommit(t, db, "ccc2", "Alice", "alice@example.com", day(1, 20), "grow")
+	insertFileStat(t, db, "ccc2", "old.go", 10, 0)
+	insertRevisionMetrics(t, db, "ccc2", "old.go", "o2", 20, 4)
+	insertCommit(t, db, "ccc3", "Bob", "bob@example.com", day(2, 3), "rename")
+	insertRename(t, db, "ccc3", "old.go", "new.go", 0, 0)
+	insertRevisionMetrics(t, db, "ccc3", "new.go", "n1", 20, 4)
+	insertCommit(t, db, "ccc4", "Bob", "bob@example.com", day(2, 10), "refactor")
+	insertFileStat(t, db, "ccc4", "new.go", 5, 15)
+	insertRevisionMetrics(t, db, "ccc4", "new.go", "n2", 10, 3)
+	// Not measured yet.
+	insertCommit(t, db, "ccc5", "Bob", "bob@example.com", day(3, 1), "tweak")
+	insertFileStat(t, db, "ccc5", "new.go", 1, 1)
+
+	revs, err := query.FileRevisions(db, "new.go", "", query.Filter{})
+	if err != nil {
+		t.Fatalf("FileRevisions: %v", err)
+	}
+	if len(revs) != 5 || revs[0].Commit != "ccc1" || revs[0].Path != "old.go" || revs[4].Commit != "ccc5" || revs[4].Path != "new.go" {
+		t.Errorf("got %+v, want the 5 commits from ccc1 at old.go to ccc5 at new.go", revs)
+	}
+
+	trend, err := query.ComplexityTrend(db, "new.go", "", query.Filter{})
+	if err != nil {
+		t.Fatalf("ComplexityTrend: %v", err)
+	}
+	wantCode := []int{10, 20, 20, 10}
+	if len(trend) != len(wantCode) {
+		t.Fatalf("got %+v, want %d points", trend, len(wantCode))
+	}
+	for i, code := range wantCode {
+		if trend[i].Code != code {
+			t.Errorf("point %d: got code %d, want %d", i, trend[i].Code, code)
+		}
+	}
+	if p := trend[1]; p.Hash != "ccc2" || p.Path != "old.go" || p.Date != "2025-01-20" || p.Period != "2025-01-20" || p.Cyclomatic != 4 {
+		t.Errorf("got %+v, want ccc2 at old.go", p)
+	}
+
+	// Monthly, the last commit of each month is sampled; March's is not
+	// measured.
+	trend, err = query.ComplexityTrend(db, "new.go", query.GranularityMonth, query.Filter{})
+	if err != nil {
+		t.Fatalf("ComplexityTrend: %v", err)
+	}
+	if len(trend) != 2 || trend[0].Hash != "ccc2" || trend[0].Period != "2025-01" || trend[1].Hash != "ccc4" || trend[1].Code != 10 {
+		t.Errorf("got %+v, want ccc2 for January and ccc4 for February", trend)
+	}
+}
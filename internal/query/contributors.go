@@ -16,10 +16,11 @@ type Contributor struct {
 
 // Contributors returns per-author commit counts, additions, and deletions
 // for commits between from (inclusive) and to (exclusive), ordered by
-// commits descending. Files matching any of the excludeGlobs patterns are
-// excluded from the additions/deletions totals but commits still count.
-func Contributors(db *sql.DB, from, to time.Time, excludeGlobs []string) ([]Contributor, error) {
-	excludeSQL, excludeArgs := buildExcludeClauses("fs.file_path", excludeGlobs)
+// commits descending. Only commits kept by filter count; files it excludes
+// are left out of the additions/deletions totals but their commits still count.
+func Contributors(db *sql.DB, from, to time.Time, filter Filter) ([]Contributor, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
 
 	q := `SELECT c.author_email,
 	        MAX(c.author_name) AS author_name,
@@ -28,13 +29,14 @@ func Contributors(db *sql.DB, from, to time.Time, excludeGlobs []string) ([]Cont
 	        COALESCE(SUM(fs.deletions), 0) AS deletions
 	 FROM commits c
 	 LEFT JOIN file_stats fs ON fs.commit_hash = c.hash` + excludeSQL + `
-	 WHERE c.committed_at >= ? AND c.committed_at < ?
+	 WHERE ` + commitInRange + commitSQL + `
 	 GROUP BY c.author_email
 	 ORDER BY commits DESC`
 
-	args := make([]any, 0, len(excludeArgs)+2)
+	args := make([]any, 0, len(excludeArgs)+len(commitArgs)+2)
 	args = append(args, excludeArgs...)
-	args = append(args, from, to)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
 
 	rows, err := db.Query(q, args...)
 	if err != nil {
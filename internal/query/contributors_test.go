@@ -26,7 +26,7 @@ func TestContributors_Basic(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	contributors, err := query.Contributors(db, from, to, nil)
+	contributors, err := query.Contributors(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("Contributors: %v", err)
 	}
@@ -57,7 +57,7 @@ func TestContributors_CommitWithoutFileStats(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	contributors, err := query.Contributors(db, from, to, nil)
+	contributors, err := query.Contributors(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("Contributors: %v", err)
 	}
@@ -87,7 +87,7 @@ func TestContributors_DateFiltering(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	contributors, err := query.Contributors(db, from, to, nil)
+	contributors, err := query.Contributors(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("Contributors: %v", err)
 	}
@@ -117,7 +117,7 @@ func TestContributors_ExcludeGlobs(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	contributors, err := query.Contributors(db, from, to, []string{"package-lock.json"})
+	contributors, err := query.Contributors(db, from, to, query.Filter{ExcludeGlobs: []string{"package-lock.json"}})
 	if err != nil {
 		t.Fatalf("Contributors: %v", err)
 	}
@@ -140,7 +140,7 @@ func TestContributors_Empty(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	contributors, err := query.Contributors(db, from, to, nil)
+	contributors, err := query.Contributors(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("Contributors: %v", err)
 	}
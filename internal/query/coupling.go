@@ -20,11 +20,23 @@ type CoChangePair struct {
 // CoChanges returns file pairs that frequently appear in the same commits
 // between from (inclusive) and to (exclusive), ordered by co-change count
 // descending. Only pairs with at least minCount shared commits are returned.
-// Files matching any of the excludeGlobs patterns are omitted.
-func CoChanges(db *sql.DB, from, to time.Time, minCount int, limit int, excludeGlobs []string) ([]CoChangePair, error) {
-	excludeA, excludeArgsA := buildExcludeClauses("a.file_path", excludeGlobs)
-	excludeB, excludeArgsB := buildExcludeClauses("b.file_path", excludeGlobs)
-	excludeFS, excludeArgsFS := buildExcludeClauses("fs.file_path", excludeGlobs)
+// Only commits kept by filter count, and files it excludes are omitted.
+func CoChanges(db *sql.DB, from, to time.Time, minCount int, limit int, filter Filter) ([]CoChangePair, error) {
+	return coChanges(db, from, to, minCount, limit, filter, "")
+}
+
+// coChanges implements CoChanges. If email is non-empty, only that author's
+// commits are considered, both for shared commits and per-file totals.
+func coChanges(db *sql.DB, from, to time.Time, minCount int, limit int, filter Filter, email string) ([]CoChangePair, error) {
+	authorSQL, authorArgs := filter.commitClauses()
+	if email != "" {
+		authorSQL += " AND c.author_email = ?"
+		authorArgs = append(authorArgs, email)
+	}
+
+	excludeA, excludeArgsA := filter.fileClauses("a.file_path")
+	excludeB, excludeArgsB := filter.fileClauses("b.file_path")
+	excludeFS, excludeArgsFS := filter.fileClauses("fs.file_path")
 
 	var b strings.Builder
 	b.WriteString(`WITH pairs AS (
@@ -34,7 +46,7 @@ func CoChanges(db *sql.DB, from, to time.Time, minCount int, limit int, excludeG
     JOIN file_stats b ON a.commit_hash = b.commit_hash
          AND a.file_path < b.file_path
     JOIN commits c ON c.hash = a.commit_hash
-    WHERE c.committed_at >= ? AND c.committed_at < ?`)
+    WHERE ` + commitInRange + authorSQL)
 	b.WriteString(excludeA)
 	b.WriteString(excludeB)
 	b.WriteString(fmt.Sprintf(`
@@ -45,7 +57,7 @@ file_commits AS (
     SELECT fs.file_path, COUNT(DISTINCT fs.commit_hash) AS commit_count
     FROM file_stats fs
     JOIN commits c ON c.hash = fs.commit_hash
-    WHERE c.committed_at >= ? AND c.committed_at < ?%s
+    WHERE %s%s%s
     GROUP BY fs.file_path
 )
 SELECT p.file_a, p.file_b, p.co_change_count,
@@ -54,16 +66,18 @@ FROM pairs p
 JOIN file_commits fa ON fa.file_path = p.file_a
 JOIN file_commits fb ON fb.file_path = p.file_b
 ORDER BY p.co_change_count DESC
-LIMIT ?`, excludeFS))
+LIMIT ?`, commitInRange, authorSQL, excludeFS))
 
-	args := make([]any, 0, 6+len(excludeArgsA)+len(excludeArgsB)+len(excludeArgsFS))
+	args := make([]any, 0, 6+2*len(authorArgs)+len(excludeArgsA)+len(excludeArgsB)+len(excludeArgsFS))
 	// pairs CTE args
-	args = append(args, from, to)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, authorArgs...)
 	args = append(args, excludeArgsA...)
 	args = append(args, excludeArgsB...)
 	args = append(args, minCount)
 	// file_commits CTE args
-	args = append(args, from, to)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, authorArgs...)
 	args = append(args, excludeArgsFS...)
 	// LIMIT
 	args = append(args, limit)
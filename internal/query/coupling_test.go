@@ -31,7 +31,7 @@ func TestCoChanges_Basic(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	pairs, err := query.CoChanges(db, from, to, 1, 100, nil)
+	pairs, err := query.CoChanges(db, from, to, 1, 100, query.Filter{})
 	if err != nil {
 		t.Fatalf("CoChanges: %v", err)
 	}
@@ -64,7 +64,7 @@ func TestCoChanges_Empty(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	pairs, err := query.CoChanges(db, from, to, 1, 100, nil)
+	pairs, err := query.CoChanges(db, from, to, 1, 100, query.Filter{})
 	if err != nil {
 		t.Fatalf("CoChanges: %v", err)
 	}
@@ -93,7 +93,7 @@ func TestCoChanges_MinCount(t *testing.T) {
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
 	// minCount=2 should exclude pairs with c.go (only 1 co-change each)
-	pairs, err := query.CoChanges(db, from, to, 2, 100, nil)
+	pairs, err := query.CoChanges(db, from, to, 2, 100, query.Filter{})
 	if err != nil {
 		t.Fatalf("CoChanges: %v", err)
 	}
@@ -125,7 +125,7 @@ func TestCoChanges_DateFiltering(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	pairs, err := query.CoChanges(db, from, to, 1, 100, nil)
+	pairs, err := query.CoChanges(db, from, to, 1, 100, query.Filter{})
 	if err != nil {
 		t.Fatalf("CoChanges: %v", err)
 	}
@@ -158,7 +158,7 @@ func TestCoChanges_ExcludeGlobs(t *testing.T) {
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
 	// Exclude *.pb.go — should remove all pairs involving generated.pb.go
-	pairs, err := query.CoChanges(db, from, to, 1, 100, []string{"*.pb.go"})
+	pairs, err := query.CoChanges(db, from, to, 1, 100, query.Filter{ExcludeGlobs: []string{"*.pb.go"}})
 	if err != nil {
 		t.Fatalf("CoChanges: %v", err)
 	}
@@ -186,7 +186,7 @@ func TestCoChanges_Limit(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	pairs, err := query.CoChanges(db, from, to, 1, 2, nil)
+	pairs, err := query.CoChanges(db, from, to, 1, 2, query.Filter{})
 	if err != nil {
 		t.Fatalf("CoChanges: %v", err)
 	}
@@ -15,30 +15,33 @@ type DashboardStats struct {
 }
 
 // GetDashboardStats returns aggregate commit and file-change stats between
-// from (inclusive) and to (exclusive). Files matching any of the excludeGlobs
-// patterns are omitted from file-level metrics (additions, deletions, files changed).
-func GetDashboardStats(db *sql.DB, from, to time.Time, excludeGlobs []string) (*DashboardStats, error) {
+// from (inclusive) and to (exclusive) for commits kept by filter. Files the
+// filter excludes are omitted from file-level metrics (additions, deletions,
+// files changed).
+func GetDashboardStats(db *sql.DB, from, to time.Time, filter Filter) (*DashboardStats, error) {
 	var s DashboardStats
 
+	commitSQL, commitArgs := filter.commitClauses()
+	args := append([]any{wallClock(from), wallClock(to)}, commitArgs...)
 	err := db.QueryRow(
 		`SELECT COUNT(*), COUNT(DISTINCT author_email)
-		 FROM commits
-		 WHERE committed_at >= ? AND committed_at < ?`,
-		from, to,
+		 FROM commits c
+		 WHERE `+commitInRange+commitSQL,
+		args...,
 	).Scan(&s.Commits, &s.Contributors)
 	if err != nil {
 		return nil, err
 	}
 
-	excludeClause, excludeArgs := buildExcludeClauses("fs.file_path", excludeGlobs)
-	args := append([]any{from, to}, excludeArgs...)
+	excludeClause, excludeArgs := filter.fileClauses("fs.file_path")
+	args = append(args, excludeArgs...)
 	err = db.QueryRow(
 		`SELECT COALESCE(SUM(fs.additions), 0),
 		        COALESCE(SUM(fs.deletions), 0),
 		        COUNT(DISTINCT fs.file_path)
 		 FROM file_stats fs
 		 JOIN commits c ON c.hash = fs.commit_hash
-		 WHERE c.committed_at >= ? AND c.committed_at < ?`+excludeClause,
+		 WHERE `+commitInRange+commitSQL+excludeClause,
 		args...,
 	).Scan(&s.Additions, &s.Deletions, &s.FilesChanged)
 	if err != nil {
@@ -55,29 +58,23 @@ type HourBucket struct {
 }
 
 // CommitsByHour returns per-hour commit counts between from (inclusive) and
-// to (exclusive). Only hours with commits are returned (sparse).
-func CommitsByHour(db *sql.DB, from, to time.Time) ([]HourBucket, error) {
-	rows, err := db.Query(
-		`SELECT CAST(SUBSTR(committed_at, 12, 2) AS INTEGER) AS hour,
-		        COUNT(*) AS count
-		 FROM commits
-		 WHERE committed_at >= ? AND committed_at < ?
-		 GROUP BY hour
-		 ORDER BY hour`,
-		from, to,
-	)
+// to (exclusive) kept by filter, bucketed in each author's local time. Only
+// hours with commits are returned (sparse).
+func CommitsByHour(db *sql.DB, from, to time.Time, filter Filter) ([]HourBucket, error) {
+	p, err := CommitPunchcard(db, from, to, "", nil, filter)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
 	var result []HourBucket
-	for rows.Next() {
-		var b HourBucket
-		if err := rows.Scan(&b.Hour, &b.Count); err != nil {
-			return nil, err
+	for hour := 0; hour < 24; hour++ {
+		count := 0
+		for _, day := range p.Counts {
+			count += day[hour]
+		}
+		if count > 0 {
+			result = append(result, HourBucket{Hour: hour, Count: count})
 		}
-		result = append(result, b)
 	}
-	return result, rows.Err()
+	return result, nil
 }
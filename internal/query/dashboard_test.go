@@ -22,7 +22,7 @@ func TestGetDashboardStats_Basic(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	s, err := query.GetDashboardStats(db, from, to, nil)
+	s, err := query.GetDashboardStats(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("GetDashboardStats: %v", err)
 	}
@@ -50,7 +50,7 @@ func TestGetDashboardStats_Empty(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	s, err := query.GetDashboardStats(db, from, to, nil)
+	s, err := query.GetDashboardStats(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("GetDashboardStats: %v", err)
 	}
@@ -76,7 +76,7 @@ func TestGetDashboardStats_DateFiltering(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	s, err := query.GetDashboardStats(db, from, to, nil)
+	s, err := query.GetDashboardStats(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("GetDashboardStats: %v", err)
 	}
@@ -100,7 +100,7 @@ func TestGetDashboardStats_ExcludeGlobs(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	s, err := query.GetDashboardStats(db, from, to, []string{"vendor/*"})
+	s, err := query.GetDashboardStats(db, from, to, query.Filter{ExcludeGlobs: []string{"vendor/*"}})
 	if err != nil {
 		t.Fatalf("GetDashboardStats: %v", err)
 	}
@@ -135,7 +135,7 @@ func TestCommitsByHour_Basic(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	buckets, err := query.CommitsByHour(db, from, to)
+	buckets, err := query.CommitsByHour(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("CommitsByHour: %v", err)
 	}
@@ -157,7 +157,7 @@ func TestCommitsByHour_Empty(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	buckets, err := query.CommitsByHour(db, from, to)
+	buckets, err := query.CommitsByHour(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("CommitsByHour: %v", err)
 	}
@@ -181,7 +181,7 @@ func TestCommitsByHour_DateFiltering(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	buckets, err := query.CommitsByHour(db, from, to)
+	buckets, err := query.CommitsByHour(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("CommitsByHour: %v", err)
 	}
@@ -0,0 +1,138 @@
+package query
+
+import (
+	"database/sql"
+	"math"
+	"path"
+	"sort"
+	"time"
+
+	"git-analytics/internal/classify"
+)
+
+// DefectHotspot scores a file or directory by how often and how recently
+// bug fixes touched it.
+type DefectHotspot struct {
+	Path         string  `json:"path"`
+	Commits      int     `json:"commits"`
+	FixCommits   int     `json:"fix_commits"`
+	FixRatio     float64 `json:"fix_ratio"` // fix commits / commits
+	LastFix      string  `json:"last_fix"`
+	DaysSinceFix int     `json:"days_since_fix"`
+	Score        float64 `json:"score"`
+	// TemporalScore is the TemporalHotspots score of the same path, so that
+	// bug magnets can be compared with plain churn hotspots.
+	TemporalScore float64 `json:"temporal_score"`
+}
+
+// DefectHotspots ranks files by bug-fix activity for commits between from
+// (inclusive) and to (exclusive). A commit counts as a bug fix when it is
+// classified as a fix or references an imported issue of a bug type. Each
+// fix is weighted by recency with the same exponential decay as
+// TemporalHotspots, and the weighted sum is multiplied by the share of the
+// file's commits that were fixes:
+//
+//	score = Σ e^(-λ * daysSince(fix)) * fixCommits / commits
+//
+// If byDirectory is true, files are aggregated into their parent
+// directories. Only paths touched by at least one fix are returned, ordered
+// by score descending. Commits and files are narrowed by filter.
+func DefectHotspots(db *sql.DB, from, to time.Time, halfLifeDays float64, byDirectory bool, filter Filter) ([]DefectHotspot, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT fs.file_path, fs.commit_hash, c.committed_at, c.tz_offset,
+	        fs.additions + fs.deletions, c.change_type = ? OR ` + defectIssueSQL + `
+	 FROM file_stats fs
+	 JOIN commits c ON c.hash = fs.commit_hash
+	 WHERE ` + commitInRange + commitSQL + excludeSQL
+
+	args := make([]any, 0, len(commitArgs)+len(excludeArgs)+3)
+	args = append(args, classify.TypeFix, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	args = append(args, excludeArgs...)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type aggregate struct {
+		commits      map[string]bool
+		fixes        map[string]bool
+		weighted     float64
+		lines        int
+		lastFix      time.Time
+		lastChange   time.Time
+		lastFixShown time.Time // lastFix in its author's zone, for display
+	}
+	lambda := math.Ln2 / halfLifeDays
+	daysSince := func(t time.Time) float64 {
+		return max(to.Sub(t).Hours()/24, 0)
+	}
+
+	byPath := make(map[string]*aggregate)
+	for rows.Next() {
+		var filePath, hash string
+		var committedAt int64
+		var offsetMinutes, lines int
+		var isFix bool
+		if err := rows.Scan(&filePath, &hash, &committedAt, &offsetMinutes, &lines, &isFix); err != nil {
+			return nil, err
+		}
+		key := filePath
+		if byDirectory {
+			key = path.Dir(filePath)
+		}
+		a, ok := byPath[key]
+		if !ok {
+			a = &aggregate{commits: make(map[string]bool), fixes: make(map[string]bool)}
+			byPath[key] = a
+		}
+
+		at := time.Unix(committedAt, 0)
+		a.commits[hash] = true
+		a.lines += lines
+		if at.After(a.lastChange) {
+			a.lastChange = at
+		}
+		if isFix && !a.fixes[hash] {
+			a.fixes[hash] = true
+			a.weighted += math.Exp(-lambda * daysSince(at))
+			if at.After(a.lastFix) {
+				a.lastFix = at
+				a.lastFixShown = at.In(time.FixedZone("", offsetMinutes*60))
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var result []DefectHotspot
+	for p, a := range byPath {
+		if len(a.fixes) == 0 {
+			continue
+		}
+		h := DefectHotspot{
+			Path:          p,
+			Commits:       len(a.commits),
+			FixCommits:    len(a.fixes),
+			LastFix:       a.lastFixShown.Format("2006-01-02"),
+			DaysSinceFix:  int(daysSince(a.lastFix)),
+			TemporalScore: float64(a.lines) * math.Exp(-lambda*daysSince(a.lastChange)),
+		}
+		h.FixRatio = float64(h.FixCommits) / float64(h.Commits)
+		h.Score = a.weighted * h.FixRatio
+		result = append(result, h)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Path < result[j].Path
+	})
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package query_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func TestDefectHotspots(t *testing.T) {
+	db := setupDB(t)
+
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	// api/handler.go: two fixes out of three commits, one fix today.
+	insertCommit(t, db, "c1", "Alice", "alice@example.com", to.AddDate(0, 0, -30), "feat: handler")
+	insertCommit(t, db, "c2", "Alice", "alice@example.com", to.AddDate(0, 0, -10), "fix: nil check")
+	insertCommit(t, db, "c3", "Bob", "bob@example.com", to, "fix: timeout")
+	// web/app.ts: lots of churn but no fixes.
+	insertCommit(t, db, "c4", "Bob", "bob@example.com", to.AddDate(0, 0, -1), "feat: app")
+	setChangeType(t, db, "c1", "feat")
+	setChangeType(t, db, "c2", "fix")
+	setChangeType(t, db, "c3", "fix")
+	setChangeType(t, db, "c4", "feat")
+	insertFileStat(t, db, "c1", "api/handler.go", 100, 0)
+	insertFileStat(t, db, "c2", "api/handler.go", 2, 1)
+	insertFileStat(t, db, "c2", "api/util.go", 1, 0)
+	insertFileStat(t, db, "c3", "api/handler.go", 5, 5)
+	insertFileStat(t, db, "c4", "web/app.ts", 500, 0)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	hotspots, err := query.DefectHotspots(db, from, to.AddDate(0, 0, 1), 10, false, query.Filter{})
+	if err != nil {
+		t.Fatalf("DefectHotspots: %v", err)
+	}
+	if len(hotspots) != 2 {
+		t.Fatalf("got %d hotspots, want 2 (files without fixes omitted): %+v", len(hotspots), hotspots)
+	}
+
+	h := hotspots[0]
+	if h.Path != "api/handler.go" || h.Commits != 3 || h.FixCommits != 2 {
+		t.Fatalf("got %+v, want api/handler.go with 2 of 3 commits fixes", h)
+	}
+	if h.LastFix != "2025-03-01" || h.DaysSinceFix != 1 {
+		t.Errorf("got last fix %s (%d days), want 2025-03-01 (1 day)", h.LastFix, h.DaysSinceFix)
+	}
+	// Fixes 11 and 1 days before the reference time, half-life 10 days.
+	want := (math.Pow(0.5, 1.1) + math.Pow(0.5, 0.1)) * 2 / 3
+	if math.Abs(h.Score-want) > 1e-9 {
+		t.Errorf("got score %f, want %f", h.Score, want)
+	}
+	if h.TemporalScore <= 0 {
+		t.Errorf("got temporal score %f, want > 0", h.TemporalScore)
+	}
+	if hotspots[1].Path != "api/util.go" || hotspots[1].FixRatio != 1 {
+		t.Errorf("got %+v, want api/util.go with fix ratio 1", hotspots[1])
+	}
+
+	dirs, err := query.DefectHotspots(db, from, to.AddDate(0, 0, 1), 10, true, query.Filter{})
+	if err != nil {
+		t.Fatalf("DefectHotspots by directory: %v", err)
+	}
+	if len(dirs) != 1 || dirs[0].Path != "api" || dirs[0].Commits != 3 || dirs[0].FixCommits != 2 {
+		t.Errorf("got %+v, want api with 2 of 3 commits fixes", dirs)
+	}
+}
@@ -0,0 +1,293 @@
+package query
+
+import (
+	"database/sql"
+	"time"
+
+	"git-analytics/internal/git"
+)
+
+// FileCommit is one commit in a file's history.
+type FileCommit struct {
+	Hash        string `json:"hash"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	Date        string `json:"date"`
+	Subject     string `json:"subject"`
+	Path        string `json:"path"`   // the file's path as of this commit
+	Status      string `json:"status"` // A, M, D or R
+	Additions   int    `json:"additions"`
+	Deletions   int    `json:"deletions"`
+}
+
+// FileAuthor summarizes one author's changes to a file.
+type FileAuthor struct {
+	AuthorName  string  `json:"author_name"`
+	AuthorEmail string  `json:"author_email"`
+	Commits     int     `json:"commits"`
+	Additions   int     `json:"additions"`
+	Deletions   int     `json:"deletions"`
+	Pct         float64 `json:"pct"` // share of the file's lines changed
+}
+
+// CoupledFile is a file that frequently changes together with another one.
+type CoupledFile struct {
+	Path          string  `json:"path"`
+	CoChangeCount int     `json:"co_change_count"`
+	CouplingRatio float64 `json:"coupling_ratio"`
+}
+
+// FileProfile gathers everything known about a single file.
+type FileProfile struct {
+	Path          string        `json:"path"`
+	PreviousPaths []string      `json:"previous_paths"`
+	Exists        bool          `json:"exists"`  // present at HEAD
+	Created       string        `json:"created"` // date the file was last added, empty if unknown
+	Deleted       string        `json:"deleted"` // date the file was deleted, empty if it exists
+	Commits       []FileCommit  `json:"commits"`
+	Series        []SeriesPoint `json:"series"`
+	Authors       []FileAuthor  `json:"authors"`
+	Coupled       []CoupledFile `json:"coupled"`
+}
+
+// GetFileProfile returns the history of path for commits between from
+// (inclusive) and to (exclusive): its commits (newest first), churn series at
+// the given granularity, author breakdown (by lines changed, descending) and
+// up to couplingLimit most frequently co-changed files. Renames recorded by
+// the indexer are followed, so changes made under earlier paths are included.
+// Only commits kept by filter count; files it excludes are omitted from the
+// coupled files. Creation and deletion dates consider the whole history.
+func GetFileProfile(db *sql.DB, path string, from, to time.Time, g Granularity, couplingLimit int, filter Filter) (*FileProfile, error) {
+	paths, err := fileLineage(db, path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &FileProfile{Path: path, PreviousPaths: paths[1:]}
+
+	if err := db.QueryRow(`SELECT EXISTS (SELECT 1 FROM head_files WHERE path = ?)`, path).Scan(&p.Exists); err != nil {
+		return nil, err
+	}
+	if p.Created, err = lastStatusDate(db, paths, git.StatusAdded); err != nil {
+		return nil, err
+	}
+	if !p.Exists {
+		if p.Deleted, err = lastStatusDate(db, paths[:1], git.StatusDeleted); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.Commits, err = fileCommits(db, paths, from, to, filter); err != nil {
+		return nil, err
+	}
+	if p.Series, err = activitySeries(db, from, to, g, seriesScope{paths: paths}, filter); err != nil {
+		return nil, err
+	}
+	if p.Authors, err = fileAuthors(db, paths, from, to, filter); err != nil {
+		return nil, err
+	}
+	if p.Coupled, err = coupledFiles(db, paths, from, to, couplingLimit, filter); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// fileLineage returns path followed by every path it was renamed from,
+// directly or transitively.
+func fileLineage(db *sql.DB, path string) ([]string, error) {
+	lineage := []string{path}
+	seen := map[string]bool{path: true}
+	for i := 0; i < len(lineage); i++ {
+		rows, err := db.Query(
+			`SELECT DISTINCT old_path FROM file_stats WHERE file_path = ? AND old_path != ''`,
+			lineage[i],
+		)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var oldPath string
+			if err := rows.Scan(&oldPath); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if !seen[oldPath] {
+				seen[oldPath] = true
+				lineage = append(lineage, oldPath)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return lineage, nil
+}
+
+// lastStatusDate returns the author-local date of the most recent commit
+// giving any of paths the change status, or "" if there is none.
+func lastStatusDate(db *sql.DB, paths []string, status string) (string, error) {
+	var date string
+	err := db.QueryRow(
+		`SELECT date(`+commitLocalTime+`, 'unixepoch')
+		 FROM file_stats fs
+		 JOIN commits c ON c.hash = fs.commit_hash
+		 WHERE fs.file_path IN (`+placeholders(len(paths))+`) AND fs.status = ?
+		 ORDER BY c.committed_at DESC
+		 LIMIT 1`,
+		append(pathArgs(paths), status)...,
+	).Scan(&date)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return date, err
+}
+
+// pathArgs converts paths into query arguments.
+func pathArgs(paths []string) []any {
+	args := make([]any, len(paths))
+	for i, p := range paths {
+		args[i] = p
+	}
+	return args
+}
+
+func fileCommits(db *sql.DB, paths []string, from, to time.Time, filter Filter) ([]FileCommit, error) {
+	commitSQL, commitArgs := filter.commitClauses()
+	q := `SELECT c.hash, c.author_name, c.author_email,
+	        date(` + commitLocalTime + `, 'unixepoch') AS day,
+	        c.message, fs.file_path, fs.status, fs.additions, fs.deletions
+	 FROM file_stats fs
+	 JOIN commits c ON c.hash = fs.commit_hash
+	 WHERE fs.file_path IN (` + placeholders(len(paths)) + `)
+	   AND ` + commitInRange + commitSQL + `
+	 ORDER BY c.committed_at DESC`
+
+	args := append(pathArgs(paths), wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []FileCommit
+	for rows.Next() {
+		var fc FileCommit
+		if err := rows.Scan(&fc.Hash, &fc.AuthorName, &fc.AuthorEmail, &fc.Date, &fc.Subject, &fc.Path, &fc.Status,
+			&fc.Additions, &fc.Deletions); err != nil {
+			return nil, err
+		}
+		result = append(result, fc)
+	}
+	return result, rows.Err()
+}
+
+func fileAuthors(db *sql.DB, paths []string, from, to time.Time, filter Filter) ([]FileAuthor, error) {
+	commitSQL, commitArgs := filter.commitClauses()
+	q := `SELECT c.author_email, MAX(c.author_name),
+	        COUNT(DISTINCT c.hash),
+	        SUM(fs.additions), SUM(fs.deletions)
+	 FROM file_stats fs
+	 JOIN commits c ON c.hash = fs.commit_hash
+	 WHERE fs.file_path IN (` + placeholders(len(paths)) + `)
+	   AND ` + commitInRange + commitSQL + `
+	 GROUP BY c.author_email
+	 ORDER BY SUM(fs.additions + fs.deletions) DESC`
+
+	args := append(pathArgs(paths), wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []FileAuthor
+	total := 0
+	for rows.Next() {
+		var a FileAuthor
+		if err := rows.Scan(&a.AuthorEmail, &a.AuthorName, &a.Commits, &a.Additions, &a.Deletions); err != nil {
+			return nil, err
+		}
+		total += a.Additions + a.Deletions
+		result = append(result, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if total > 0 {
+		for i := range result {
+			result[i].Pct = float64(result[i].Additions+result[i].Deletions) / float64(total) * 100
+		}
+	}
+	return result, nil
+}
+
+// coupledFiles returns the files most often changed in the same commits as
+// any of paths. The coupling ratio divides the shared commits by the smaller
+// of the two files' commit counts, as in CoChanges.
+func coupledFiles(db *sql.DB, paths []string, from, to time.Time, limit int, filter Filter) ([]CoupledFile, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+	in := placeholders(len(paths))
+
+	q := `WITH target AS (
+    SELECT DISTINCT fs.commit_hash
+    FROM file_stats fs
+    JOIN commits c ON c.hash = fs.commit_hash
+    WHERE fs.file_path IN (` + in + `) AND ` + commitInRange + commitSQL + `
+),
+partners AS (
+    SELECT fs.file_path, COUNT(DISTINCT fs.commit_hash) AS co_change_count
+    FROM file_stats fs
+    JOIN target t ON t.commit_hash = fs.commit_hash
+    WHERE fs.file_path NOT IN (` + in + `)` + excludeSQL + `
+    GROUP BY fs.file_path
+),
+partner_commits AS (
+    SELECT fs.file_path, COUNT(DISTINCT fs.commit_hash) AS commit_count
+    FROM file_stats fs
+    JOIN commits c ON c.hash = fs.commit_hash
+    WHERE fs.file_path IN (SELECT file_path FROM partners) AND ` + commitInRange + commitSQL + `
+    GROUP BY fs.file_path
+)
+SELECT p.file_path, p.co_change_count, pc.commit_count,
+       (SELECT COUNT(*) FROM target)
+FROM partners p
+JOIN partner_commits pc ON pc.file_path = p.file_path
+ORDER BY p.co_change_count DESC, p.file_path
+LIMIT ?`
+
+	args := make([]any, 0, 2*len(paths)+len(excludeArgs)+2*len(commitArgs)+5)
+	args = append(args, pathArgs(paths)...)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	args = append(args, pathArgs(paths)...)
+	args = append(args, excludeArgs...)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	args = append(args, limit)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []CoupledFile
+	for rows.Next() {
+		var cf CoupledFile
+		var partnerCommits, targetCommits int
+		if err := rows.Scan(&cf.Path, &cf.CoChangeCount, &partnerCommits, &targetCommits); err != nil {
+			return nil, err
+		}
+		minCommits := min(partnerCommits, targetCommits)
+		if minCommits > 0 {
+			cf.CouplingRatio = float64(cf.CoChangeCount) / float64(minCommits)
+		}
+		result = append(result, cf)
+	}
+	return result, rows.Err()
+}
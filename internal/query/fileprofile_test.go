@@ -0,0 +1,204 @@
+package query_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func insertRename(t *testing.T, db *sql.DB, commitHash, oldPath, newPath string, additions, deletions int) {
+	t.Helper()
+	_, err := db.Exec(
+		`INSERT INTO file_stats (commit_hash, file_path, old_path, additions, deletions) VALUES (?, ?, ?, ?, ?)`,
+		commitHash, newPath, oldPath, additions, deletions,
+	)
+	if err != nil {
+		t.Fatalf("insert rename: %v", err)
+	}
+}
+
+func TestGetFileProfile_FollowsRenames(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC), "add old.go")
+	insertCommit(t, db, "bbb1", "Bob", "bob@example.com",
+		time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC), "rename to new.go")
+	insertCommit(t, db, "aaa2", "Alice", "alice@example.com",
+		time.Date(2025, 2, 5, 10, 0, 0, 0, time.UTC), "edit new.go")
+	insertCommit(t, db, "ccc1", "Carol", "carol@example.com",
+		time.Date(2025, 2, 6, 10, 0, 0, 0, time.UTC), "unrelated")
+
+	insertFileStat(t, db, "aaa1", "src/old.go", 30, 0)
+	insertFileStat(t, db, "aaa1", "src/old_test.go", 10, 0)
+	insertRename(t, db, "bbb1", "src/old.go", "src/new.go", 2, 2)
+	insertFileStat(t, db, "aaa2", "src/new.go", 8, 0)
+	insertFileStat(t, db, "aaa2", "src/old_test.go", 4, 0)
+	insertFileStat(t, db, "ccc1", "README.md", 1, 0)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	p, err := query.GetFileProfile(db, "src/new.go", from, to, query.GranularityMonth, 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("GetFileProfile: %v", err)
+	}
+
+	if len(p.PreviousPaths) != 1 || p.PreviousPaths[0] != "src/old.go" {
+		t.Errorf("PreviousPaths: got %v, want [src/old.go]", p.PreviousPaths)
+	}
+
+	if len(p.Commits) != 3 {
+		t.Fatalf("expected 3 commits, got %d: %+v", len(p.Commits), p.Commits)
+	}
+	if p.Commits[0].Hash != "aaa2" || p.Commits[2].Hash != "aaa1" {
+		t.Errorf("expected newest first, got %s ... %s", p.Commits[0].Hash, p.Commits[2].Hash)
+	}
+	if p.Commits[2].Path != "src/old.go" || p.Commits[2].Subject != "add old.go" || p.Commits[2].Date != "2025-01-10" {
+		t.Errorf("oldest commit: got %+v", p.Commits[2])
+	}
+
+	if len(p.Series) != 2 {
+		t.Fatalf("expected 2 months, got %d: %+v", len(p.Series), p.Series)
+	}
+	if p.Series[0].Commits != 2 || p.Series[0].Additions != 32 || p.Series[1].Commits != 1 {
+		t.Errorf("series: got %+v", p.Series)
+	}
+
+	// Alice: 38 of 42 lines changed; Bob: 4.
+	if len(p.Authors) != 2 {
+		t.Fatalf("expected 2 authors, got %d: %+v", len(p.Authors), p.Authors)
+	}
+	a := p.Authors[0]
+	if a.AuthorEmail != "alice@example.com" || a.Commits != 2 || a.Additions != 38 {
+		t.Errorf("top author: got %+v", a)
+	}
+	if diff := a.Pct - 38.0/42.0*100; diff > 0.01 || diff < -0.01 {
+		t.Errorf("top author pct: got %f", a.Pct)
+	}
+
+	if len(p.Coupled) != 1 {
+		t.Fatalf("expected 1 coupled file, got %d: %+v", len(p.Coupled), p.Coupled)
+	}
+	cf := p.Coupled[0]
+	if cf.Path != "src/old_test.go" || cf.CoChangeCount != 2 || cf.CouplingRatio != 1.0 {
+		t.Errorf("coupled: got %+v, want {src/old_test.go, 2, 1.0}", cf)
+	}
+}
+
+func TestGetFileProfile_Unknown(t *testing.T) {
+	db := setupDB(t)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	p, err := query.GetFileProfile(db, "missing.go", from, to, query.GranularityMonth, 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("GetFileProfile: %v", err)
+	}
+
+	if len(p.Commits) != 0 || len(p.Authors) != 0 || len(p.Coupled) != 0 || len(p.PreviousPaths) != 0 {
+		t.Errorf("expected empty profile, got %+v", p)
+	}
+}
+
+func setFileStatus(t *testing.T, db *sql.DB, commitHash, filePath, status string) {
+	t.Helper()
+	if _, err := db.Exec(`UPDATE file_stats SET status = ? WHERE commit_hash = ? AND file_path = ?`, status, commitHash, filePath); err != nil {
+		t.Fatalf("set file status: %v", err)
+	}
+}
+
+func TestGetFileProfile_Lifecycle(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC), "add tool.go")
+	insertCommit(t, db, "aaa2", "Alice", "alice@example.com",
+		time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC), "remove tool.go")
+	insertFileStat(t, db, "aaa1", "tool.go", 10, 0)
+	insertFileStat(t, db, "aaa2", "tool.go", 0, 10)
+	setFileStatus(t, db, "aaa1", "tool.go", "A")
+	setFileStatus(t, db, "aaa2", "tool.go", "D")
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	p, err := query.GetFileProfile(db, "tool.go", from, to, query.GranularityMonth, 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("GetFileProfile: %v", err)
+	}
+	if p.Exists || p.Created != "2025-01-10" || p.Deleted != "2025-01-20" {
+		t.Errorf("got exists=%v created=%q deleted=%q, want deleted file created 2025-01-10, deleted 2025-01-20",
+			p.Exists, p.Created, p.Deleted)
+	}
+	if p.Commits[0].Status != "D" || p.Commits[1].Status != "A" {
+		t.Errorf("got statuses %q, %q, want D, A", p.Commits[0].Status, p.Commits[1].Status)
+	}
+
+	insertHeadFile(t, db, "tool.go")
+	p, err = query.GetFileProfile(db, "tool.go", from, to, query.GranularityMonth, 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("GetFileProfile: %v", err)
+	}
+	if !p.Exists || p.Deleted != "" {
+		t.Errorf("got exists=%v deleted=%q, want existing file", p.Exists, p.Deleted)
+	}
+}
+
+func TestGetFileProfile_Generated(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC), "regenerate")
+	insertFileStat(t, db, "aaa1", "api.pb.go", 30, 5)
+	if _, err := db.Exec(`INSERT INTO path_kinds (path, generated) VALUES ('api.pb.go', TRUE)`); err != nil {
+		t.Fatalf("insert path kind: %v", err)
+	}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	// The file was asked for explicitly, so being generated does not hide
+	// its churn.
+	p, err := query.GetFileProfile(db, "api.pb.go", from, to, query.GranularityMonth, 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("GetFileProfile: %v", err)
+	}
+	if len(p.Commits) != 1 {
+		t.Fatalf("expected 1 commit, got %+v", p.Commits)
+	}
+	if len(p.Series) != 1 || p.Series[0].Commits != 1 || p.Series[0].Additions != 30 || p.Series[0].Deletions != 5 {
+		t.Errorf("got series %+v, want 1 commit with +30/-5", p.Series)
+	}
+}
+
+func TestGetFileProfile_ExcludeReverts(t *testing.T) {
+	db := setupDB(t)
+
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 10, 0, 0, 0, time.UTC) }
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com", day(10), "add a")
+	insertCommit(t, db, "aaa2", "Bob", "bob@example.com", day(11), "break a")
+	insertCommit(t, db, "aaa3", "Bob", "bob@example.com", day(12), "Revert \"break a\"")
+	insertFileStat(t, db, "aaa1", "a.go", 10, 0)
+	insertFileStat(t, db, "aaa2", "a.go", 5, 5)
+	insertFileStat(t, db, "aaa3", "a.go", 5, 5)
+	markRevert(t, db, "aaa3", "aaa2")
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	p, err := query.GetFileProfile(db, "a.go", from, to, query.GranularityMonth, 10, query.Filter{ExcludeReverts: true})
+	if err != nil {
+		t.Fatalf("GetFileProfile: %v", err)
+	}
+	if len(p.Commits) != 1 || p.Commits[0].Hash != "aaa1" {
+		t.Fatalf("got commits %+v, want only aaa1", p.Commits)
+	}
+	// The series counts the same commits as the commit list.
+	if len(p.Series) != 1 || p.Series[0].Commits != 1 || p.Series[0].Additions != 10 || p.Series[0].Deletions != 0 {
+		t.Errorf("got series %+v, want 1 commit with +10/-0", p.Series)
+	}
+}
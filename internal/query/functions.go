@@ -0,0 +1,117 @@
+package query
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+)
+
+// FunctionHotspot represents aggregated churn for a single Go function or
+// method as of the indexed HEAD.
+type FunctionHotspot struct {
+	Path         string `json:"path"`
+	Name         string `json:"name"` // F, T.M or (*T).M
+	StartLine    int    `json:"start_line"`
+	EndLine      int    `json:"end_line"`
+	LinesChanged int    `json:"lines_changed"` // lines added, rewritten or removed within the function
+	Commits      int    `json:"commits"`
+}
+
+// FunctionHotspots returns per-function churn and commit counts for commits
+// between from (inclusive) and to (exclusive), ordered by lines_changed
+// descending. Functions are those declared in the Go files at the indexed
+// HEAD (see indexer.IndexFunctions); each one's line range is traced back
+// through the hunks of every commit as in LineHistory, so code that moved
+// within its file or was renamed along with it keeps its history. Functions
+// no commit in range changed are omitted, as are the functions of files that
+// do not parse (see FunctionParseErrors). Only commits kept by filter count,
+// and files it excludes are omitted.
+func FunctionHotspots(db *sql.DB, from, to time.Time, filter Filter) ([]FunctionHotspot, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("f.path")
+	commitSQL, commitArgs := filter.commitClauses()
+
+	rows, err := db.Query(
+		`SELECT f.path, f.name, f.start_line, f.end_line
+		 FROM functions f
+		 JOIN head_files hf ON hf.path = f.path AND hf.blob_hash = f.blob_hash
+		 WHERE 1 = 1`+excludeSQL+`
+		 ORDER BY f.path, f.start_line`, excludeArgs...)
+	if err != nil {
+		return nil, err
+	}
+	var funcs []FunctionHotspot
+	for rows.Next() {
+		var h FunctionHotspot
+		if err := rows.Scan(&h.Path, &h.Name, &h.StartLine, &h.EndLine); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		funcs = append(funcs, h)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	keepArgs := make([]any, 0, len(commitArgs)+2)
+	keepArgs = append(keepArgs, wallClock(from), wallClock(to))
+	keepArgs = append(keepArgs, commitArgs...)
+
+	result := []FunctionHotspot{}
+	var changes []*rangeChange
+	for i, h := range funcs {
+		if i == 0 || h.Path != funcs[i-1].Path {
+			if changes, err = rangeChanges(db, h.Path, commitInRange+commitSQL, keepArgs); err != nil {
+				return nil, err
+			}
+		}
+		traceRange(changes, h.Path, h.StartLine, h.EndLine, func(c *rangeChange, start, end, changed, removed int) {
+			if c.kept {
+				h.LinesChanged += changed + removed
+				h.Commits++
+			}
+		})
+		if h.Commits > 0 {
+			result = append(result, h)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].LinesChanged > result[j].LinesChanged
+	})
+	return result, nil
+}
+
+// FunctionParseError is a Go file at the indexed HEAD whose functions are
+// unknown because it does not parse.
+type FunctionParseError struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// FunctionParseErrors returns the Go files at the indexed HEAD that
+// indexer.IndexFunctions could not parse, ordered by path. Files excluded by
+// filter are omitted.
+func FunctionParseErrors(db *sql.DB, filter Filter) ([]FunctionParseError, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("fb.path")
+	rows, err := db.Query(
+		`SELECT fb.path, fb.parse_error
+		 FROM function_blobs fb
+		 JOIN head_files hf ON hf.path = fb.path AND hf.blob_hash = fb.blob_hash
+		 WHERE fb.parse_error != ''`+excludeSQL+`
+		 ORDER BY fb.path`, excludeArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []FunctionParseError{}
+	for rows.Next() {
+		var e FunctionParseError
+		if err := rows.Scan(&e.Path, &e.Error); err != nil {
+			return nil, err
+		}
+		result = append(result, e)
+	}
+	return result, rows.Err()
+}
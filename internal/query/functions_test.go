@@ -0,0 +1,106 @@
+package query_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func insertFunction(t *testing.T, db *sql.DB, path, blob, name string, start, end int) {
+	t.Helper()
+	_, err := db.Exec(
+		`INSERT INTO functions (path, blob_hash, name, start_line, end_line) VALUES (?, ?, ?, ?, ?)`,
+		path, blob, name, start, end,
+	)
+	if err != nil {
+		t.Fatalf("insert function: %v", err)
+	}
+}
+
+func TestFunctionHotspots(t *testing.T) {
+	db := setupDB(t)
+
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 10, 0, 0, 0, time.UTC) }
+	insertCommit(t, db, "ccc1", "Alice", "alice@example.com", day(1), "add a")
+	insertHunk(t, db, "ccc1", "a.go", 0, 0, 0, 1, 20)
+	// Line 5 rewritten and two lines inserted after line 12.
+	insertCommit(t, db, "ccc2", "Bob", "bob@example.com", day(2), "edit")
+	insertHunk(t, db, "ccc2", "a.go", 0, 5, 1, 5, 1)
+	insertHunk(t, db, "ccc2", "a.go", 1, 12, 0, 13, 2)
+	// Line 21 deleted, after the range.
+	insertCommit(t, db, "ccc3", "Bob", "bob@example.com", day(3), "trim")
+	insertHunk(t, db, "ccc3", "a.go", 0, 21, 1, 20, 0)
+
+	insertHeadFile(t, db, "a.go")
+	insertFunction(t, db, "a.go", "blob-a.go", "F", 3, 8)
+	insertFunction(t, db, "a.go", "blob-a.go", "(*T).G", 10, 17)
+	insertFunction(t, db, "a.go", "blob-a.go", "H", 19, 21)
+	// Functions of blobs no longer at HEAD are ignored.
+	insertFunction(t, db, "a.go", "old-blob", "Old", 1, 20)
+
+	hotspots, err := query.FunctionHotspots(db, day(1), day(3), query.Filter{})
+	if err != nil {
+		t.Fatalf("FunctionHotspots: %v", err)
+	}
+	want := []query.FunctionHotspot{
+		{Path: "a.go", Name: "(*T).G", StartLine: 10, EndLine: 17, LinesChanged: 8, Commits: 2},
+		{Path: "a.go", Name: "F", StartLine: 3, EndLine: 8, LinesChanged: 7, Commits: 2},
+		{Path: "a.go", Name: "H", StartLine: 19, EndLine: 21, LinesChanged: 4, Commits: 1},
+	}
+	if len(hotspots) != len(want) {
+		t.Fatalf("got %+v, want %+v", hotspots, want)
+	}
+	for i := range want {
+		if hotspots[i] != want[i] {
+			t.Errorf("hotspot %d: got %+v, want %+v", i, hotspots[i], want[i])
+		}
+	}
+
+	// The deletion within H counts once its commit is in range.
+	hotspots, err = query.FunctionHotspots(db, day(3), day(4), query.Filter{})
+	if err != nil {
+		t.Fatalf("FunctionHotspots: %v", err)
+	}
+	if len(hotspots) != 1 || hotspots[0].Name != "H" || hotspots[0].LinesChanged != 1 {
+		t.Errorf("got %+v, want H with 1 line removed", hotspots)
+	}
+
+	hotspots, err = query.FunctionHotspots(db, day(1), day(4), query.Filter{ExcludeGlobs: []string{"*.go"}})
+	if err != nil {
+		t.Fatalf("FunctionHotspots: %v", err)
+	}
+	if len(hotspots) != 0 {
+		t.Errorf("expected excluded files omitted, got %+v", hotspots)
+	}
+}
+
+func TestFunctionParseErrors(t *testing.T) {
+	db := setupDB(t)
+
+	insertHeadFile(t, db, "bad.go")
+	insertHeadFile(t, db, "gen/bad.go")
+	insertHeadFile(t, db, "good.go")
+	for _, fb := range []struct{ path, blob, parseError string }{
+		{"bad.go", "blob-bad.go", "1:10: expected name"},
+		{"gen/bad.go", "blob-gen/bad.go", "3:1: expected declaration"},
+		{"good.go", "blob-good.go", ""},
+		// An older blob of good.go that did not parse is not at HEAD.
+		{"good.go", "old", "2:1: expected declaration"},
+	} {
+		if _, err := db.Exec(`INSERT INTO function_blobs (path, blob_hash, parse_error) VALUES (?, ?, ?)`,
+			fb.path, fb.blob, fb.parseError); err != nil {
+			t.Fatalf("insert function blob: %v", err)
+		}
+	}
+
+	errs, err := query.FunctionParseErrors(db, query.Filter{ExcludeGlobs: []string{"gen/"}})
+	if err != nil {
+		t.Fatalf("FunctionParseErrors: %v", err)
+	}
+	want := query.FunctionParseError{Path: "bad.go", Error: "1:10: expected name"}
+	if len(errs) != 1 || errs[0] != want {
+		t.Errorf("got %+v, want %+v", errs, want)
+	}
+}
@@ -12,30 +12,24 @@ type HeatmapDay struct {
 }
 
 // CommitHeatmap returns per-day commit counts between from (inclusive) and to
-// (exclusive). If email is non-empty, results are filtered to that author.
-// Only days with commits are returned (sparse).
-func CommitHeatmap(db *sql.DB, from, to time.Time, email string) ([]HeatmapDay, error) {
-	var rows *sql.Rows
-	var err error
+// (exclusive), bucketed by the author's local calendar day. If email is
+// non-empty, results are filtered to that author. Only days with commits are
+// returned (sparse).
+func CommitHeatmap(db *sql.DB, from, to time.Time, email string, filter Filter) ([]HeatmapDay, error) {
+	commitSQL, commitArgs := filter.commitClauses()
 
-	if email == "" {
-		rows, err = db.Query(
-			`SELECT SUBSTR(committed_at, 1, 10) AS day, COUNT(*) AS count
-			 FROM commits
-			 WHERE committed_at >= ? AND committed_at < ?
-			 GROUP BY day ORDER BY day`,
-			from, to,
-		)
-	} else {
-		rows, err = db.Query(
-			`SELECT SUBSTR(committed_at, 1, 10) AS day, COUNT(*) AS count
-			 FROM commits
-			 WHERE committed_at >= ? AND committed_at < ?
-			   AND author_email = ?
-			 GROUP BY day ORDER BY day`,
-			from, to, email,
-		)
+	q := `SELECT date(` + commitLocalTime + `, 'unixepoch') AS day, COUNT(*) AS count
+	 FROM commits c
+	 WHERE ` + commitInRange + commitSQL
+	args := append([]any{wallClock(from), wallClock(to)}, commitArgs...)
+	if email != "" {
+		q += ` AND c.author_email = ?`
+		args = append(args, email)
 	}
+	q += `
+	 GROUP BY day ORDER BY day`
+
+	rows, err := db.Query(q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -27,9 +27,10 @@ func setupDB(t *testing.T) *sql.DB {
 
 func insertCommit(t *testing.T, db *sql.DB, hash, name, email string, at time.Time, msg string) {
 	t.Helper()
+	_, offset := at.Zone()
 	_, err := db.Exec(
-		`INSERT INTO commits (hash, author_name, author_email, committed_at, message) VALUES (?, ?, ?, ?, ?)`,
-		hash, name, email, at, msg,
+		`INSERT INTO commits (hash, author_name, author_email, committed_at, tz_offset, message) VALUES (?, ?, ?, ?, ?, ?)`,
+		hash, name, email, at.Unix(), offset/60, msg,
 	)
 	if err != nil {
 		t.Fatalf("insert commit: %v", err)
@@ -50,7 +51,7 @@ func TestCommitHeatmap_Basic(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	days, err := query.CommitHeatmap(db, from, to, "")
+	days, err := query.CommitHeatmap(db, from, to, "", query.Filter{})
 	if err != nil {
 		t.Fatalf("CommitHeatmap: %v", err)
 	}
@@ -77,7 +78,7 @@ func TestCommitHeatmap_FilterByEmail(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	days, err := query.CommitHeatmap(db, from, to, "alice@example.com")
+	days, err := query.CommitHeatmap(db, from, to, "alice@example.com", query.Filter{})
 	if err != nil {
 		t.Fatalf("CommitHeatmap: %v", err)
 	}
@@ -96,7 +97,7 @@ func TestCommitHeatmap_Empty(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	days, err := query.CommitHeatmap(db, from, to, "")
+	days, err := query.CommitHeatmap(db, from, to, "", query.Filter{})
 	if err != nil {
 		t.Fatalf("CommitHeatmap: %v", err)
 	}
@@ -105,3 +106,28 @@ func TestCommitHeatmap_Empty(t *testing.T) {
 		t.Errorf("expected 0 days, got %d", len(days))
 	}
 }
+
+func TestCommitHeatmap_AuthorLocalDay(t *testing.T) {
+	db := setupDB(t)
+
+	// 00:30 on Jan 16 in +09:00 is still Jan 15 in UTC; it belongs to the
+	// author's Jan 16.
+	tokyo := time.FixedZone("", 9*60*60)
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 16, 0, 30, 0, 0, tokyo), "just after midnight")
+
+	from := time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)
+
+	days, err := query.CommitHeatmap(db, from, to, "", query.Filter{})
+	if err != nil {
+		t.Fatalf("CommitHeatmap: %v", err)
+	}
+
+	if len(days) != 1 {
+		t.Fatalf("expected 1 day, got %d: %v", len(days), days)
+	}
+	if days[0].Date != "2025-01-16" || days[0].Count != 1 {
+		t.Errorf("got %+v, want {2025-01-16, 1}", days[0])
+	}
+}
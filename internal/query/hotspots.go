@@ -4,10 +4,20 @@ import (
 	"database/sql"
 	"math"
 	"sort"
-	"strings"
 	"time"
 )
 
+// Quadrant places a file by its churn and its complexity relative to the
+// other files of a result.
+type Quadrant string
+
+const (
+	QuadrantHotspot  Quadrant = "hotspot"  // high churn, high complexity
+	QuadrantVolatile Quadrant = "volatile" // high churn, low complexity
+	QuadrantComplex  Quadrant = "complex"  // low churn, high complexity
+	QuadrantCalm     Quadrant = "calm"     // low churn, low complexity
+)
+
 // FileHotspot represents aggregated churn for a single file path.
 type FileHotspot struct {
 	Path         string `json:"path"`
@@ -15,28 +25,43 @@ type FileHotspot struct {
 	Additions    int    `json:"additions"`
 	Deletions    int    `json:"deletions"`
 	Commits      int    `json:"commits"`
+	// Size and complexity of the file at the indexed HEAD (see
+	// indexer.IndexComplexity); zero for files no longer present.
+	Code       int `json:"code"`
+	Comment    int `json:"comment"`
+	Blank      int `json:"blank"`
+	Indent     int `json:"indent"`
+	MaxIndent  int `json:"max_indent"`
+	Cyclomatic int `json:"cyclomatic"`
+	// Quadrant is empty for files without metrics.
+	Quadrant Quadrant `json:"quadrant"`
 }
 
 // FileHotspots returns per-file churn (additions + deletions) and commit counts
 // for commits between from (inclusive) and to (exclusive), ordered by
-// lines_changed descending. Files matching any of the excludeGlobs patterns
-// are omitted from results entirely.
-func FileHotspots(db *sql.DB, from, to time.Time, excludeGlobs []string) ([]FileHotspot, error) {
-	excludeSQL, excludeArgs := buildExcludeClauses("fs.file_path", excludeGlobs)
+// lines_changed descending, with each file's metrics at HEAD and its
+// quadrant by lines changed and indentation complexity. Only commits kept by
+// filter count, and files it excludes are omitted from results entirely.
+func FileHotspots(db *sql.DB, from, to time.Time, filter Filter) ([]FileHotspot, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
 
 	q := `SELECT fs.file_path,
 	        SUM(fs.additions + fs.deletions) AS lines_changed,
 	        SUM(fs.additions) AS additions,
 	        SUM(fs.deletions) AS deletions,
-	        COUNT(DISTINCT fs.commit_hash) AS commits
+	        COUNT(DISTINCT fs.commit_hash) AS commits,
+	        ` + metricColumns + `
 	 FROM file_stats fs
 	 JOIN commits c ON c.hash = fs.commit_hash
-	 WHERE c.committed_at >= ? AND c.committed_at < ?` + excludeSQL + `
+	 ` + metricJoins + `
+	 WHERE ` + commitInRange + commitSQL + excludeSQL + `
 	 GROUP BY fs.file_path
 	 ORDER BY lines_changed DESC`
 
-	args := make([]any, 0, len(excludeArgs)+2)
-	args = append(args, from, to)
+	args := make([]any, 0, len(commitArgs)+len(excludeArgs)+2)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
 	args = append(args, excludeArgs...)
 
 	rows, err := db.Query(q, args...)
@@ -46,14 +71,70 @@ func FileHotspots(db *sql.DB, from, to time.Time, excludeGlobs []string) ([]File
 	defer rows.Close()
 
 	var result []FileHotspot
+	var churn []float64
+	var indent []int
+	var measured []bool
 	for rows.Next() {
 		var h FileHotspot
-		if err := rows.Scan(&h.Path, &h.LinesChanged, &h.Additions, &h.Deletions, &h.Commits); err != nil {
+		var ok bool
+		if err := rows.Scan(&h.Path, &h.LinesChanged, &h.Additions, &h.Deletions, &h.Commits,
+			&ok, &h.Code, &h.Comment, &h.Blank, &h.Indent, &h.MaxIndent, &h.Cyclomatic); err != nil {
 			return nil, err
 		}
 		result = append(result, h)
+		churn = append(churn, float64(h.LinesChanged))
+		indent = append(indent, h.Indent)
+		measured = append(measured, ok)
 	}
-	return result, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, q := range quadrants(churn, indent, measured) {
+		result[i].Quadrant = q
+	}
+	return result, nil
+}
+
+// metricColumns selects whether a file has metrics at HEAD, then the
+// metrics, from the joins in metricJoins on fs.file_path.
+const metricColumns = `m.path IS NOT NULL, COALESCE(m.code, 0), COALESCE(m.comment, 0), COALESCE(m.blank, 0),
+	        COALESCE(m.indent, 0), COALESCE(m.max_indent, 0), COALESCE(m.cyclomatic, 0)`
+
+const metricJoins = `LEFT JOIN head_files hf ON hf.path = fs.file_path
+	 LEFT JOIN file_metrics m ON m.path = hf.path AND m.blob_hash = hf.blob_hash`
+
+// quadrants places each measured file in a quadrant by its churn and its
+// indentation complexity, splitting both at their medians over the measured
+// files; a file is high on an axis if it is above the median.
+func quadrants(churn []float64, indent []int, measured []bool) []Quadrant {
+	var churns, indents []float64
+	for i, ok := range measured {
+		if ok {
+			churns = append(churns, churn[i])
+			indents = append(indents, float64(indent[i]))
+		}
+	}
+	churnMedian, indentMedian := median(churns), median(indents)
+
+	result := make([]Quadrant, len(measured))
+	for i, ok := range measured {
+		if !ok {
+			continue
+		}
+		hot, complex := churn[i] > churnMedian, float64(indent[i]) > indentMedian
+		switch {
+		case hot && complex:
+			result[i] = QuadrantHotspot
+		case hot:
+			result[i] = QuadrantVolatile
+		case complex:
+			result[i] = QuadrantComplex
+		default:
+			result[i] = QuadrantCalm
+		}
+	}
+	return result
 }
 
 // TemporalHotspot extends FileHotspot with recency-weighted scoring.
@@ -66,27 +147,44 @@ type TemporalHotspot struct {
 	LastChanged  string  `json:"last_changed"`
 	DaysSince    int     `json:"days_since"`
 	Score        float64 `json:"score"`
+	// Metrics at HEAD and quadrant as in FileHotspot, with the score as
+	// the churn axis.
+	Code       int      `json:"code"`
+	Comment    int      `json:"comment"`
+	Blank      int      `json:"blank"`
+	Indent     int      `json:"indent"`
+	MaxIndent  int      `json:"max_indent"`
+	Cyclomatic int      `json:"cyclomatic"`
+	Quadrant   Quadrant `json:"quadrant"`
 }
 
 // TemporalHotspots returns per-file churn weighted by recency using exponential
 // decay: score = lines_changed * e^(-λ * daysSince) where λ = ln(2)/halfLifeDays.
 // Results are ordered by score descending. The reference time for recency is `to`.
-func TemporalHotspots(db *sql.DB, from, to time.Time, halfLifeDays float64, excludeGlobs []string) ([]TemporalHotspot, error) {
-	excludeSQL, excludeArgs := buildExcludeClauses("fs.file_path", excludeGlobs)
+// Commits and files are narrowed by filter as in FileHotspots.
+func TemporalHotspots(db *sql.DB, from, to time.Time, halfLifeDays float64, filter Filter) ([]TemporalHotspot, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
 
+	// With a single MAX() aggregate, SQLite takes the bare tz_offset column
+	// from the same row, giving the latest commit's author offset.
 	q := `SELECT fs.file_path,
 	        SUM(fs.additions + fs.deletions) AS lines_changed,
 	        SUM(fs.additions) AS additions,
 	        SUM(fs.deletions) AS deletions,
 	        COUNT(DISTINCT fs.commit_hash) AS commits,
-	        MAX(c.committed_at) AS last_committed_at
+	        MAX(c.committed_at) AS last_committed_at,
+	        c.tz_offset,
+	        ` + metricColumns + `
 	 FROM file_stats fs
 	 JOIN commits c ON c.hash = fs.commit_hash
-	 WHERE c.committed_at >= ? AND c.committed_at < ?` + excludeSQL + `
+	 ` + metricJoins + `
+	 WHERE ` + commitInRange + commitSQL + excludeSQL + `
 	 GROUP BY fs.file_path`
 
-	args := make([]any, 0, len(excludeArgs)+2)
-	args = append(args, from, to)
+	args := make([]any, 0, len(commitArgs)+len(excludeArgs)+2)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
 	args = append(args, excludeArgs...)
 
 	rows, err := db.Query(q, args...)
@@ -98,27 +196,20 @@ func TemporalHotspots(db *sql.DB, from, to time.Time, halfLifeDays float64, excl
 	lambda := math.Ln2 / halfLifeDays
 
 	var result []TemporalHotspot
+	var scores []float64
+	var indent []int
+	var measured []bool
 	for rows.Next() {
 		var h TemporalHotspot
-		var lastCommittedAt string
-		if err := rows.Scan(&h.Path, &h.LinesChanged, &h.Additions, &h.Deletions, &h.Commits, &lastCommittedAt); err != nil {
+		var lastCommittedAt int64
+		var offsetMinutes int
+		var ok bool
+		if err := rows.Scan(&h.Path, &h.LinesChanged, &h.Additions, &h.Deletions, &h.Commits, &lastCommittedAt, &offsetMinutes,
+			&ok, &h.Code, &h.Comment, &h.Blank, &h.Indent, &h.MaxIndent, &h.Cyclomatic); err != nil {
 			return nil, err
 		}
 
-		lastTime, err := time.Parse(time.RFC3339, lastCommittedAt)
-		if err != nil {
-			// modernc.org/sqlite serializes time.Time via Go's String() method:
-			// "2006-01-02 15:04:05 +0000 UTC" or "2006-01-02 15:04:05 +0900 +0900".
-			// Strip the trailing tz name so we can parse the numeric offset alone.
-			trimmed := lastCommittedAt
-			if idx := strings.LastIndex(trimmed, " "); idx > 0 {
-				trimmed = trimmed[:idx]
-			}
-			lastTime, err = time.Parse("2006-01-02 15:04:05 -0700", trimmed)
-			if err != nil {
-				return nil, err
-			}
-		}
+		lastTime := time.Unix(lastCommittedAt, 0).In(time.FixedZone("", offsetMinutes*60))
 
 		daysSince := to.Sub(lastTime).Hours() / 24
 		if daysSince < 0 {
@@ -129,11 +220,18 @@ func TemporalHotspots(db *sql.DB, from, to time.Time, halfLifeDays float64, excl
 		h.Score = float64(h.LinesChanged) * math.Exp(-lambda*daysSince)
 
 		result = append(result, h)
+		scores = append(scores, h.Score)
+		indent = append(indent, h.Indent)
+		measured = append(measured, ok)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
+	for i, q := range quadrants(scores, indent, measured) {
+		result[i].Quadrant = q
+	}
+
 	sort.Slice(result, func(i, j int) bool {
 		return result[i].Score > result[j].Score
 	})
@@ -3,6 +3,8 @@ package query_test
 import (
 	"database/sql"
 	"math"
+	"slices"
+	"sort"
 	"testing"
 	"time"
 
@@ -38,7 +40,7 @@ func TestFileHotspots_Basic(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	hotspots, err := query.FileHotspots(db, from, to, nil)
+	hotspots, err := query.FileHotspots(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("FileHotspots: %v", err)
 	}
@@ -79,7 +81,7 @@ func TestFileHotspots_DateFiltering(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	hotspots, err := query.FileHotspots(db, from, to, nil)
+	hotspots, err := query.FileHotspots(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("FileHotspots: %v", err)
 	}
@@ -104,7 +106,7 @@ func TestFileHotspots_ExcludeGlobs(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	hotspots, err := query.FileHotspots(db, from, to, []string{"*.pb.go"})
+	hotspots, err := query.FileHotspots(db, from, to, query.Filter{ExcludeGlobs: []string{"*.pb.go"}})
 	if err != nil {
 		t.Fatalf("FileHotspots: %v", err)
 	}
@@ -117,13 +119,54 @@ func TestFileHotspots_ExcludeGlobs(t *testing.T) {
 	}
 }
 
+func TestFileHotspots_IncludeGlobs(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), "first")
+
+	for _, p := range []string{"src/a.go", "src/sub/b.go", "src/a_test.go", "src/sub/b_test.go", "lib/src/c.go", "main.go"} {
+		insertFileStat(t, db, "aaa1", p, 10, 0)
+	}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		filter query.Filter
+		want   []string
+	}{
+		// Anchored include list; "*" does not cross directories.
+		{query.Filter{IncludeGlobs: []string{"/src/*.go"}}, []string{"src/a.go", "src/a_test.go"}},
+		// "**" does, and exclusions with negation apply on top.
+		{query.Filter{
+			IncludeGlobs: []string{"src/**"},
+			ExcludeGlobs: []string{"*_test.go", "!src/sub/b_test.go"},
+		}, []string{"src/a.go", "src/sub/b.go", "src/sub/b_test.go"}},
+	}
+	for _, tt := range tests {
+		hotspots, err := query.FileHotspots(db, from, to, tt.filter)
+		if err != nil {
+			t.Fatalf("FileHotspots: %v", err)
+		}
+		var got []string
+		for _, h := range hotspots {
+			got = append(got, h.Path)
+		}
+		sort.Strings(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%+v: got %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
 func TestFileHotspots_Empty(t *testing.T) {
 	db := setupDB(t)
 
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	hotspots, err := query.FileHotspots(db, from, to, nil)
+	hotspots, err := query.FileHotspots(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("FileHotspots: %v", err)
 	}
@@ -151,7 +194,7 @@ func TestTemporalHotspots_Basic(t *testing.T) {
 	insertFileStat(t, db, "bbb1", "old.go", 50, 50)
 
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
-	hotspots, err := query.TemporalHotspots(db, from, to, 90, nil)
+	hotspots, err := query.TemporalHotspots(db, from, to, 90, query.Filter{})
 	if err != nil {
 		t.Fatalf("TemporalHotspots: %v", err)
 	}
@@ -180,7 +223,7 @@ func TestTemporalHotspots_DecayMath(t *testing.T) {
 	insertFileStat(t, db, "aaa1", "main.go", 50, 50) // 100 lines changed
 
 	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
-	hotspots, err := query.TemporalHotspots(db, from, to, halfLife, nil)
+	hotspots, err := query.TemporalHotspots(db, from, to, halfLife, query.Filter{})
 	if err != nil {
 		t.Fatalf("TemporalHotspots: %v", err)
 	}
@@ -206,7 +249,7 @@ func TestTemporalHotspots_SameDay(t *testing.T) {
 	insertFileStat(t, db, "aaa1", "main.go", 30, 20) // 50 lines
 
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
-	hotspots, err := query.TemporalHotspots(db, from, to, 90, nil)
+	hotspots, err := query.TemporalHotspots(db, from, to, 90, query.Filter{})
 	if err != nil {
 		t.Fatalf("TemporalHotspots: %v", err)
 	}
@@ -235,7 +278,7 @@ func TestTemporalHotspots_DateFiltering(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	hotspots, err := query.TemporalHotspots(db, from, to, 90, nil)
+	hotspots, err := query.TemporalHotspots(db, from, to, 90, query.Filter{})
 	if err != nil {
 		t.Fatalf("TemporalHotspots: %v", err)
 	}
@@ -260,7 +303,7 @@ func TestTemporalHotspots_ExcludeGlobs(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	hotspots, err := query.TemporalHotspots(db, from, to, 90, []string{"*.pb.go"})
+	hotspots, err := query.TemporalHotspots(db, from, to, 90, query.Filter{ExcludeGlobs: []string{"*.pb.go"}})
 	if err != nil {
 		t.Fatalf("TemporalHotspots: %v", err)
 	}
@@ -279,7 +322,7 @@ func TestTemporalHotspots_Empty(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	hotspots, err := query.TemporalHotspots(db, from, to, 90, nil)
+	hotspots, err := query.TemporalHotspots(db, from, to, 90, query.Filter{})
 	if err != nil {
 		t.Fatalf("TemporalHotspots: %v", err)
 	}
@@ -306,7 +349,7 @@ func TestTemporalHotspots_ReordersByScore(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	// Use a very short half-life so old changes decay heavily
-	hotspots, err := query.TemporalHotspots(db, from, to, 5, nil)
+	hotspots, err := query.TemporalHotspots(db, from, to, 5, query.Filter{})
 	if err != nil {
 		t.Fatalf("TemporalHotspots: %v", err)
 	}
@@ -323,3 +366,134 @@ func TestTemporalHotspots_ReordersByScore(t *testing.T) {
 			hotspots[0].Path, hotspots[0].Score, hotspots[1].Score)
 	}
 }
+
+func TestFileHotspots_ExistingOnly(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), "commit")
+	insertFileStat(t, db, "aaa1", "main.go", 10, 5)
+	insertFileStat(t, db, "aaa1", "deleted.go", 50, 0)
+	insertHeadFile(t, db, "main.go")
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	hotspots, err := query.FileHotspots(db, from, to, query.Filter{ExistingOnly: true})
+	if err != nil {
+		t.Fatalf("FileHotspots: %v", err)
+	}
+	if len(hotspots) != 1 || hotspots[0].Path != "main.go" {
+		t.Errorf("expected only main.go, got %+v", hotspots)
+	}
+}
+
+func insertMetrics(t *testing.T, db *sql.DB, path string, code, indent, cyclomatic int) {
+	t.Helper()
+	_, err := db.Exec(
+		`INSERT INTO file_metrics (path, blob_hash, lines, code, comment, blank, indent, max_indent, cyclomatic)
+		 VALUES (?, ?, ?, ?, 0, 0, ?, 1, ?)`,
+		path, "blob-"+path, code, code, indent, cyclomatic,
+	)
+	if err != nil {
+		t.Fatalf("insert metrics: %v", err)
+	}
+}
+
+func TestFileHotspots_Complexity(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), "commit")
+	insertFileStat(t, db, "aaa1", "hot.go", 50, 0)
+	insertFileStat(t, db, "aaa1", "busy.go", 40, 0)
+	insertFileStat(t, db, "aaa1", "tangled.go", 3, 0)
+	insertFileStat(t, db, "aaa1", "calm.go", 2, 0)
+	insertFileStat(t, db, "aaa1", "deleted.go", 100, 0)
+	for _, path := range []string{"hot.go", "busy.go", "tangled.go", "calm.go"} {
+		insertHeadFile(t, db, path)
+	}
+	insertMetrics(t, db, "hot.go", 200, 90, 12)
+	insertMetrics(t, db, "busy.go", 100, 10, 3)
+	insertMetrics(t, db, "tangled.go", 300, 80, 20)
+	insertMetrics(t, db, "calm.go", 20, 5, 1)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	hotspots, err := query.FileHotspots(db, from, to, query.Filter{})
+	if err != nil {
+		t.Fatalf("FileHotspots: %v", err)
+	}
+	want := map[string]query.Quadrant{
+		"hot.go":     query.QuadrantHotspot,
+		"busy.go":    query.QuadrantVolatile,
+		"tangled.go": query.QuadrantComplex,
+		"calm.go":    query.QuadrantCalm,
+		"deleted.go": "",
+	}
+	if len(hotspots) != len(want) {
+		t.Fatalf("expected %d hotspots, got %+v", len(want), hotspots)
+	}
+	for _, h := range hotspots {
+		if h.Quadrant != want[h.Path] {
+			t.Errorf("%s: got quadrant %q, want %q", h.Path, h.Quadrant, want[h.Path])
+		}
+		if h.Path == "hot.go" && (h.Code != 200 || h.Indent != 90 || h.Cyclomatic != 12) {
+			t.Errorf("hot.go: got %+v, want code 200, indent 90, cyclomatic 12", h)
+		}
+	}
+
+	temporal, err := query.TemporalHotspots(db, from, to, 30, query.Filter{})
+	if err != nil {
+		t.Fatalf("TemporalHotspots: %v", err)
+	}
+	for _, h := range temporal {
+		if h.Quadrant != want[h.Path] {
+			t.Errorf("temporal %s: got quadrant %q, want %q", h.Path, h.Quadrant, want[h.Path])
+		}
+	}
+}
+
+func TestFileHotspots_PathKinds(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), "commit")
+	insertFileStat(t, db, "aaa1", "main.go", 10, 5)
+	insertFileStat(t, db, "aaa1", "api.pb.go", 500, 0)
+	insertFileStat(t, db, "aaa1", "vendor/lib.go", 300, 0)
+	insertFileStat(t, db, "aaa1", "README.md", 20, 0)
+	for _, k := range []struct {
+		path, kind string
+	}{{"api.pb.go", "generated"}, {"vendor/lib.go", "vendored"}, {"README.md", "documentation"}} {
+		if _, err := db.Exec(`INSERT INTO path_kinds (path, `+k.kind+`) VALUES (?, TRUE)`, k.path); err != nil {
+			t.Fatalf("insert path kind: %v", err)
+		}
+	}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	paths := func(filter query.Filter) []string {
+		t.Helper()
+		hotspots, err := query.FileHotspots(db, from, to, filter)
+		if err != nil {
+			t.Fatalf("FileHotspots: %v", err)
+		}
+		var result []string
+		for _, h := range hotspots {
+			result = append(result, h.Path)
+		}
+		return result
+	}
+
+	if got := paths(query.Filter{}); len(got) != 2 || got[0] != "README.md" || got[1] != "main.go" {
+		t.Errorf("default: got %v, want README.md and main.go", got)
+	}
+	if got := paths(query.Filter{IncludeGenerated: true, IncludeVendored: true}); len(got) != 4 {
+		t.Errorf("including generated and vendored: got %v, want all 4 files", got)
+	}
+	if got := paths(query.Filter{ExcludeDocumentation: true}); len(got) != 1 || got[0] != "main.go" {
+		t.Errorf("excluding documentation: got %v, want only main.go", got)
+	}
+}
@@ -0,0 +1,241 @@
+package query
+
+import (
+	"database/sql"
+	"time"
+)
+
+// defectIssueSQL matches commits aliased as c that reference an imported
+// issue whose type denotes a bug: "Bug" or "Defect", in any case.
+const defectIssueSQL = `EXISTS (SELECT 1 FROM commit_issues ci
+	JOIN issues i ON i.key = ci.issue_key
+	WHERE ci.commit_hash = c.hash AND lower(i.type) IN ('bug', 'defect'))`
+
+// IssueSummary aggregates the commits referencing one issue. Type, Title,
+// Status, CreatedAt and ResolvedAt are only known for imported issues.
+type IssueSummary struct {
+	Key         string `json:"key"`
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	CreatedAt   string `json:"created_at"`  // "2006-01-02" or empty
+	ResolvedAt  string `json:"resolved_at"` // "2006-01-02" or empty
+	Commits     int    `json:"commits"`
+	Files       int    `json:"files"`
+	Additions   int    `json:"additions"`
+	Deletions   int    `json:"deletions"`
+	FirstCommit string `json:"first_commit"`
+	LastCommit  string `json:"last_commit"`
+}
+
+// IssueCommit is one commit referencing an issue.
+type IssueCommit struct {
+	Hash        string `json:"hash"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	Date        string `json:"date"`
+	Subject     string `json:"subject"`
+	Additions   int    `json:"additions"`
+	Deletions   int    `json:"deletions"`
+}
+
+// issueColumns selects an issue's metadata from commit_issues ci LEFT JOIN
+// issues i, for scanning with scanIssue.
+const issueColumns = `ci.issue_key, COALESCE(i.type, ''), COALESCE(i.title, ''),
+	        COALESCE(i.status, ''), i.created_at, i.resolved_at`
+
+// Issues returns the issues referenced by commits between from (inclusive)
+// and to (exclusive), with their commit and file totals, ordered by commits
+// descending. Commits and files are narrowed by filter.
+func Issues(db *sql.DB, from, to time.Time, filter Filter) ([]IssueSummary, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `WITH file_totals AS (
+    SELECT ci.issue_key, COUNT(DISTINCT fs.file_path) AS files,
+           SUM(fs.additions) AS additions, SUM(fs.deletions) AS deletions
+    FROM commit_issues ci
+    JOIN commits c ON c.hash = ci.commit_hash
+    JOIN file_stats fs ON fs.commit_hash = c.hash
+    WHERE ` + commitInRange + commitSQL + excludeSQL + `
+    GROUP BY ci.issue_key
+)
+SELECT ` + issueColumns + `,
+       COUNT(DISTINCT c.hash),
+       COALESCE(MAX(ft.files), 0), COALESCE(MAX(ft.additions), 0), COALESCE(MAX(ft.deletions), 0),
+       date(MIN(` + commitLocalTime + `), 'unixepoch'),
+       date(MAX(` + commitLocalTime + `), 'unixepoch')
+FROM commit_issues ci
+JOIN commits c ON c.hash = ci.commit_hash
+LEFT JOIN issues i ON i.key = ci.issue_key
+LEFT JOIN file_totals ft ON ft.issue_key = ci.issue_key
+WHERE ` + commitInRange + commitSQL + `
+GROUP BY ci.issue_key
+ORDER BY COUNT(DISTINCT c.hash) DESC, ci.issue_key`
+
+	args := make([]any, 0, 2*len(commitArgs)+len(excludeArgs)+4)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	args = append(args, excludeArgs...)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+
+	return queryIssues(db, q, args)
+}
+
+// FileIssues returns the issues referenced by commits between from
+// (inclusive) and to (exclusive) that changed path, most recently worked on
+// first. Commits, additions and deletions count only changes to the file,
+// while Files counts every file the issue's commits in range changed;
+// renames are followed as in GetFileProfile. Commits are narrowed by filter,
+// which also omits files from Files.
+func FileIssues(db *sql.DB, path string, from, to time.Time, filter Filter) ([]IssueSummary, error) {
+	paths, err := fileLineage(db, path)
+	if err != nil {
+		return nil, err
+	}
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `WITH file_totals AS (
+    SELECT ci.issue_key, COUNT(DISTINCT fs.file_path) AS files
+    FROM commit_issues ci
+    JOIN commits c ON c.hash = ci.commit_hash
+    JOIN file_stats fs ON fs.commit_hash = c.hash
+    WHERE ` + commitInRange + commitSQL + excludeSQL + `
+    GROUP BY ci.issue_key
+)
+SELECT ` + issueColumns + `,
+       COUNT(DISTINCT c.hash), COALESCE(MAX(ft.files), 0), SUM(fs.additions), SUM(fs.deletions),
+       date(MIN(` + commitLocalTime + `), 'unixepoch'),
+       date(MAX(` + commitLocalTime + `), 'unixepoch')
+FROM file_stats fs
+JOIN commits c ON c.hash = fs.commit_hash
+JOIN commit_issues ci ON ci.commit_hash = c.hash
+LEFT JOIN issues i ON i.key = ci.issue_key
+LEFT JOIN file_totals ft ON ft.issue_key = ci.issue_key
+WHERE fs.file_path IN (` + placeholders(len(paths)) + `)
+  AND ` + commitInRange + commitSQL + `
+GROUP BY ci.issue_key
+ORDER BY MAX(c.committed_at) DESC, ci.issue_key`
+
+	args := make([]any, 0, 2*len(commitArgs)+len(excludeArgs)+len(paths)+4)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	args = append(args, excludeArgs...)
+	args = append(args, pathArgs(paths)...)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+
+	return queryIssues(db, q, args)
+}
+
+func queryIssues(db *sql.DB, q string, args []any) ([]IssueSummary, error) {
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []IssueSummary
+	for rows.Next() {
+		var s IssueSummary
+		var createdAt, resolvedAt sql.NullInt64
+		if err := rows.Scan(&s.Key, &s.Type, &s.Title, &s.Status, &createdAt, &resolvedAt,
+			&s.Commits, &s.Files, &s.Additions, &s.Deletions, &s.FirstCommit, &s.LastCommit); err != nil {
+			return nil, err
+		}
+		s.CreatedAt = formatEpochDay(createdAt)
+		s.ResolvedAt = formatEpochDay(resolvedAt)
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+// formatEpochDay formats UTC epoch seconds as "2006-01-02", or returns an
+// empty string for NULL.
+func formatEpochDay(n sql.NullInt64) string {
+	if !n.Valid {
+		return ""
+	}
+	return time.Unix(n.Int64, 0).UTC().Format("2006-01-02")
+}
+
+// IssueCommits returns the commits referencing the issue key, newest first.
+// Additions and deletions exclude files omitted by filter; commits are
+// narrowed by it.
+func IssueCommits(db *sql.DB, key string, filter Filter) ([]IssueCommit, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT c.hash, c.author_name, c.author_email,
+	        date(` + commitLocalTime + `, 'unixepoch'), c.message,
+	        COALESCE(SUM(fs.additions), 0), COALESCE(SUM(fs.deletions), 0)
+	 FROM commit_issues ci
+	 JOIN commits c ON c.hash = ci.commit_hash
+	 LEFT JOIN file_stats fs ON fs.commit_hash = c.hash` + excludeSQL + `
+	 WHERE ci.issue_key = ?` + commitSQL + `
+	 GROUP BY c.hash
+	 ORDER BY c.committed_at DESC`
+
+	args := make([]any, 0, len(excludeArgs)+len(commitArgs)+1)
+	args = append(args, excludeArgs...)
+	args = append(args, key)
+	args = append(args, commitArgs...)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []IssueCommit
+	for rows.Next() {
+		var ic IssueCommit
+		if err := rows.Scan(&ic.Hash, &ic.AuthorName, &ic.AuthorEmail, &ic.Date, &ic.Subject, &ic.Additions, &ic.Deletions); err != nil {
+			return nil, err
+		}
+		result = append(result, ic)
+	}
+	return result, rows.Err()
+}
+
+// IssueFiles returns the files changed by commits referencing the issue key,
+// ordered by lines changed descending. Commits and files are narrowed by
+// filter.
+func IssueFiles(db *sql.DB, key string, filter Filter) ([]FileHotspot, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT fs.file_path,
+	        SUM(fs.additions + fs.deletions) AS lines_changed,
+	        SUM(fs.additions), SUM(fs.deletions),
+	        COUNT(DISTINCT fs.commit_hash)
+	 FROM commit_issues ci
+	 JOIN commits c ON c.hash = ci.commit_hash
+	 JOIN file_stats fs ON fs.commit_hash = c.hash
+	 WHERE ci.issue_key = ?` + commitSQL + excludeSQL + `
+	 GROUP BY fs.file_path
+	 ORDER BY lines_changed DESC, fs.file_path`
+
+	args := make([]any, 0, len(commitArgs)+len(excludeArgs)+1)
+	args = append(args, key)
+	args = append(args, commitArgs...)
+	args = append(args, excludeArgs...)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []FileHotspot
+	for rows.Next() {
+		var h FileHotspot
+		if err := rows.Scan(&h.Path, &h.LinesChanged, &h.Additions, &h.Deletions, &h.Commits); err != nil {
+			return nil, err
+		}
+		result = append(result, h)
+	}
+	return result, rows.Err()
+}
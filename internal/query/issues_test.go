@@ -0,0 +1,135 @@
+package query_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func linkIssue(t *testing.T, db *sql.DB, hash, key string) {
+	t.Helper()
+	if _, err := db.Exec(`INSERT INTO commit_issues (commit_hash, issue_key) VALUES (?, ?)`, hash, key); err != nil {
+		t.Fatalf("insert commit_issue: %v", err)
+	}
+}
+
+func insertIssue(t *testing.T, db *sql.DB, key, issueType string, createdAt, resolvedAt time.Time) {
+	t.Helper()
+	_, err := db.Exec(`INSERT INTO issues (key, type, title, status, created_at, resolved_at) VALUES (?, ?, ?, 'Done', ?, ?)`,
+		key, issueType, "title of "+key, createdAt.Unix(), resolvedAt.Unix())
+	if err != nil {
+		t.Fatalf("insert issue: %v", err)
+	}
+}
+
+func setupIssueDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := setupDB(t)
+	insertCommit(t, db, "c1", "Alice", "alice@example.com", time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC), "PROJ-1 start")
+	insertCommit(t, db, "c2", "Bob", "bob@example.com", time.Date(2025, 1, 8, 10, 0, 0, 0, time.UTC), "PROJ-1 finish")
+	insertCommit(t, db, "c3", "Bob", "bob@example.com", time.Date(2025, 1, 9, 10, 0, 0, 0, time.UTC), "#7 docs")
+	insertFileStat(t, db, "c1", "api/a.go", 10, 0)
+	insertFileStat(t, db, "c1", "api/b.go", 5, 0)
+	insertFileStat(t, db, "c2", "api/a.go", 3, 2)
+	insertFileStat(t, db, "c3", "README.md", 1, 1)
+	linkIssue(t, db, "c1", "PROJ-1")
+	linkIssue(t, db, "c2", "PROJ-1")
+	linkIssue(t, db, "c3", "#7")
+	insertIssue(t, db, "PROJ-1", "Bug",
+		time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC))
+	return db
+}
+
+func TestIssues(t *testing.T) {
+	db := setupIssueDB(t)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	list, err := query.Issues(db, from, to, query.Filter{})
+	if err != nil {
+		t.Fatalf("Issues: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("got %d issues, want 2", len(list))
+	}
+	got := list[0]
+	want := query.IssueSummary{
+		Key: "PROJ-1", Type: "Bug", Title: "title of PROJ-1", Status: "Done",
+		CreatedAt: "2025-01-02", ResolvedAt: "2025-01-10",
+		Commits: 2, Files: 2, Additions: 18, Deletions: 2,
+		FirstCommit: "2025-01-05", LastCommit: "2025-01-08",
+	}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if list[1].Key != "#7" || list[1].Type != "" || list[1].Commits != 1 {
+		t.Errorf("got %+v, want unimported #7 with 1 commit", list[1])
+	}
+
+	excluded, err := query.Issues(db, from, to, query.Filter{ExcludeGlobs: []string{"api/b.go"}})
+	if err != nil {
+		t.Fatalf("Issues with exclude: %v", err)
+	}
+	if excluded[0].Files != 1 || excluded[0].Additions != 13 {
+		t.Errorf("got %+v, want 1 file and 13 additions", excluded[0])
+	}
+}
+
+func TestIssueCommitsAndFiles(t *testing.T) {
+	db := setupIssueDB(t)
+
+	commits, err := query.IssueCommits(db, "PROJ-1", query.Filter{})
+	if err != nil {
+		t.Fatalf("IssueCommits: %v", err)
+	}
+	if len(commits) != 2 || commits[0].Hash != "c2" || commits[1].Additions != 15 {
+		t.Errorf("got %+v, want c2 then c1 with 15 additions", commits)
+	}
+
+	files, err := query.IssueFiles(db, "PROJ-1", query.Filter{})
+	if err != nil {
+		t.Fatalf("IssueFiles: %v", err)
+	}
+	if len(files) != 2 || files[0].Path != "api/a.go" || files[0].Commits != 2 || files[0].LinesChanged != 15 {
+		t.Errorf("got %+v, want api/a.go first with 2 commits and 15 lines", files)
+	}
+}
+
+func TestFileIssues(t *testing.T) {
+	db := setupIssueDB(t)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	list, err := query.FileIssues(db, "api/a.go", from, to, query.Filter{})
+	if err != nil {
+		t.Fatalf("FileIssues: %v", err)
+	}
+	if len(list) != 1 || list[0].Key != "PROJ-1" || list[0].Commits != 2 || list[0].Additions != 13 || list[0].Files != 2 {
+		t.Errorf("got %+v, want PROJ-1 with 2 commits, 13 additions and 2 files", list)
+	}
+
+	list, err = query.FileIssues(db, "api/a.go", from, to, query.Filter{ExcludeGlobs: []string{"api/b.go"}})
+	if err != nil {
+		t.Fatalf("FileIssues: %v", err)
+	}
+	if len(list) != 1 || list[0].Files != 1 {
+		t.Errorf("got %+v, want PROJ-1 with 1 file once api/b.go is excluded", list)
+	}
+}
+
+func TestDefectHotspots_LinkedBugIssues(t *testing.T) {
+	db := setupIssueDB(t)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	// No commit is classified as a fix, but PROJ-1 is an imported bug.
+	hotspots, err := query.DefectHotspots(db, from, to, 30, false, query.Filter{})
+	if err != nil {
+		t.Fatalf("DefectHotspots: %v", err)
+	}
+	if len(hotspots) != 2 || hotspots[0].Path != "api/a.go" || hotspots[0].FixCommits != 2 {
+		t.Errorf("got %+v, want api/a.go and api/b.go with api/a.go fixed twice", hotspots)
+	}
+}
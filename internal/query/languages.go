@@ -0,0 +1,295 @@
+package query
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+)
+
+// languageExpr is the language of the file joined as fl by languageJoin,
+// "Other" for files of unknown language.
+const languageExpr = `COALESCE(fl.language, 'Other')`
+
+// languageJoin joins the languages detected by indexer.IndexLanguages on
+// fs.file_path.
+const languageJoin = `LEFT JOIN file_languages fl ON fl.path = fs.file_path`
+
+// LanguageStat summarizes the files of one language.
+type LanguageStat struct {
+	Language string `json:"language"`
+	// Files and lines at the indexed HEAD; lines need the metrics of
+	// indexer.IndexComplexity.
+	Files   int `json:"files"`
+	Code    int `json:"code"`
+	Comment int `json:"comment"`
+	Blank   int `json:"blank"`
+	// Churn of commits in the requested range.
+	LinesChanged int `json:"lines_changed"`
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+	Commits      int `json:"commits"`
+}
+
+// LanguageBreakdown returns per-language files and lines of code at HEAD and
+// churn for commits between from (inclusive) and to (exclusive), ordered by
+// lines of code descending, then by lines changed. Files are assigned the
+// languages detected by indexer.IndexLanguages, and files of unknown
+// language are counted as "Other". Only commits kept by filter count, and
+// files it excludes are omitted.
+func LanguageBreakdown(db *sql.DB, from, to time.Time, filter Filter) ([]LanguageStat, error) {
+	stats := make(map[string]*LanguageStat)
+	stat := func(lang string) *LanguageStat {
+		s, ok := stats[lang]
+		if !ok {
+			s = &LanguageStat{Language: lang}
+			stats[lang] = s
+		}
+		return s
+	}
+
+	excludeSQL, excludeArgs := filter.fileClauses("hf.path")
+	rows, err := db.Query(
+		`SELECT `+languageExpr+`, COUNT(*),
+		        COALESCE(SUM(m.code), 0), COALESCE(SUM(m.comment), 0), COALESCE(SUM(m.blank), 0)
+		 FROM head_files hf
+		 LEFT JOIN file_languages fl ON fl.path = hf.path
+		 LEFT JOIN file_metrics m ON m.path = hf.path AND m.blob_hash = hf.blob_hash
+		 WHERE 1 = 1`+excludeSQL+`
+		 GROUP BY 1`, excludeArgs...)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var lang string
+		var s LanguageStat
+		if err := rows.Scan(&lang, &s.Files, &s.Code, &s.Comment, &s.Blank); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		dst := stat(lang)
+		dst.Files, dst.Code, dst.Comment, dst.Blank = s.Files, s.Code, s.Comment, s.Blank
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	excludeSQL, excludeArgs = filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+	q := `SELECT ` + languageExpr + `, SUM(fs.additions), SUM(fs.deletions), COUNT(DISTINCT fs.commit_hash)
+	 FROM file_stats fs
+	 JOIN commits c ON c.hash = fs.commit_hash
+	 ` + languageJoin + `
+	 WHERE ` + commitInRange + commitSQL + excludeSQL + `
+	 GROUP BY 1`
+	args := make([]any, 0, len(commitArgs)+len(excludeArgs)+2)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	args = append(args, excludeArgs...)
+
+	rows, err = db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var lang string
+		var additions, deletions, commits int
+		if err := rows.Scan(&lang, &additions, &deletions, &commits); err != nil {
+			return nil, err
+		}
+		s := stat(lang)
+		s.Additions, s.Deletions, s.Commits = additions, deletions, commits
+		s.LinesChanged = additions + deletions
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]LanguageStat, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Code != b.Code {
+			return a.Code > b.Code
+		}
+		if a.LinesChanged != b.LinesChanged {
+			return a.LinesChanged > b.LinesChanged
+		}
+		return a.Language < b.Language
+	})
+	return result, nil
+}
+
+// LanguagePoint is the churn of one language in one time bucket.
+type LanguagePoint struct {
+	Period       string `json:"period"`
+	LinesChanged int    `json:"lines_changed"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+	Commits      int    `json:"commits"`
+}
+
+// LanguageSeries is the churn of one language over time.
+type LanguageSeries struct {
+	Language     string          `json:"language"`
+	LinesChanged int             `json:"lines_changed"` // over the whole range
+	Points       []LanguagePoint `json:"points"`
+}
+
+// LanguageActivity returns per-language churn per bucket of the given
+// granularity for commits between from (inclusive) and to (exclusive), with
+// languages as in LanguageBreakdown. Every bucket in the range is returned
+// in chronological order, and series are ordered by lines changed
+// descending. Only commits kept by filter count, and files it excludes are
+// omitted.
+func LanguageActivity(db *sql.DB, from, to time.Time, g Granularity, filter Filter) ([]LanguageSeries, error) {
+	period, err := periodExpr(g)
+	if err != nil {
+		return nil, err
+	}
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT ` + languageExpr + `, ` + period + `,
+	        SUM(fs.additions), SUM(fs.deletions), COUNT(DISTINCT fs.commit_hash)
+	 FROM file_stats fs
+	 JOIN commits c ON c.hash = fs.commit_hash
+	 ` + languageJoin + `
+	 WHERE ` + commitInRange + commitSQL + excludeSQL + `
+	 GROUP BY 1, 2`
+	args := make([]any, 0, len(commitArgs)+len(excludeArgs)+2)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	args = append(args, excludeArgs...)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	labels := periodLabels(from, to, g)
+	index := make(map[string]int, len(labels))
+	for i, label := range labels {
+		index[label] = i
+	}
+	series := make(map[string]*LanguageSeries)
+	for rows.Next() {
+		var lang, p string
+		var additions, deletions, commits int
+		if err := rows.Scan(&lang, &p, &additions, &deletions, &commits); err != nil {
+			return nil, err
+		}
+		s, ok := series[lang]
+		if !ok {
+			s = &LanguageSeries{Language: lang, Points: make([]LanguagePoint, len(labels))}
+			for i, label := range labels {
+				s.Points[i].Period = label
+			}
+			series[lang] = s
+		}
+		s.LinesChanged += additions + deletions
+		if i, ok := index[p]; ok {
+			s.Points[i] = LanguagePoint{
+				Period: p, LinesChanged: additions + deletions,
+				Additions: additions, Deletions: deletions, Commits: commits,
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]LanguageSeries, 0, len(series))
+	for _, s := range series {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].LinesChanged != result[j].LinesChanged {
+			return result[i].LinesChanged > result[j].LinesChanged
+		}
+		return result[i].Language < result[j].Language
+	})
+	return result, nil
+}
+
+// AuthorLanguageMix is the share of each language in one author's churn.
+type AuthorLanguageMix struct {
+	AuthorName   string          `json:"author_name"`
+	AuthorEmail  string          `json:"author_email"`
+	LinesChanged int             `json:"lines_changed"`
+	Languages    []LanguageShare `json:"languages"` // by lines changed, descending
+}
+
+// AuthorLanguages returns the language mix of every author's changes for
+// commits between from (inclusive) and to (exclusive), with languages as in
+// LanguageBreakdown, ordered by lines changed descending. Only commits kept
+// by filter count, and files it excludes are omitted.
+func AuthorLanguages(db *sql.DB, from, to time.Time, filter Filter) ([]AuthorLanguageMix, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT c.author_email, MAX(c.author_name), ` + languageExpr + `,
+	        COUNT(DISTINCT fs.file_path), SUM(fs.additions + fs.deletions)
+	 FROM file_stats fs
+	 JOIN commits c ON c.hash = fs.commit_hash
+	 ` + languageJoin + `
+	 WHERE ` + commitInRange + commitSQL + excludeSQL + `
+	 GROUP BY 1, 3`
+	args := make([]any, 0, len(commitArgs)+len(excludeArgs)+2)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	args = append(args, excludeArgs...)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	authors := make(map[string]*AuthorLanguageMix)
+	for rows.Next() {
+		var email, name string
+		var l LanguageShare
+		if err := rows.Scan(&email, &name, &l.Language, &l.Files, &l.LinesChanged); err != nil {
+			return nil, err
+		}
+		a, ok := authors[email]
+		if !ok {
+			a = &AuthorLanguageMix{AuthorEmail: email}
+			authors[email] = a
+		}
+		a.AuthorName = max(a.AuthorName, name)
+		a.LinesChanged += l.LinesChanged
+		a.Languages = append(a.Languages, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]AuthorLanguageMix, 0, len(authors))
+	for _, a := range authors {
+		for i := range a.Languages {
+			if a.LinesChanged > 0 {
+				a.Languages[i].Pct = float64(a.Languages[i].LinesChanged) / float64(a.LinesChanged) * 100
+			}
+		}
+		sort.Slice(a.Languages, func(i, j int) bool {
+			if a.Languages[i].LinesChanged != a.Languages[j].LinesChanged {
+				return a.Languages[i].LinesChanged > a.Languages[j].LinesChanged
+			}
+			return a.Languages[i].Language < a.Languages[j].Language
+		})
+		result = append(result, *a)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].LinesChanged != result[j].LinesChanged {
+			return result[i].LinesChanged > result[j].LinesChanged
+		}
+		return result[i].AuthorEmail < result[j].AuthorEmail
+	})
+	return result, nil
+}
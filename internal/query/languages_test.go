@@ -0,0 +1,112 @@
+package query_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func insertLanguage(t *testing.T, db *sql.DB, path, lang string) {
+	t.Helper()
+	if _, err := db.Exec(`INSERT INTO file_languages (path, language) VALUES (?, ?)`, path, lang); err != nil {
+		t.Fatalf("insert language: %v", err)
+	}
+}
+
+// setupLanguagesDB records a migration from Python to Go over two months.
+func setupLanguagesDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com", time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC), "python")
+	insertFileStat(t, db, "aaa1", "tool.py", 100, 0)
+	insertFileStat(t, db, "aaa1", "bin/run", 10, 0)
+	insertCommit(t, db, "aaa2", "Bob", "bob@example.com", time.Date(2025, 2, 5, 10, 0, 0, 0, time.UTC), "port")
+	insertFileStat(t, db, "aaa2", "tool.py", 0, 60)
+	insertFileStat(t, db, "aaa2", "tool.go", 80, 0)
+	insertFileStat(t, db, "aaa2", "LICENSE", 20, 0)
+
+	for _, p := range []string{"tool.py", "tool.go", "bin/run", "LICENSE"} {
+		insertHeadFile(t, db, p)
+	}
+	insertMetrics(t, db, "tool.py", 40, 0, 0)
+	insertMetrics(t, db, "tool.go", 80, 0, 0)
+	insertMetrics(t, db, "bin/run", 10, 0, 0)
+	insertLanguage(t, db, "tool.py", "Python")
+	insertLanguage(t, db, "bin/run", "Python")
+	insertLanguage(t, db, "tool.go", "Go")
+	return db
+}
+
+func TestLanguageBreakdown(t *testing.T) {
+	db := setupLanguagesDB(t)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	stats, err := query.LanguageBreakdown(db, from, to, query.Filter{})
+	if err != nil {
+		t.Fatalf("LanguageBreakdown: %v", err)
+	}
+	want := []query.LanguageStat{
+		{Language: "Go", Files: 1, Code: 80, LinesChanged: 80, Additions: 80, Commits: 1},
+		{Language: "Python", Files: 2, Code: 50, LinesChanged: 170, Additions: 110, Deletions: 60, Commits: 2},
+		{Language: "Other", Files: 1, LinesChanged: 20, Additions: 20, Commits: 1},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("got %+v, want %+v", stats, want)
+	}
+	for i := range want {
+		if stats[i] != want[i] {
+			t.Errorf("language %d: got %+v, want %+v", i, stats[i], want[i])
+		}
+	}
+}
+
+func TestLanguageActivity(t *testing.T) {
+	db := setupLanguagesDB(t)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	series, err := query.LanguageActivity(db, from, to, query.GranularityMonth, query.Filter{ExcludeGlobs: []string{"LICENSE"}})
+	if err != nil {
+		t.Fatalf("LanguageActivity: %v", err)
+	}
+	if len(series) != 2 || series[0].Language != "Python" || series[1].Language != "Go" {
+		t.Fatalf("got %+v, want Python then Go", series)
+	}
+	py := series[0]
+	if py.LinesChanged != 170 || len(py.Points) != 2 || py.Points[0].LinesChanged != 110 || py.Points[1].Deletions != 60 {
+		t.Errorf("Python: got %+v, want 110 lines in January and 60 deleted in February", py)
+	}
+	goSeries := series[1]
+	if len(goSeries.Points) != 2 || goSeries.Points[0].Period != "2025-01" || goSeries.Points[0].LinesChanged != 0 || goSeries.Points[1].Additions != 80 {
+		t.Errorf("Go: got %+v, want an empty January and 80 lines in February", goSeries)
+	}
+}
+
+func TestAuthorLanguages(t *testing.T) {
+	db := setupLanguagesDB(t)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mixes, err := query.AuthorLanguages(db, from, to, query.Filter{})
+	if err != nil {
+		t.Fatalf("AuthorLanguages: %v", err)
+	}
+	if len(mixes) != 2 {
+		t.Fatalf("expected 2 authors, got %+v", mixes)
+	}
+	bob := mixes[0]
+	if bob.AuthorEmail != "bob@example.com" || bob.LinesChanged != 160 || len(bob.Languages) != 3 {
+		t.Fatalf("got %+v, want Bob with 160 lines in 3 languages", bob)
+	}
+	if l := bob.Languages[0]; l.Language != "Go" || l.LinesChanged != 80 || l.Pct != 50 {
+		t.Errorf("got %+v, want Go with half of Bob's lines", l)
+	}
+	alice := mixes[1]
+	if len(alice.Languages) != 1 || alice.Languages[0].Language != "Python" || alice.Languages[0].Files != 2 || alice.Languages[0].Pct != 100 {
+		t.Errorf("got %+v, want Alice all in Python across 2 files", alice)
+	}
+}
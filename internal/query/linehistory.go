@@ -0,0 +1,189 @@
+package query
+
+import (
+	"database/sql"
+)
+
+// LineRangeCommit is a commit that changed a tracked range of lines.
+type LineRangeCommit struct {
+	Hash        string `json:"hash"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	Date        string `json:"date"`
+	Subject     string `json:"subject"`
+	Path        string `json:"path"`    // the file's path as of this commit
+	Start       int    `json:"start"`   // first line of the range after this commit
+	End         int    `json:"end"`     // last line of the range after this commit
+	Changed     int    `json:"changed"` // lines of the range the commit added or rewrote
+	Removed     int    `json:"removed"` // lines the commit deleted from within the range
+	// Coalesced is true if the commit's changes to the file were stored as
+	// one hunk spanning them, so Changed may overstate them.
+	Coalesced bool `json:"coalesced"`
+}
+
+// LineHistory returns the commits that changed lines start to end (1-based,
+// inclusive) of path as of the indexed HEAD, newest first, like git log -L.
+// The range is traced back through each commit's hunks (see
+// indexer.IndexHunks), widening it to the old lines a commit rewrote, and
+// follows renames. Commits that only deleted lines from between lines of the
+// range are returned too, as git log -L does. Tracing stops once every line
+// of the range was added by
+// a commit. Only commits kept by filter are returned, but every commit is
+// traced through.
+func LineHistory(db *sql.DB, path string, start, end int, filter Filter) ([]LineRangeCommit, error) {
+	commitSQL, commitArgs := filter.commitClauses()
+	changes, err := rangeChanges(db, path, "1 = 1"+commitSQL, commitArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []LineRangeCommit{}
+	traceRange(changes, path, start, end, func(c *rangeChange, start, end, changed, removed int) {
+		if !c.kept {
+			return
+		}
+		lc := c.LineRangeCommit
+		lc.Start, lc.End, lc.Changed, lc.Removed = start, end, changed, removed
+		result = append(result, lc)
+	})
+	return result, nil
+}
+
+// rangeChange is one commit's hunks of one file, as traced by traceRange.
+type rangeChange struct {
+	LineRangeCommit
+	kept    bool // whether the keep expression given to rangeChanges held
+	oldPath string
+	hunks   []storedHunk
+}
+
+// rangeChanges loads the hunks of path and of the names it had before, per
+// commit and file, newest first. keep is an SQL expression over the commit
+// c, with its arguments, recorded as each change's kept.
+func rangeChanges(db *sql.DB, path, keep string, keepArgs []any) ([]*rangeChange, error) {
+	paths, err := fileLineage(db, path)
+	if err != nil {
+		return nil, err
+	}
+	in := placeholders(len(paths))
+
+	// Renames without content changes have no hunks, so each rename also
+	// contributes a row with seq -1.
+	q := `SELECT c.hash, c.author_name, c.author_email, date(` + commitLocalTime + `, 'unixepoch'), c.message,
+	        (` + keep + `),
+	        h.file_path, COALESCE(fs.old_path, ''), h.seq, h.old_start, h.old_lines, h.new_start, h.new_lines, h.coalesced
+	 FROM (
+	     SELECT commit_hash, file_path, seq, old_start, old_lines, new_start, new_lines, coalesced
+	     FROM hunks WHERE file_path IN (` + in + `)
+	     UNION ALL
+	     SELECT commit_hash, file_path, -1, 0, 0, 0, 0, 0
+	     FROM file_stats WHERE file_path IN (` + in + `) AND old_path != ''
+	 ) h
+	 JOIN commits c ON c.hash = h.commit_hash
+	 LEFT JOIN file_stats fs ON fs.commit_hash = h.commit_hash AND fs.file_path = h.file_path
+	 ORDER BY c.committed_at DESC, c.hash, h.file_path, h.seq`
+	args := make([]any, 0, len(keepArgs)+2*len(paths))
+	args = append(args, keepArgs...)
+	args = append(args, pathArgs(paths)...)
+	args = append(args, pathArgs(paths)...)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*rangeChange
+	for rows.Next() {
+		var lc LineRangeCommit
+		var kept, coalesced bool
+		var filePath, oldPath string
+		var seq int
+		var h storedHunk
+		if err := rows.Scan(&lc.Hash, &lc.AuthorName, &lc.AuthorEmail, &lc.Date, &lc.Subject, &kept,
+			&filePath, &oldPath, &seq, &h.oldStart, &h.oldLines, &h.newStart, &h.newLines, &coalesced); err != nil {
+			return nil, err
+		}
+		if n := len(changes); n == 0 || changes[n-1].Hash != lc.Hash || changes[n-1].Path != filePath {
+			lc.Path = filePath
+			changes = append(changes, &rangeChange{LineRangeCommit: lc, kept: kept})
+		}
+		c := changes[len(changes)-1]
+		if seq < 0 {
+			c.oldPath = oldPath
+			continue
+		}
+		c.Coalesced = c.Coalesced || coalesced
+		c.hunks = append(c.hunks, h)
+	}
+	return changes, rows.Err()
+}
+
+// traceRange traces lines start to end of path back through changes, as
+// loaded by rangeChanges, calling visit with each change that added,
+// rewrote or removed lines within the range, the range after the change,
+// and the number of lines it changed and removed there.
+func traceRange(changes []*rangeChange, path string, start, end int, visit func(c *rangeChange, start, end, changed, removed int)) {
+	current := path
+	for _, c := range changes {
+		if start > end {
+			break
+		}
+		if c.Path != current {
+			continue
+		}
+		changed, removed := 0, 0
+		for _, h := range c.hunks {
+			if h.newLines > 0 {
+				changed += max(0, min(end, h.newStart+h.newLines-1)-max(start, h.newStart)+1)
+			} else if h.newStart >= start && h.newStart < end {
+				// A pure deletion between two lines of the range.
+				removed += h.oldLines
+			}
+		}
+		if changed > 0 || removed > 0 {
+			visit(c, start, end, changed, removed)
+		}
+		start, end = oldLine(c.hunks, start, true), oldLine(c.hunks, end, false)
+		if c.oldPath != "" {
+			current = c.oldPath
+		}
+	}
+}
+
+// oldLine maps line, the first (or, if first is false, the last) line of a
+// range in a file after a commit, to the corresponding line before it, given
+// the commit's hunks of the file. A line the commit added or rewrote maps to
+// the first (or last) line the hunk replaced, or, when the hunk replaced
+// nothing, to the line after (or before) it.
+func oldLine(hunks []storedHunk, line int, first bool) int {
+	delta := 0
+	for _, h := range hunks {
+		if h.newLines == 0 {
+			// A pure deletion lies between new lines newStart and newStart+1.
+			if line <= h.newStart {
+				break
+			}
+			delta += h.oldLines
+			continue
+		}
+		if line < h.newStart {
+			break
+		}
+		if line >= h.newStart+h.newLines {
+			delta += h.oldLines - h.newLines
+			continue
+		}
+		switch {
+		case h.oldLines == 0 && first:
+			return h.oldStart + 1
+		case h.oldLines == 0:
+			return h.oldStart
+		case first:
+			return h.oldStart
+		default:
+			return h.oldStart + h.oldLines - 1
+		}
+	}
+	return line + delta
+}
@@ -0,0 +1,78 @@
+package query_test
+
+import (
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func TestLineHistory(t *testing.T) {
+	db := setupDB(t)
+
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 10, 0, 0, 0, time.UTC) }
+	insertCommit(t, db, "ccc1", "Alice", "alice@example.com", day(1), "add a")
+	insertHunk(t, db, "ccc1", "a.go", 0, 0, 0, 1, 10)
+	// Two lines inserted after line 3.
+	insertCommit(t, db, "ccc2", "Bob", "bob@example.com", day(2), "insert")
+	insertHunk(t, db, "ccc2", "a.go", 0, 3, 0, 4, 2)
+	// Line 8, originally line 6, rewritten.
+	insertCommit(t, db, "ccc3", "Bob", "bob@example.com", day(3), "rewrite")
+	insertHunk(t, db, "ccc3", "a.go", 0, 8, 1, 8, 1)
+	insertCommit(t, db, "ccc4", "Alice", "alice@example.com", day(4), "rename")
+	insertRename(t, db, "ccc4", "a.go", "b.go", 0, 0)
+	// Line 1 deleted.
+	insertCommit(t, db, "ccc5", "Alice", "alice@example.com", day(5), "trim")
+	insertHunk(t, db, "ccc5", "b.go", 0, 1, 1, 0, 0)
+
+	history, err := query.LineHistory(db, "b.go", 7, 7, query.Filter{})
+	if err != nil {
+		t.Fatalf("LineHistory: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 commits, got %+v", history)
+	}
+	if h := history[0]; h.Hash != "ccc3" || h.Path != "a.go" || h.Start != 8 || h.End != 8 || h.Changed != 1 || h.Subject != "rewrite" {
+		t.Errorf("got %+v, want ccc3 rewriting line 8 of a.go", h)
+	}
+	if h := history[1]; h.Hash != "ccc1" || h.Start != 6 || h.Changed != 1 || h.Date != "2025-01-01" {
+		t.Errorf("got %+v, want ccc1 adding line 6", h)
+	}
+
+	// HEAD lines 4 to 6 are the second inserted line and original lines 4
+	// and 5.
+	history, err = query.LineHistory(db, "b.go", 4, 6, query.Filter{})
+	if err != nil {
+		t.Fatalf("LineHistory: %v", err)
+	}
+	if len(history) != 2 || history[0].Hash != "ccc2" || history[0].Changed != 1 || history[1].Hash != "ccc1" || history[1].Start != 4 || history[1].End != 5 || history[1].Changed != 2 {
+		t.Errorf("got %+v, want ccc2 with 1 line and ccc1 with lines 4 to 5", history)
+	}
+}
+
+func TestLineHistory_Deletion(t *testing.T) {
+	db := setupDB(t)
+
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 10, 0, 0, 0, time.UTC) }
+	insertCommit(t, db, "ddd1", "Alice", "alice@example.com", day(1), "add a")
+	insertHunk(t, db, "ddd1", "a.go", 0, 0, 0, 1, 10)
+	// Lines 4 and 5 deleted, leaving no line of the range rewritten.
+	insertCommit(t, db, "ddd2", "Bob", "bob@example.com", day(2), "delete")
+	insertHunk(t, db, "ddd2", "a.go", 0, 4, 2, 3, 0)
+
+	// HEAD lines 2 to 5 were lines 2, 3, 6 and 7, so ddd2 changed the range
+	// even though it left none of its lines behind.
+	history, err := query.LineHistory(db, "a.go", 2, 5, query.Filter{})
+	if err != nil {
+		t.Fatalf("LineHistory: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 commits, got %+v", history)
+	}
+	if h := history[0]; h.Hash != "ddd2" || h.Changed != 0 || h.Removed != 2 {
+		t.Errorf("got %+v, want ddd2 removing 2 lines", h)
+	}
+	if h := history[1]; h.Hash != "ddd1" || h.Start != 2 || h.End != 7 || h.Changed != 6 || h.Removed != 0 {
+		t.Errorf("got %+v, want ddd1 adding lines 2 to 7", h)
+	}
+}
@@ -22,17 +22,18 @@ type FileOwnership struct {
 
 // FileOwnerships returns per-file ownership analysis for commits between from
 // (inclusive) and to (exclusive). Results are sorted by top_author_pct descending
-// (highest concentration of ownership first). Files matching any of the
-// excludeGlobs patterns are omitted.
-func FileOwnerships(db *sql.DB, from, to time.Time, excludeGlobs []string) ([]FileOwnership, error) {
-	excludeSQL, excludeArgs := buildExcludeClauses("fs.file_path", excludeGlobs)
+// (highest concentration of ownership first). Only commits kept by filter
+// count, and files it excludes are omitted.
+func FileOwnerships(db *sql.DB, from, to time.Time, filter Filter) ([]FileOwnership, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
 
 	q := `WITH file_author AS (
     SELECT fs.file_path, c.author_email, MAX(c.author_name) AS author_name,
            SUM(fs.additions + fs.deletions) AS lines_changed
     FROM file_stats fs
     JOIN commits c ON c.hash = fs.commit_hash
-    WHERE c.committed_at >= ? AND c.committed_at < ?` + excludeSQL + `
+    WHERE ` + commitInRange + commitSQL + excludeSQL + `
     GROUP BY fs.file_path, c.author_email
 )
 SELECT file_path, author_email, author_name, lines_changed,
@@ -41,8 +42,9 @@ SELECT file_path, author_email, author_name, lines_changed,
 FROM file_author
 ORDER BY file_path, lines_changed DESC`
 
-	args := make([]any, 0, len(excludeArgs)+2)
-	args = append(args, from, to)
+	args := make([]any, 0, len(commitArgs)+len(excludeArgs)+2)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
 	args = append(args, excludeArgs...)
 
 	rows, err := db.Query(q, args...)
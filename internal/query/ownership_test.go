@@ -23,7 +23,7 @@ func TestFileOwnerships_Basic(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	results, err := query.FileOwnerships(db, from, to, nil)
+	results, err := query.FileOwnerships(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("FileOwnerships: %v", err)
 	}
@@ -67,7 +67,7 @@ func TestFileOwnerships_SingleContributor(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	results, err := query.FileOwnerships(db, from, to, nil)
+	results, err := query.FileOwnerships(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("FileOwnerships: %v", err)
 	}
@@ -106,7 +106,7 @@ func TestFileOwnerships_DateFiltering(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	results, err := query.FileOwnerships(db, from, to, nil)
+	results, err := query.FileOwnerships(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("FileOwnerships: %v", err)
 	}
@@ -139,7 +139,7 @@ func TestFileOwnerships_ExcludeGlobs(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	results, err := query.FileOwnerships(db, from, to, []string{"*.pb.go"})
+	results, err := query.FileOwnerships(db, from, to, query.Filter{ExcludeGlobs: []string{"*.pb.go"}})
 	if err != nil {
 		t.Fatalf("FileOwnerships: %v", err)
 	}
@@ -158,7 +158,7 @@ func TestFileOwnerships_Empty(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	results, err := query.FileOwnerships(db, from, to, nil)
+	results, err := query.FileOwnerships(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("FileOwnerships: %v", err)
 	}
@@ -187,7 +187,7 @@ func TestFileOwnerships_MultipleFiles(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 
-	results, err := query.FileOwnerships(db, from, to, nil)
+	results, err := query.FileOwnerships(db, from, to, query.Filter{})
 	if err != nil {
 		t.Fatalf("FileOwnerships: %v", err)
 	}
@@ -0,0 +1,91 @@
+package query
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"modernc.org/sqlite"
+
+	"git-analytics/internal/pathmatch"
+)
+
+// pathFilter is the compiled form of a Filter's include and exclude
+// patterns.
+type pathFilter struct {
+	include *pathmatch.Matcher
+	exclude *pathmatch.Matcher
+}
+
+// keep reports whether the file at p passes the include and exclude
+// patterns.
+func (f *pathFilter) keep(p string) bool {
+	if !f.include.Empty() && !f.include.Match(p) {
+		return false
+	}
+	return !f.exclude.Match(p)
+}
+
+// maxPathFilters bounds the number of cached pathFilters. Only a few
+// pattern sets are in use at a time, so the cache is simply emptied when it
+// fills up.
+const maxPathFilters = 64
+
+// pathFilters caches compiled pathFilters by their pathFilterSpec, so that
+// each distinct set of patterns is compiled once rather than per row.
+var (
+	pathFiltersMu sync.Mutex
+	pathFilters   = make(map[string]*pathFilter)
+)
+
+// pathFilterSpec encodes include and exclude patterns as the first argument
+// of the keep_path SQL function.
+func pathFilterSpec(include, exclude []string) string {
+	spec, _ := json.Marshal([2][]string{include, exclude})
+	return string(spec)
+}
+
+// compiledPathFilter returns the pathFilter for spec, compiling it on first
+// use.
+func compiledPathFilter(spec string) (*pathFilter, error) {
+	pathFiltersMu.Lock()
+	defer pathFiltersMu.Unlock()
+	if f, ok := pathFilters[spec]; ok {
+		return f, nil
+	}
+	var lists [2][]string
+	if err := json.Unmarshal([]byte(spec), &lists); err != nil {
+		return nil, fmt.Errorf("keep_path: parsing spec: %w", err)
+	}
+	f := &pathFilter{
+		include: pathmatch.Compile(lists[0]),
+		exclude: pathmatch.Compile(lists[1]),
+	}
+	if len(pathFilters) >= maxPathFilters {
+		clear(pathFilters)
+	}
+	pathFilters[spec] = f
+	return f, nil
+}
+
+// keep_path(spec, path) is true if path passes the patterns encoded in spec
+// by pathFilterSpec. It is registered for every connection the sqlite
+// driver opens.
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("keep_path", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		spec, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("keep_path: spec is %T, want string", args[0])
+		}
+		p, ok := args[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("keep_path: path is %T, want string", args[1])
+		}
+		f, err := compiledPathFilter(spec)
+		if err != nil {
+			return nil, err
+		}
+		return f.keep(p), nil
+	})
+}
@@ -0,0 +1,37 @@
+package query
+
+import "database/sql"
+
+// ClassifiedPath is a path detected as generated, vendored or
+// documentation.
+type ClassifiedPath struct {
+	Path          string `json:"path"`
+	Generated     bool   `json:"generated"`
+	Vendored      bool   `json:"vendored"`
+	Documentation bool   `json:"documentation"`
+	Exists        bool   `json:"exists"` // present at the indexed HEAD
+}
+
+// ClassifiedPaths returns the paths classified by indexer.IndexPathKinds,
+// sorted by path, so that the files a Filter omits by default can be shown.
+func ClassifiedPaths(db *sql.DB) ([]ClassifiedPath, error) {
+	rows, err := db.Query(
+		`SELECT pk.path, pk.generated, pk.vendored, pk.documentation, hf.path IS NOT NULL
+		 FROM path_kinds pk
+		 LEFT JOIN head_files hf ON hf.path = pk.path
+		 ORDER BY pk.path`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []ClassifiedPath{}
+	for rows.Next() {
+		var p ClassifiedPath
+		if err := rows.Scan(&p.Path, &p.Generated, &p.Vendored, &p.Documentation, &p.Exists); err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,34 @@
+package query_test
+
+import (
+	"testing"
+
+	"git-analytics/internal/query"
+)
+
+func TestClassifiedPaths(t *testing.T) {
+	db := setupDB(t)
+
+	if _, err := db.Exec(`INSERT INTO path_kinds (path, generated, vendored, documentation) VALUES
+		('vendor/x.go', FALSE, TRUE, FALSE), ('api.pb.go', TRUE, FALSE, FALSE)`); err != nil {
+		t.Fatalf("insert path kinds: %v", err)
+	}
+	insertHeadFile(t, db, "api.pb.go")
+
+	paths, err := query.ClassifiedPaths(db)
+	if err != nil {
+		t.Fatalf("ClassifiedPaths: %v", err)
+	}
+	want := []query.ClassifiedPath{
+		{Path: "api.pb.go", Generated: true, Exists: true},
+		{Path: "vendor/x.go", Vendored: true},
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("got %+v, want %+v", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("path %d: got %+v, want %+v", i, paths[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package query
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+)
+
+// ReviewLatencyPoint summarizes the pull requests opened in one time bucket.
+// Latencies are medians in hours over the pull requests that reached the
+// event; they are 0 when none did.
+type ReviewLatencyPoint struct {
+	Period                 string  `json:"period"`
+	Opened                 int     `json:"opened"`
+	Reviewed               int     `json:"reviewed"`
+	Merged                 int     `json:"merged"`
+	MedianFirstReviewHours float64 `json:"median_first_review_hours"`
+	MedianMergeHours       float64 `json:"median_merge_hours"`
+}
+
+// pullRequestClauses returns a SQL fragment restricting pull requests
+// aliased as p to those filter keeps, and its args. A pull request is kept
+// if one of its commits (see pr_commits) is kept by filter and changes a file
+// it keeps, or if none of its commits is indexed.
+func (f Filter) pullRequestClauses() (string, []any) {
+	fileSQL, fileArgs := f.fileClauses("fs.file_path")
+	commitSQL, commitArgs := f.commitClauses()
+	if fileSQL == "" && commitSQL == "" {
+		return "", nil
+	}
+	clause := ` AND (NOT EXISTS (SELECT 1 FROM pr_commits pc JOIN commits c ON c.hash = pc.commit_hash
+	                             WHERE pc.pr_number = p.number)
+	       OR EXISTS (SELECT 1 FROM pr_commits pc
+	                  JOIN commits c ON c.hash = pc.commit_hash
+	                  JOIN file_stats fs ON fs.commit_hash = c.hash
+	                  WHERE pc.pr_number = p.number` + commitSQL + fileSQL + `))`
+	return clause, append(commitArgs, fileArgs...)
+}
+
+// ReviewLatency returns, per bucket of the given granularity, how long pull
+// requests opened between from (inclusive) and to (exclusive) waited for
+// their first review by someone other than the author, and how long they
+// took to merge. Pull request times are bucketed in UTC. Every bucket in the
+// range is returned in chronological order, including empty ones. Pull
+// requests are narrowed by filter through their commits.
+func ReviewLatency(db *sql.DB, from, to time.Time, g Granularity, filter Filter) ([]ReviewLatencyPoint, error) {
+	period, err := periodExprOf("p.created_at", g)
+	if err != nil {
+		return nil, err
+	}
+	prSQL, prArgs := filter.pullRequestClauses()
+
+	rows, err := db.Query(
+		`SELECT `+period+`, p.created_at, p.merged_at,
+		        (SELECT MIN(r.submitted_at) FROM pr_reviews r
+		         WHERE r.pr_number = p.number AND r.reviewer != p.author)
+		 FROM pull_requests p
+		 WHERE p.created_at >= ? AND p.created_at < ?`+prSQL,
+		append([]any{from.Unix(), to.Unix()}, prArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type bucket struct {
+		opened              int
+		firstReview, merged []float64
+	}
+	byPeriod := make(map[string]*bucket)
+	for rows.Next() {
+		var label string
+		var createdAt int64
+		var mergedAt, firstReview sql.NullInt64
+		if err := rows.Scan(&label, &createdAt, &mergedAt, &firstReview); err != nil {
+			return nil, err
+		}
+		b, ok := byPeriod[label]
+		if !ok {
+			b = &bucket{}
+			byPeriod[label] = b
+		}
+		b.opened++
+		if firstReview.Valid {
+			b.firstReview = append(b.firstReview, hoursBetween(createdAt, firstReview.Int64))
+		}
+		if mergedAt.Valid {
+			b.merged = append(b.merged, hoursBetween(createdAt, mergedAt.Int64))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	labels := periodLabels(from, to, g)
+	result := make([]ReviewLatencyPoint, len(labels))
+	for i, label := range labels {
+		p := ReviewLatencyPoint{Period: label}
+		if b, ok := byPeriod[label]; ok {
+			p.Opened = b.opened
+			p.Reviewed = len(b.firstReview)
+			p.Merged = len(b.merged)
+			p.MedianFirstReviewHours = median(b.firstReview)
+			p.MedianMergeHours = median(b.merged)
+		}
+		result[i] = p
+	}
+	return result, nil
+}
+
+// ReviewerLoad summarizes one reviewer's activity.
+type ReviewerLoad struct {
+	Reviewer     string `json:"reviewer"`
+	Requested    int    `json:"requested"`     // pull requests they were asked to review
+	PullRequests int    `json:"pull_requests"` // pull requests they reviewed
+	Reviews      int    `json:"reviews"`
+	Approvals    int    `json:"approvals"`
+	// MedianResponseHours is the median time from a pull request being
+	// opened to this reviewer's first review of it.
+	MedianResponseHours float64 `json:"median_response_hours"`
+}
+
+// ReviewerLoads returns review activity per reviewer on pull requests opened
+// between from (inclusive) and to (exclusive), ordered by pull requests
+// reviewed descending. Authors reviewing their own pull requests are not
+// counted. Pull requests are narrowed by filter through their commits.
+func ReviewerLoads(db *sql.DB, from, to time.Time, filter Filter) ([]ReviewerLoad, error) {
+	prSQL, prArgs := filter.pullRequestClauses()
+	args := append([]any{from.Unix(), to.Unix()}, prArgs...)
+
+	rows, err := db.Query(
+		`SELECT r.reviewer, r.pr_number, p.created_at, r.state, r.submitted_at
+		 FROM pr_reviews r
+		 JOIN pull_requests p ON p.number = r.pr_number
+		 WHERE p.created_at >= ? AND p.created_at < ? AND r.reviewer != p.author`+prSQL,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type aggregate struct {
+		load          ReviewerLoad
+		firstResponse map[int]int64 // pr number → earliest review time
+		created       map[int]int64
+	}
+	byReviewer := make(map[string]*aggregate)
+	get := func(reviewer string) *aggregate {
+		a, ok := byReviewer[reviewer]
+		if !ok {
+			a = &aggregate{
+				load:          ReviewerLoad{Reviewer: reviewer},
+				firstResponse: make(map[int]int64),
+				created:       make(map[int]int64),
+			}
+			byReviewer[reviewer] = a
+		}
+		return a
+	}
+
+	for rows.Next() {
+		var reviewer, state string
+		var number int
+		var createdAt int64
+		var submittedAt sql.NullInt64
+		if err := rows.Scan(&reviewer, &number, &createdAt, &state, &submittedAt); err != nil {
+			return nil, err
+		}
+		a := get(reviewer)
+		a.load.Reviews++
+		if state == "approved" {
+			a.load.Approvals++
+		}
+		a.created[number] = createdAt
+		if submittedAt.Valid {
+			if first, ok := a.firstResponse[number]; !ok || submittedAt.Int64 < first {
+				a.firstResponse[number] = submittedAt.Int64
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	requested, err := db.Query(
+		`SELECT rr.reviewer, COUNT(*)
+		 FROM pr_reviewers rr
+		 JOIN pull_requests p ON p.number = rr.pr_number
+		 WHERE p.created_at >= ? AND p.created_at < ?`+prSQL+`
+		 GROUP BY rr.reviewer`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer requested.Close()
+	for requested.Next() {
+		var reviewer string
+		var count int
+		if err := requested.Scan(&reviewer, &count); err != nil {
+			return nil, err
+		}
+		get(reviewer).load.Requested = count
+	}
+	if err := requested.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]ReviewerLoad, 0, len(byReviewer))
+	for _, a := range byReviewer {
+		a.load.PullRequests = len(a.created)
+		var responses []float64
+		for number, at := range a.firstResponse {
+			responses = append(responses, hoursBetween(a.created[number], at))
+		}
+		a.load.MedianResponseHours = median(responses)
+		result = append(result, a.load)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].PullRequests != result[j].PullRequests {
+			return result[i].PullRequests > result[j].PullRequests
+		}
+		return result[i].Reviewer < result[j].Reviewer
+	})
+	return result, nil
+}
+
+// PullRequestFiles returns the files changed by the indexed commits of the
+// pull request number, ordered by lines changed descending. Commits and
+// files are narrowed by filter.
+func PullRequestFiles(db *sql.DB, number int, filter Filter) ([]FileHotspot, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT fs.file_path,
+	        SUM(fs.additions + fs.deletions) AS lines_changed,
+	        SUM(fs.additions), SUM(fs.deletions),
+	        COUNT(DISTINCT fs.commit_hash)
+	 FROM pr_commits pc
+	 JOIN commits c ON c.hash = pc.commit_hash
+	 JOIN file_stats fs ON fs.commit_hash = c.hash
+	 WHERE pc.pr_number = ?` + commitSQL + excludeSQL + `
+	 GROUP BY fs.file_path
+	 ORDER BY lines_changed DESC, fs.file_path`
+
+	args := make([]any, 0, len(commitArgs)+len(excludeArgs)+1)
+	args = append(args, number)
+	args = append(args, commitArgs...)
+	args = append(args, excludeArgs...)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []FileHotspot
+	for rows.Next() {
+		var h FileHotspot
+		if err := rows.Scan(&h.Path, &h.LinesChanged, &h.Additions, &h.Deletions, &h.Commits); err != nil {
+			return nil, err
+		}
+		result = append(result, h)
+	}
+	return result, rows.Err()
+}
+
+// hoursBetween returns the hours from one epoch second to another.
+func hoursBetween(from, to int64) float64 {
+	return float64(to-from) / 3600
+}
+
+// median returns the median of values, or 0 if there are none.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
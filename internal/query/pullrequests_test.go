@@ -0,0 +1,143 @@
+package query_test
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func insertPullRequest(t *testing.T, db *sql.DB, number int, author string, createdAt time.Time, mergedAfter time.Duration) {
+	t.Helper()
+	var mergedAt any
+	if mergedAfter > 0 {
+		mergedAt = createdAt.Add(mergedAfter).Unix()
+	}
+	_, err := db.Exec(
+		`INSERT INTO pull_requests (number, title, author, state, created_at, merged_at) VALUES (?, 'title', ?, 'merged', ?, ?)`,
+		number, author, createdAt.Unix(), mergedAt,
+	)
+	if err != nil {
+		t.Fatalf("insert pull request: %v", err)
+	}
+}
+
+func insertReview(t *testing.T, db *sql.DB, number int, reviewer, state string, at time.Time) {
+	t.Helper()
+	_, err := db.Exec(
+		`INSERT INTO pr_reviews (pr_number, reviewer, state, submitted_at) VALUES (?, ?, ?, ?)`,
+		number, reviewer, state, at.Unix(),
+	)
+	if err != nil {
+		t.Fatalf("insert review: %v", err)
+	}
+}
+
+func setupPullRequestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := setupDB(t)
+	jan := time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC)
+	insertPullRequest(t, db, 1, "alice", jan, 24*time.Hour)
+	insertReview(t, db, 1, "alice", "commented", jan.Add(time.Hour)) // self-review is ignored
+	insertReview(t, db, 1, "bob", "commented", jan.Add(2*time.Hour))
+	insertReview(t, db, 1, "bob", "approved", jan.Add(4*time.Hour))
+	insertPullRequest(t, db, 2, "alice", jan.AddDate(0, 0, 5), 48*time.Hour)
+	insertReview(t, db, 2, "carol", "approved", jan.AddDate(0, 0, 5).Add(6*time.Hour))
+	insertPullRequest(t, db, 3, "bob", jan.AddDate(0, 0, 6), 0)
+	if _, err := db.Exec(`INSERT INTO pr_reviewers (pr_number, reviewer) VALUES (3, 'carol')`); err != nil {
+		t.Fatalf("insert reviewer: %v", err)
+	}
+	return db
+}
+
+func TestReviewLatency(t *testing.T) {
+	db := setupPullRequestDB(t)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	series, err := query.ReviewLatency(db, from, to, query.GranularityMonth, query.Filter{})
+	if err != nil {
+		t.Fatalf("ReviewLatency: %v", err)
+	}
+	if len(series) != 2 {
+		t.Fatalf("got %d points, want 2", len(series))
+	}
+	jan := series[0]
+	if jan.Opened != 3 || jan.Reviewed != 2 || jan.Merged != 2 {
+		t.Errorf("got %+v, want 3 opened, 2 reviewed, 2 merged", jan)
+	}
+	// First reviews after 2h and 6h; merges after 24h and 48h.
+	if jan.MedianFirstReviewHours != 4 || jan.MedianMergeHours != 36 {
+		t.Errorf("got medians %v / %v, want 4 / 36", jan.MedianFirstReviewHours, jan.MedianMergeHours)
+	}
+	if series[1].Opened != 0 {
+		t.Errorf("got %+v for February, want empty", series[1])
+	}
+}
+
+func TestReviewerLoads(t *testing.T) {
+	db := setupPullRequestDB(t)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	loads, err := query.ReviewerLoads(db, from, to, query.Filter{})
+	if err != nil {
+		t.Fatalf("ReviewerLoads: %v", err)
+	}
+	if len(loads) != 2 {
+		t.Fatalf("got %+v, want bob and carol", loads)
+	}
+	bob, carol := loads[0], loads[1]
+	if bob.Reviewer != "bob" || bob.PullRequests != 1 || bob.Reviews != 2 || bob.Approvals != 1 {
+		t.Errorf("got %+v", bob)
+	}
+	if math.Abs(bob.MedianResponseHours-2) > 1e-9 {
+		t.Errorf("got bob response %v hours, want 2", bob.MedianResponseHours)
+	}
+	if carol.Reviewer != "carol" || carol.Requested != 1 || carol.PullRequests != 1 || carol.MedianResponseHours != 6 {
+		t.Errorf("got %+v", carol)
+	}
+}
+
+func TestPullRequests_Commits(t *testing.T) {
+	db := setupPullRequestDB(t)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com", time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC), "login")
+	insertFileStat(t, db, "aaa1", "a.go", 10, 2)
+	insertFileStat(t, db, "aaa1", "a_test.go", 5, 0)
+	insertCommit(t, db, "bbb2", "Alice", "alice@example.com", time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC), "docs")
+	insertFileStat(t, db, "bbb2", "README.md", 3, 1)
+	if _, err := db.Exec(`INSERT INTO pr_commits (pr_number, commit_hash) VALUES (1, 'aaa1'), (2, 'bbb2'), (2, 'unindexed')`); err != nil {
+		t.Fatalf("insert pr commits: %v", err)
+	}
+
+	files, err := query.PullRequestFiles(db, 1, query.Filter{})
+	if err != nil {
+		t.Fatalf("PullRequestFiles: %v", err)
+	}
+	if len(files) != 2 || files[0].Path != "a.go" || files[0].LinesChanged != 12 || files[1].Path != "a_test.go" {
+		t.Errorf("got %+v, want a.go then a_test.go", files)
+	}
+
+	// Pull request 2 only changed README.md, so excluding it drops carol's
+	// review; pull request 3 has no indexed commits and is kept.
+	loads, err := query.ReviewerLoads(db, from, to, query.Filter{ExcludeGlobs: []string{"*.md"}})
+	if err != nil {
+		t.Fatalf("ReviewerLoads: %v", err)
+	}
+	if len(loads) != 2 || loads[1].Reviewer != "carol" || loads[1].PullRequests != 0 || loads[1].Requested != 1 {
+		t.Errorf("got %+v, want carol requested once and reviewing nothing", loads)
+	}
+
+	series, err := query.ReviewLatency(db, from, to, query.GranularityMonth, query.Filter{ExcludeGlobs: []string{"*.md"}})
+	if err != nil {
+		t.Fatalf("ReviewLatency: %v", err)
+	}
+	if series[0].Opened != 2 || series[0].Merged != 1 {
+		t.Errorf("got %+v, want 2 opened and 1 merged", series[0])
+	}
+}
@@ -0,0 +1,67 @@
+package query
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Punchcard holds commit counts bucketed by day of week and hour of day.
+// Counts is indexed [weekday][hour], where weekday follows time.Weekday
+// (0 = Sunday) and hour is 0-23.
+type Punchcard struct {
+	Timezone string  `json:"timezone"`
+	Counts   [][]int `json:"counts"`
+	Total    int     `json:"total"`
+}
+
+// CommitPunchcard returns a 7×24 grid of commit counts between from
+// (inclusive) and to (exclusive). If loc is nil, each commit is bucketed in
+// its author's local time using the offset recorded at commit time; otherwise
+// every commit is converted to loc. If email is non-empty, results are
+// filtered to that author.
+func CommitPunchcard(db *sql.DB, from, to time.Time, email string, loc *time.Location, filter Filter) (*Punchcard, error) {
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT c.committed_at, c.tz_offset
+	 FROM commits c
+	 WHERE ` + commitInRange + commitSQL
+	args := append([]any{wallClock(from), wallClock(to)}, commitArgs...)
+	if email != "" {
+		q += ` AND c.author_email = ?`
+		args = append(args, email)
+	}
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	p := &Punchcard{Timezone: "author", Counts: make([][]int, 7)}
+	if loc != nil {
+		p.Timezone = loc.String()
+	}
+	for i := range p.Counts {
+		p.Counts[i] = make([]int, 24)
+	}
+
+	for rows.Next() {
+		var committedAt int64
+		var offsetMinutes int
+		if err := rows.Scan(&committedAt, &offsetMinutes); err != nil {
+			return nil, err
+		}
+		t := time.Unix(committedAt, 0)
+		if loc != nil {
+			t = t.In(loc)
+		} else {
+			t = t.In(time.FixedZone("", offsetMinutes*60))
+		}
+		p.Counts[t.Weekday()][t.Hour()]++
+		p.Total++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
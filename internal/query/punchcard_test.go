@@ -0,0 +1,104 @@
+package query_test
+
+import (
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func TestCommitPunchcard_AuthorLocalTime(t *testing.T) {
+	db := setupDB(t)
+
+	tokyo := time.FixedZone("", 9*60*60)
+	// Wednesday 2025-01-15 08:30 in Tokyo is Tuesday 23:30 UTC.
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 8, 30, 0, 0, tokyo), "tokyo morning")
+	// Wednesday 2025-01-15 14:00 UTC.
+	insertCommit(t, db, "bbb1", "Bob", "bob@example.com",
+		time.Date(2025, 1, 15, 14, 0, 0, 0, time.UTC), "utc afternoon")
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	p, err := query.CommitPunchcard(db, from, to, "", nil, query.Filter{})
+	if err != nil {
+		t.Fatalf("CommitPunchcard: %v", err)
+	}
+
+	if len(p.Counts) != 7 || len(p.Counts[0]) != 24 {
+		t.Fatalf("expected 7x24 grid, got %dx%d", len(p.Counts), len(p.Counts[0]))
+	}
+	if p.Total != 2 {
+		t.Errorf("Total: got %d, want 2", p.Total)
+	}
+	if got := p.Counts[time.Wednesday][8]; got != 1 {
+		t.Errorf("Wed 08:00: got %d, want 1", got)
+	}
+	if got := p.Counts[time.Wednesday][14]; got != 1 {
+		t.Errorf("Wed 14:00: got %d, want 1", got)
+	}
+	if p.Timezone != "author" {
+		t.Errorf("Timezone: got %q, want %q", p.Timezone, "author")
+	}
+}
+
+func TestCommitPunchcard_ViewerTimezone(t *testing.T) {
+	db := setupDB(t)
+
+	tokyo := time.FixedZone("", 9*60*60)
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 8, 30, 0, 0, tokyo), "tokyo morning")
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	p, err := query.CommitPunchcard(db, from, to, "", time.UTC, query.Filter{})
+	if err != nil {
+		t.Fatalf("CommitPunchcard: %v", err)
+	}
+
+	if got := p.Counts[time.Tuesday][23]; got != 1 {
+		t.Errorf("Tue 23:00 UTC: got %d, want 1", got)
+	}
+	if p.Timezone != "UTC" {
+		t.Errorf("Timezone: got %q, want %q", p.Timezone, "UTC")
+	}
+}
+
+func TestCommitPunchcard_FilterByEmail(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), "alice commit")
+	insertCommit(t, db, "bbb1", "Bob", "bob@example.com",
+		time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), "bob commit")
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	p, err := query.CommitPunchcard(db, from, to, "bob@example.com", nil, query.Filter{})
+	if err != nil {
+		t.Fatalf("CommitPunchcard: %v", err)
+	}
+
+	if p.Total != 1 || p.Counts[time.Wednesday][10] != 1 {
+		t.Errorf("expected a single Wed 10:00 commit, got total %d", p.Total)
+	}
+}
+
+func TestCommitPunchcard_Empty(t *testing.T) {
+	db := setupDB(t)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	p, err := query.CommitPunchcard(db, from, to, "", nil, query.Filter{})
+	if err != nil {
+		t.Fatalf("CommitPunchcard: %v", err)
+	}
+
+	if p.Total != 0 || len(p.Counts) != 7 {
+		t.Errorf("expected empty 7-row grid, got total %d with %d rows", p.Total, len(p.Counts))
+	}
+}
@@ -1,20 +1,139 @@
 package query
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
-// buildExcludeClauses returns a SQL fragment like " AND col NOT GLOB ? AND col NOT GLOB ?"
-// and the corresponding args slice. Returns ("", nil) when globs is empty.
-func buildExcludeClauses(column string, globs []string) (string, []any) {
-	if len(globs) == 0 {
-		return "", nil
+// Filter narrows the commits and files a query considers. The zero value
+// only omits files detected as generated or vendored (see
+// indexer.IndexPathKinds).
+type Filter struct {
+	// ExcludeGlobs omits files matching these patterns, which follow
+	// .gitignore rules: a pattern without a slash matches at any depth, "**"
+	// matches any number of directories and a "!" prefix re-includes files
+	// matched by an earlier pattern (see package pathmatch).
+	ExcludeGlobs []string `json:"exclude_globs"`
+	// IncludeGlobs, if not empty, keeps only files matching these patterns,
+	// with the same syntax as ExcludeGlobs. Exclusions apply on top.
+	IncludeGlobs []string `json:"include_globs"`
+	// ChangeTypes keeps only commits classified as one of these change types
+	// (see package classify). Empty means every type.
+	ChangeTypes []string `json:"change_types"`
+	// ExcludeReverts omits reverting commits and the commits they revert,
+	// so that changes which were undone do not count as churn.
+	ExcludeReverts bool `json:"exclude_reverts"`
+	// ExistingOnly keeps only files that exist at the indexed HEAD.
+	ExistingOnly bool `json:"existing_only"`
+	// Binary selects whether binary files are included (the default),
+	// excluded or the only files kept. A file is binary if any recorded
+	// change to it was.
+	Binary BinaryMode `json:"binary"`
+	// IncludeGenerated and IncludeVendored keep files detected as generated
+	// or vendored, which are omitted by default.
+	IncludeGenerated bool `json:"include_generated"`
+	IncludeVendored  bool `json:"include_vendored"`
+	// ExcludeDocumentation omits files detected as documentation.
+	ExcludeDocumentation bool `json:"exclude_documentation"`
+}
+
+// BinaryMode selects how a Filter treats binary files.
+type BinaryMode string
+
+const (
+	BinaryInclude BinaryMode = ""
+	BinaryExclude BinaryMode = "exclude"
+	BinaryOnly    BinaryMode = "only"
+)
+
+// fileClauses returns a SQL fragment excluding files in column that the
+// filter omits, and its args.
+func (f Filter) fileClauses(column string) (string, []any) {
+	clause, args := f.pathClauses(column)
+	if f.ExistingOnly {
+		clause += " AND " + column + " IN (SELECT path FROM head_files)"
+	}
+	binary := "EXISTS (SELECT 1 FROM file_stats bf WHERE bf.file_path = " + column + " AND bf.binary)"
+	switch f.Binary {
+	case BinaryExclude:
+		clause += " AND NOT " + binary
+	case BinaryOnly:
+		clause += " AND " + binary
+	}
+	var kinds []string
+	if !f.IncludeGenerated {
+		kinds = append(kinds, "generated")
 	}
+	if !f.IncludeVendored {
+		kinds = append(kinds, "vendored")
+	}
+	if f.ExcludeDocumentation {
+		kinds = append(kinds, "documentation")
+	}
+	if len(kinds) > 0 {
+		clause += " AND " + column + " NOT IN (SELECT path FROM path_kinds WHERE " + strings.Join(kinds, " OR ") + ")"
+	}
+	return clause, args
+}
+
+// commitClauses returns a SQL fragment like " AND c.change_type IN (?, ?)"
+// restricting commits aliased as c to those the filter keeps, and its args.
+// Returns ("", nil) when the filter does not constrain commits.
+func (f Filter) commitClauses() (string, []any) {
 	var b strings.Builder
-	args := make([]any, len(globs))
-	for i, g := range globs {
-		b.WriteString(" AND ")
-		b.WriteString(column)
-		b.WriteString(" NOT GLOB ?")
-		args[i] = g
+	var args []any
+	if len(f.ChangeTypes) > 0 {
+		b.WriteString(" AND c.change_type IN (" + placeholders(len(f.ChangeTypes)) + ")")
+		for _, t := range f.ChangeTypes {
+			args = append(args, t)
+		}
+	}
+	if f.ExcludeReverts {
+		b.WriteString(" AND c.reverts = '' AND NOT EXISTS (SELECT 1 FROM commits rv WHERE rv.reverts = c.hash)")
 	}
 	return b.String(), args
 }
+
+// pathClauses returns a SQL fragment like " AND keep_path(?, col)" keeping
+// files in column that pass the filter's include and exclude patterns, and
+// its args. Returns ("", nil) when there are no patterns.
+func (f Filter) pathClauses(column string) (string, []any) {
+	if len(f.IncludeGlobs) == 0 && len(f.ExcludeGlobs) == 0 {
+		return "", nil
+	}
+	return " AND keep_path(?, " + column + ")", []any{pathFilterSpec(f.IncludeGlobs, f.ExcludeGlobs)}
+}
+
+// underPath returns a SQL condition matching files in column that are dir
+// or lie below it, and its args. A trailing slash on dir is ignored, so
+// "src" matches "src/a.go" but not "srcgen/a.go" or "src_old.go".
+func underPath(column, dir string) (string, []any) {
+	dir = strings.TrimSuffix(dir, "/")
+	return "(" + column + " = ? OR instr(" + column + ", ?) = 1)", []any{dir, dir + "/"}
+}
+
+// placeholders returns n comma-separated SQL parameter placeholders.
+func placeholders(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strings.Repeat("?, ", n-1) + "?"
+}
+
+// commitLocalTime is the SQL expression for a commit's author-local
+// wall-clock time in seconds since the Unix epoch, for commits aliased as c.
+// Date ranges and calendar buckets use it so that a commit made just after
+// midnight in +09:00 lands on the author's day rather than the UTC one.
+// It matches idx_commits_local_time.
+const commitLocalTime = `(c.committed_at + c.tz_offset * 60)`
+
+// commitInRange restricts commits aliased as c to author-local times in
+// [from, to). Its arguments are wallClock(from), wallClock(to).
+const commitInRange = commitLocalTime + ` >= ? AND ` + commitLocalTime + ` < ?`
+
+// wallClock returns t's wall-clock time in its own location, expressed as
+// seconds since the Unix epoch, for comparison against commitLocalTime.
+func wallClock(t time.Time) int64 {
+	_, offset := t.Zone()
+	return t.Unix() + int64(offset)
+}
@@ -0,0 +1,167 @@
+package query
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RevertPoint holds the revert rate for one time bucket.
+type RevertPoint struct {
+	Period  string  `json:"period"`
+	Commits int     `json:"commits"`
+	Reverts int     `json:"reverts"`
+	Rate    float64 `json:"rate"` // reverts / commits
+}
+
+// RevertSeries returns the number of commits and reverts per bucket of the
+// given granularity for commits between from (inclusive) and to (exclusive).
+// Every bucket in the range is returned in chronological order, including
+// empty ones. Commits are narrowed by filter.
+func RevertSeries(db *sql.DB, from, to time.Time, g Granularity, filter Filter) ([]RevertPoint, error) {
+	period, err := periodExpr(g)
+	if err != nil {
+		return nil, err
+	}
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT ` + period + ` AS period, COUNT(*), SUM(c.reverts != '')
+	 FROM commits c
+	 WHERE ` + commitInRange + commitSQL + `
+	 GROUP BY period`
+	args := append([]any{wallClock(from), wallClock(to)}, commitArgs...)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byPeriod := make(map[string]RevertPoint)
+	for rows.Next() {
+		var p RevertPoint
+		if err := rows.Scan(&p.Period, &p.Commits, &p.Reverts); err != nil {
+			return nil, err
+		}
+		p.Rate = float64(p.Reverts) / float64(p.Commits)
+		byPeriod[p.Period] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	labels := periodLabels(from, to, g)
+	result := make([]RevertPoint, len(labels))
+	for i, label := range labels {
+		p, ok := byPeriod[label]
+		if !ok {
+			p = RevertPoint{Period: label}
+		}
+		result[i] = p
+	}
+	return result, nil
+}
+
+// RevertedFile counts how often changes to a file were reverted.
+type RevertedFile struct {
+	Path       string  `json:"path"`
+	Reverted   int     `json:"reverted"` // commits touching the file that were later reverted
+	Commits    int     `json:"commits"`
+	RevertRate float64 `json:"revert_rate"` // reverted / commits
+}
+
+// RevertedFiles returns the files changed by commits between from
+// (inclusive) and to (exclusive) that were later reverted, ordered by the
+// number of reverted commits descending. Commits and files are narrowed by
+// filter.
+func RevertedFiles(db *sql.DB, from, to time.Time, filter Filter) ([]RevertedFile, error) {
+	excludeSQL, excludeArgs := filter.fileClauses("fs.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT fs.file_path,
+	        COUNT(DISTINCT CASE WHEN EXISTS (SELECT 1 FROM commits rv WHERE rv.reverts = c.hash)
+	                            THEN c.hash END) AS reverted,
+	        COUNT(DISTINCT c.hash)
+	 FROM file_stats fs
+	 JOIN commits c ON c.hash = fs.commit_hash
+	 WHERE ` + commitInRange + commitSQL + excludeSQL + `
+	 GROUP BY fs.file_path
+	 HAVING reverted > 0
+	 ORDER BY reverted DESC, fs.file_path`
+
+	args := make([]any, 0, len(commitArgs)+len(excludeArgs)+2)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	args = append(args, excludeArgs...)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []RevertedFile
+	for rows.Next() {
+		var f RevertedFile
+		if err := rows.Scan(&f.Path, &f.Reverted, &f.Commits); err != nil {
+			return nil, err
+		}
+		f.RevertRate = float64(f.Reverted) / float64(f.Commits)
+		result = append(result, f)
+	}
+	return result, rows.Err()
+}
+
+// Revert pairs a reverting commit with the commit it reverts. The Original
+// fields are empty when the reverted commit is not in the index, e.g. because
+// it lives on another branch.
+type Revert struct {
+	Hash                string  `json:"hash"`
+	AuthorName          string  `json:"author_name"`
+	AuthorEmail         string  `json:"author_email"`
+	Date                string  `json:"date"`
+	Subject             string  `json:"subject"`
+	Method              string  `json:"method"` // "message" or "inverse"
+	OriginalHash        string  `json:"original_hash"`
+	OriginalAuthorEmail string  `json:"original_author_email"`
+	OriginalDate        string  `json:"original_date"`
+	OriginalSubject     string  `json:"original_subject"`
+	HoursToRevert       float64 `json:"hours_to_revert"`
+}
+
+// Reverts returns the reverting commits between from (inclusive) and to
+// (exclusive), newest first, with the commits they revert and how long after
+// them they came. Commits are narrowed by filter.
+func Reverts(db *sql.DB, from, to time.Time, filter Filter) ([]Revert, error) {
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT c.hash, c.author_name, c.author_email,
+	        date(` + commitLocalTime + `, 'unixepoch'), c.message, c.revert_method, c.reverts,
+	        COALESCE(o.author_email, ''),
+	        COALESCE(date(o.committed_at + o.tz_offset * 60, 'unixepoch'), ''),
+	        COALESCE(o.message, ''),
+	        COALESCE(c.committed_at - o.committed_at, 0)
+	 FROM commits c
+	 LEFT JOIN commits o ON o.hash = c.reverts
+	 WHERE c.reverts != '' AND ` + commitInRange + commitSQL + `
+	 ORDER BY c.committed_at DESC`
+	args := append([]any{wallClock(from), wallClock(to)}, commitArgs...)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []Revert
+	for rows.Next() {
+		var r Revert
+		var seconds int64
+		if err := rows.Scan(&r.Hash, &r.AuthorName, &r.AuthorEmail, &r.Date, &r.Subject, &r.Method,
+			&r.OriginalHash, &r.OriginalAuthorEmail, &r.OriginalDate, &r.OriginalSubject, &seconds); err != nil {
+			return nil, err
+		}
+		r.HoursToRevert = float64(seconds) / 3600
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
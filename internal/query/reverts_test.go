@@ -0,0 +1,103 @@
+package query_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func markRevert(t *testing.T, db *sql.DB, hash, original string) {
+	t.Helper()
+	if _, err := db.Exec(`UPDATE commits SET reverts = ?, revert_method = 'message' WHERE hash = ?`, original, hash); err != nil {
+		t.Fatalf("mark revert: %v", err)
+	}
+}
+
+func setupRevertDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := setupDB(t)
+	insertCommit(t, db, "c1", "Alice", "alice@example.com", time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC), "Add login")
+	insertCommit(t, db, "c2", "Bob", "bob@example.com", time.Date(2025, 1, 5, 16, 0, 0, 0, time.UTC), `Revert "Add login"`)
+	insertCommit(t, db, "c3", "Alice", "alice@example.com", time.Date(2025, 2, 3, 10, 0, 0, 0, time.UTC), "Tweak login")
+	insertCommit(t, db, "c4", "Alice", "alice@example.com", time.Date(2025, 2, 4, 10, 0, 0, 0, time.UTC), "Tweak docs")
+	insertFileStat(t, db, "c1", "login.go", 100, 0)
+	insertFileStat(t, db, "c1", "main.go", 2, 0)
+	insertFileStat(t, db, "c2", "login.go", 0, 100)
+	insertFileStat(t, db, "c2", "main.go", 0, 2)
+	insertFileStat(t, db, "c3", "login.go", 5, 1)
+	insertFileStat(t, db, "c4", "README.md", 5, 1)
+	markRevert(t, db, "c2", "c1")
+	return db
+}
+
+func TestRevertSeries(t *testing.T) {
+	db := setupRevertDB(t)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	series, err := query.RevertSeries(db, from, to, query.GranularityMonth, query.Filter{})
+	if err != nil {
+		t.Fatalf("RevertSeries: %v", err)
+	}
+	if len(series) != 2 {
+		t.Fatalf("got %d points, want 2", len(series))
+	}
+	if series[0].Commits != 2 || series[0].Reverts != 1 || series[0].Rate != 0.5 {
+		t.Errorf("got %+v, want 2 commits, 1 revert", series[0])
+	}
+	if series[1].Reverts != 0 || series[1].Rate != 0 {
+		t.Errorf("got %+v, want no reverts", series[1])
+	}
+}
+
+func TestRevertedFiles(t *testing.T) {
+	db := setupRevertDB(t)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	files, err := query.RevertedFiles(db, from, to, query.Filter{})
+	if err != nil {
+		t.Fatalf("RevertedFiles: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("got %+v, want login.go and main.go", files)
+	}
+	// login.go: c1 reverted; touched by c1, c2 and c3.
+	if files[0].Path != "login.go" || files[0].Reverted != 1 || files[0].Commits != 3 {
+		t.Errorf("got %+v", files[0])
+	}
+}
+
+func TestReverts(t *testing.T) {
+	db := setupRevertDB(t)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	reverts, err := query.Reverts(db, from, to, query.Filter{})
+	if err != nil {
+		t.Fatalf("Reverts: %v", err)
+	}
+	if len(reverts) != 1 {
+		t.Fatalf("got %d reverts, want 1", len(reverts))
+	}
+	r := reverts[0]
+	if r.Hash != "c2" || r.OriginalHash != "c1" || r.OriginalSubject != "Add login" || r.Method != "message" || r.HoursToRevert != 6 {
+		t.Errorf("got %+v", r)
+	}
+}
+
+func TestFilter_ExcludeReverts(t *testing.T) {
+	db := setupRevertDB(t)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	hotspots, err := query.FileHotspots(db, from, to, query.Filter{ExcludeReverts: true})
+	if err != nil {
+		t.Fatalf("FileHotspots: %v", err)
+	}
+	if len(hotspots) != 2 || hotspots[0].LinesChanged != 6 {
+		t.Errorf("got %+v, want only c3 and c4 churn", hotspots)
+	}
+}
@@ -0,0 +1,286 @@
+package query
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+)
+
+// ReworkAuthor sums the rework of one author's commits.
+type ReworkAuthor struct {
+	AuthorName  string  `json:"author_name"`
+	AuthorEmail string  `json:"author_email"`
+	Churn       int     `json:"churn"`       // lines added plus lines removed
+	Rework      int     `json:"rework"`      // removed lines written within the window
+	SelfRework  int     `json:"self_rework"` // rework of the author's own lines
+	Ratio       float64 `json:"ratio"`       // rework / churn
+}
+
+// ReworkFile sums the rework of changes to one file.
+type ReworkFile struct {
+	Path       string  `json:"path"`
+	Churn      int     `json:"churn"`
+	Rework     int     `json:"rework"`
+	SelfRework int     `json:"self_rework"`
+	Ratio      float64 `json:"ratio"`
+}
+
+// ReworkPoint sums the rework of commits in one time bucket.
+type ReworkPoint struct {
+	Period     string  `json:"period"`
+	Churn      int     `json:"churn"`
+	Rework     int     `json:"rework"`
+	SelfRework int     `json:"self_rework"`
+	Ratio      float64 `json:"ratio"`
+}
+
+// ReworkReport describes how much churn rewrites or removes recently
+// written lines.
+type ReworkReport struct {
+	Days       int            `json:"days"` // window within which rewritten lines count as rework
+	Churn      int            `json:"churn"`
+	Rework     int            `json:"rework"`
+	SelfRework int            `json:"self_rework"`
+	Ratio      float64        `json:"ratio"`
+	Authors    []ReworkAuthor `json:"authors"` // by rework, descending
+	Files      []ReworkFile   `json:"files"`   // by rework, descending
+	Series     []ReworkPoint  `json:"series"`
+}
+
+// reworkTally accumulates churn and rework.
+type reworkTally struct {
+	churn, rework, selfRework int
+}
+
+func (t reworkTally) ratio() float64 {
+	if t.churn == 0 {
+		return 0
+	}
+	return float64(t.rework) / float64(t.churn)
+}
+
+// lineBirth records when and by whom a line was last written. An author of
+// -1 marks a line whose origin is unknown.
+type lineBirth struct {
+	at     int64
+	author int
+}
+
+// storedHunk is one hunk of a file in a commit, as stored by the indexer.
+type storedHunk struct {
+	oldStart, oldLines, newStart, newLines int
+}
+
+// Rework measures rework for commits between from (inclusive) and to
+// (exclusive): lines removed or rewritten that were written less than days
+// days before, per author, per file and per bucket of the given granularity.
+// It needs the hunks extracted by indexer.IndexHunks.
+//
+// The age of each line is found by replaying every file's hunks in commit
+// date order from the start of the history, following renames. Hunks of
+// commits made on parallel branches apply to different versions of a file,
+// so their lines' ages are approximate, and coalesced hunks count as
+// rewriting every line they span. Only commits kept by filter count, and
+// files it excludes are omitted, but every commit is replayed.
+func Rework(db *sql.DB, from, to time.Time, g Granularity, days int, filter Filter) (*ReworkReport, error) {
+	period, err := periodExpr(g)
+	if err != nil {
+		return nil, err
+	}
+	excludeSQL, excludeArgs := filter.fileClauses("h.file_path")
+	commitSQL, commitArgs := filter.commitClauses()
+
+	// Renames without content changes have no hunks, so each rename also
+	// contributes a row with seq -1, which sorts before the file's hunks.
+	q := `SELECT c.hash, c.committed_at, c.author_email, c.author_name, ` + period + `,
+	        (` + commitInRange + commitSQL + `), (1 = 1` + excludeSQL + `),
+	        h.file_path, COALESCE(fs.old_path, ''), h.seq, h.old_start, h.old_lines, h.new_start, h.new_lines
+	 FROM (
+	     SELECT commit_hash, file_path, seq, old_start, old_lines, new_start, new_lines FROM hunks
+	     UNION ALL
+	     SELECT commit_hash, file_path, -1, 0, 0, 0, 0 FROM file_stats WHERE old_path != ''
+	 ) h
+	 JOIN commits c ON c.hash = h.commit_hash
+	 LEFT JOIN file_stats fs ON fs.commit_hash = h.commit_hash AND fs.file_path = h.file_path
+	 WHERE ` + commitLocalTime + ` < ?
+	 ORDER BY c.committed_at, c.hash, h.file_path, h.seq`
+	args := make([]any, 0, len(commitArgs)+len(excludeArgs)+3)
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	args = append(args, excludeArgs...)
+	args = append(args, wallClock(to))
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	window := int64(days) * 24 * 60 * 60
+	authors := make(map[string]int) // email -> index into names
+	var names []string
+	lines := make(map[string][]lineBirth)
+
+	total := reworkTally{}
+	byAuthor := make(map[string]*reworkTally)
+	byFile := make(map[string]*reworkTally)
+	byPeriod := make(map[string]*reworkTally)
+	tally := func(m map[string]*reworkTally, key string) *reworkTally {
+		t, ok := m[key]
+		if !ok {
+			t = &reworkTally{}
+			m[key] = t
+		}
+		return t
+	}
+
+	// The hunks of one file in one commit are applied together, since their
+	// old line numbers all refer to the file before the commit.
+	var cur struct {
+		hash, path, email, period string
+		at                        int64
+		counted                   bool
+		hunks                     []storedHunk
+	}
+	flush := func() {
+		if len(cur.hunks) == 0 {
+			return
+		}
+		var t reworkTally
+		for _, h := range cur.hunks {
+			t.churn += h.oldLines + h.newLines
+		}
+		author := authors[cur.email]
+		removed, kept := applyHunks(lines[cur.path], cur.hunks, lineBirth{at: cur.at, author: author})
+		lines[cur.path] = kept
+		cur.hunks = cur.hunks[:0]
+		if !cur.counted {
+			return
+		}
+
+		for _, b := range removed {
+			if b.author < 0 || cur.at-b.at >= window {
+				continue
+			}
+			t.rework++
+			if b.author == author {
+				t.selfRework++
+			}
+		}
+		for _, dst := range []*reworkTally{&total, tally(byAuthor, cur.email), tally(byFile, cur.path), tally(byPeriod, cur.period)} {
+			dst.churn += t.churn
+			dst.rework += t.rework
+			dst.selfRework += t.selfRework
+		}
+	}
+
+	for rows.Next() {
+		var hash, email, name, p, path, oldPath string
+		var at int64
+		var inRange, fileKept bool
+		var seq int
+		var h storedHunk
+		if err := rows.Scan(&hash, &at, &email, &name, &p, &inRange, &fileKept, &path, &oldPath, &seq,
+			&h.oldStart, &h.oldLines, &h.newStart, &h.newLines); err != nil {
+			return nil, err
+		}
+		if hash != cur.hash || path != cur.path {
+			flush()
+			cur.hash, cur.path, cur.email, cur.period, cur.at = hash, path, email, p, at
+			cur.counted = inRange && fileKept
+		}
+		if _, ok := authors[email]; !ok {
+			authors[email] = len(names)
+			names = append(names, name)
+		}
+		if seq < 0 {
+			if oldPath != "" {
+				if moved, ok := lines[oldPath]; ok {
+					lines[path] = moved
+					delete(lines, oldPath)
+				}
+			}
+			continue
+		}
+		cur.hunks = append(cur.hunks, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	report := &ReworkReport{
+		Days:       days,
+		Churn:      total.churn,
+		Rework:     total.rework,
+		SelfRework: total.selfRework,
+		Ratio:      total.ratio(),
+		Authors:    make([]ReworkAuthor, 0, len(byAuthor)),
+		Files:      make([]ReworkFile, 0, len(byFile)),
+	}
+	for email, t := range byAuthor {
+		report.Authors = append(report.Authors, ReworkAuthor{
+			AuthorName: names[authors[email]], AuthorEmail: email,
+			Churn: t.churn, Rework: t.rework, SelfRework: t.selfRework, Ratio: t.ratio(),
+		})
+	}
+	sort.Slice(report.Authors, func(i, j int) bool {
+		a, b := report.Authors[i], report.Authors[j]
+		if a.Rework != b.Rework {
+			return a.Rework > b.Rework
+		}
+		return a.AuthorEmail < b.AuthorEmail
+	})
+	for path, t := range byFile {
+		report.Files = append(report.Files, ReworkFile{
+			Path: path, Churn: t.churn, Rework: t.rework, SelfRework: t.selfRework, Ratio: t.ratio(),
+		})
+	}
+	sort.Slice(report.Files, func(i, j int) bool {
+		a, b := report.Files[i], report.Files[j]
+		if a.Rework != b.Rework {
+			return a.Rework > b.Rework
+		}
+		return a.Path < b.Path
+	})
+
+	labels := periodLabels(from, to, g)
+	report.Series = make([]ReworkPoint, len(labels))
+	for i, label := range labels {
+		report.Series[i] = ReworkPoint{Period: label}
+		if t, ok := byPeriod[label]; ok {
+			report.Series[i].Churn, report.Series[i].Rework, report.Series[i].SelfRework = t.churn, t.rework, t.selfRework
+			report.Series[i].Ratio = t.ratio()
+		}
+	}
+	return report, nil
+}
+
+// applyHunks applies a commit's hunks of one file, in order, to the births of
+// the file's lines, giving added lines the birth b. It returns the lines
+// removed and the file's new lines. Hunks reaching past the end of the known
+// lines, as happens when branches are replayed out of order, remove lines of
+// unknown origin.
+func applyHunks(old []lineBirth, hunks []storedHunk, b lineBirth) (removed, kept []lineBirth) {
+	kept = make([]lineBirth, 0, len(old))
+	pos := 0
+	for _, h := range hunks {
+		// The hunk starts after line oldStart when it removes nothing.
+		cut := h.oldStart
+		if h.oldLines > 0 {
+			cut--
+		}
+		cut = min(max(cut, pos), len(old))
+		kept = append(kept, old[pos:cut]...)
+		end := min(cut+h.oldLines, len(old))
+		removed = append(removed, old[cut:end]...)
+		for range h.oldLines - (end - cut) {
+			removed = append(removed, lineBirth{author: -1})
+		}
+		pos = end
+		for range h.newLines {
+			kept = append(kept, b)
+		}
+	}
+	return removed, append(kept, old[pos:]...)
+}
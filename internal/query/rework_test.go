@@ -0,0 +1,102 @@
+package query_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func insertHunk(t *testing.T, db *sql.DB, commitHash, filePath string, seq, oldStart, oldLines, newStart, newLines int) {
+	t.Helper()
+	_, err := db.Exec(
+		`INSERT INTO hunks (commit_hash, file_path, seq, old_start, old_lines, new_start, new_lines) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		commitHash, filePath, seq, oldStart, oldLines, newStart, newLines,
+	)
+	if err != nil {
+		t.Fatalf("insert hunk: %v", err)
+	}
+}
+
+func setupReworkDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := setupDB(t)
+
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 10, 0, 0, 0, time.UTC) }
+	// Alice writes a.go; Bob rewrites lines 2-3 four days later.
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com", day(1), "add a")
+	insertFileStat(t, db, "aaa1", "a.go", 10, 0)
+	insertHunk(t, db, "aaa1", "a.go", 0, 0, 0, 1, 10)
+	insertCommit(t, db, "bbb1", "Bob", "bob@example.com", day(5), "fix a")
+	insertFileStat(t, db, "bbb1", "a.go", 2, 2)
+	insertHunk(t, db, "bbb1", "a.go", 0, 2, 2, 2, 2)
+	// Alice deletes her line 1 and Bob's line 2.
+	insertCommit(t, db, "aaa2", "Alice", "alice@example.com", day(10), "trim a")
+	insertFileStat(t, db, "aaa2", "a.go", 0, 2)
+	insertHunk(t, db, "aaa2", "a.go", 0, 1, 2, 0, 0)
+	// a.go is renamed to b.go, whose first line (Alice's day 1 line 4) Bob
+	// rewrites after the window.
+	insertCommit(t, db, "aaa3", "Alice", "alice@example.com", day(12), "rename a")
+	insertRename(t, db, "aaa3", "a.go", "b.go", 0, 0)
+	insertCommit(t, db, "bbb2", "Bob", "bob@example.com", day(20), "fix b")
+	insertFileStat(t, db, "bbb2", "b.go", 1, 1)
+	insertHunk(t, db, "bbb2", "b.go", 0, 1, 1, 1, 1)
+	return db
+}
+
+func TestRework(t *testing.T) {
+	db := setupReworkDB(t)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	r, err := query.Rework(db, from, to, query.GranularityMonth, 14, query.Filter{})
+	if err != nil {
+		t.Fatalf("Rework: %v", err)
+	}
+
+	if r.Churn != 18 || r.Rework != 4 || r.SelfRework != 1 || r.Days != 14 {
+		t.Errorf("got churn=%d rework=%d self=%d days=%d, want 18, 4, 1, 14", r.Churn, r.Rework, r.SelfRework, r.Days)
+	}
+	if len(r.Authors) != 2 {
+		t.Fatalf("expected 2 authors, got %+v", r.Authors)
+	}
+	// Both rewrote 2 recent lines; ties are ordered by email.
+	alice, bob := r.Authors[0], r.Authors[1]
+	if alice.AuthorEmail != "alice@example.com" || alice.Churn != 12 || alice.Rework != 2 || alice.SelfRework != 1 {
+		t.Errorf("got %+v, want Alice with 12 churn, 2 rework, 1 of it her own", alice)
+	}
+	if bob.AuthorName != "Bob" || bob.Churn != 6 || bob.Rework != 2 || bob.SelfRework != 0 || bob.Ratio != 2.0/6 {
+		t.Errorf("got %+v, want Bob with 6 churn and 2 rework", bob)
+	}
+	if len(r.Files) != 2 || r.Files[0].Path != "a.go" || r.Files[0].Rework != 4 || r.Files[1].Path != "b.go" || r.Files[1].Rework != 0 {
+		t.Errorf("got %+v, want a.go with 4 rework and b.go with none", r.Files)
+	}
+	if len(r.Series) != 1 || r.Series[0].Period != "2025-01" || r.Series[0].Rework != 4 {
+		t.Errorf("got %+v, want one January point with 4 rework", r.Series)
+	}
+}
+
+func TestRework_RangeKeepsEarlierHistory(t *testing.T) {
+	db := setupReworkDB(t)
+
+	// Lines written before the range still count when rewritten in it.
+	from := time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	r, err := query.Rework(db, from, to, query.GranularityMonth, 14, query.Filter{})
+	if err != nil {
+		t.Fatalf("Rework: %v", err)
+	}
+	if r.Churn != 4 || r.Rework != 2 {
+		t.Errorf("got churn=%d rework=%d, want 4, 2", r.Churn, r.Rework)
+	}
+
+	// Excluded files are not counted but are still replayed.
+	r, err = query.Rework(db, from, to, query.GranularityMonth, 30, query.Filter{ExcludeGlobs: []string{"a.go"}})
+	if err != nil {
+		t.Fatalf("Rework: %v", err)
+	}
+	if r.Churn != 2 || r.Rework != 1 || len(r.Files) != 1 || r.Files[0].Path != "b.go" {
+		t.Errorf("got %+v, want only b.go's rewritten line", r)
+	}
+}
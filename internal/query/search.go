@@ -0,0 +1,122 @@
+package query
+
+import (
+	"database/sql"
+	"html"
+	"strings"
+	"time"
+)
+
+// CommitMatch is a commit whose message matched a search.
+type CommitMatch struct {
+	Hash        string `json:"hash"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	Date        string `json:"date"`
+	// Subject and Snippet are HTML-escaped with matched terms wrapped in
+	// <mark></mark>. Snippet is an excerpt of the description around the
+	// best match, or empty if the description did not match.
+	Subject string  `json:"subject"`
+	Snippet string  `json:"snippet"`
+	Score   float64 `json:"score"` // higher is more relevant
+}
+
+// Highlight markers used inside SQL; they cannot occur in escaped text.
+const (
+	markOpen  = "\x01"
+	markClose = "\x02"
+)
+
+// SearchCommits performs a full-text search over commit subjects and
+// descriptions for commits between from (inclusive) and to (exclusive),
+// returning up to limit matches ordered by relevance. Subject matches weigh
+// more than description matches, and words are stemmed, so "refresh" also
+// finds "refreshing". Every whitespace-separated term must match; a
+// trailing "*" makes a term a prefix match. If email is non-empty, results
+// are filtered to that author; if pathPrefix is non-empty, to commits
+// touching files under it. Only commits kept by filter are returned.
+func SearchCommits(db *sql.DB, text string, from, to time.Time, email, pathPrefix string, limit int, filter Filter) ([]CommitMatch, error) {
+	match := ftsQuery(text)
+	if match == "" {
+		return nil, nil
+	}
+
+	q := `SELECT c.hash, c.author_name, c.author_email,
+	        date(` + commitLocalTime + `, 'unixepoch'),
+	        highlight(commits_fts, 0, char(1), char(2)),
+	        snippet(commits_fts, 1, char(1), char(2), '…', 24),
+	        bm25(commits_fts, 10.0, 1.0) AS rank
+	 FROM commits_fts
+	 JOIN commits c ON c.id = commits_fts.rowid
+	 WHERE commits_fts MATCH ? AND ` + commitInRange
+	args := []any{match, wallClock(from), wallClock(to)}
+	commitSQL, commitArgs := filter.commitClauses()
+	q += commitSQL
+	args = append(args, commitArgs...)
+	if email != "" {
+		q += ` AND c.author_email = ?`
+		args = append(args, email)
+	}
+	if pathPrefix != "" {
+		underSQL, underArgs := underPath("fs.file_path", pathPrefix)
+		q += ` AND EXISTS (SELECT 1 FROM file_stats fs
+		                   WHERE fs.commit_hash = c.hash AND ` + underSQL + `)`
+		args = append(args, underArgs...)
+	}
+	q += `
+	 ORDER BY rank
+	 LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []CommitMatch
+	for rows.Next() {
+		var m CommitMatch
+		var rank float64
+		if err := rows.Scan(&m.Hash, &m.AuthorName, &m.AuthorEmail, &m.Date, &m.Subject, &m.Snippet, &rank); err != nil {
+			return nil, err
+		}
+		m.Subject = markHTML(m.Subject)
+		if strings.Contains(m.Snippet, markOpen) {
+			m.Snippet = markHTML(m.Snippet)
+		} else {
+			m.Snippet = ""
+		}
+		// bm25 is negative, with more relevant rows further below zero.
+		m.Score = -rank
+		result = append(result, m)
+	}
+	return result, rows.Err()
+}
+
+// ftsQuery turns free text into an FTS5 query that matches rows containing
+// every term. Terms are quoted so that punctuation and FTS5 operators in user
+// input are treated literally; a trailing "*" is kept as a prefix match.
+func ftsQuery(text string) string {
+	var terms []string
+	for _, field := range strings.Fields(text) {
+		prefix := strings.HasSuffix(field, "*")
+		field = strings.TrimRight(field, "*")
+		if field == "" {
+			continue
+		}
+		term := `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " ")
+}
+
+// markHTML escapes s for HTML and turns the highlight markers into <mark>.
+func markHTML(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, markOpen, "<mark>")
+	return strings.ReplaceAll(s, markClose, "</mark>")
+}
@@ -0,0 +1,142 @@
+package query_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func insertCommitWithDescription(t *testing.T, db *sql.DB, hash, name, email string, at time.Time, msg, desc string) {
+	t.Helper()
+	_, offset := at.Zone()
+	_, err := db.Exec(
+		`INSERT INTO commits (hash, author_name, author_email, committed_at, tz_offset, message, description) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		hash, name, email, at.Unix(), offset/60, msg, desc,
+	)
+	if err != nil {
+		t.Fatalf("insert commit: %v", err)
+	}
+}
+
+func TestSearchCommits_RanksSubjectMatchesFirst(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommitWithDescription(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+		"Update dependencies", "Also touches the token refresh helper in passing.")
+	insertCommitWithDescription(t, db, "bbb1", "Bob", "bob@example.com",
+		time.Date(2025, 1, 16, 10, 0, 0, 0, time.UTC),
+		"Fix auth token refreshing", "Sessions expired early.")
+	insertCommitWithDescription(t, db, "ccc1", "Carol", "carol@example.com",
+		time.Date(2025, 1, 17, 10, 0, 0, 0, time.UTC),
+		"Add logging", "")
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	matches, err := query.SearchCommits(db, "token refresh", from, to, "", "", 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("SearchCommits: %v", err)
+	}
+
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d: %+v", len(matches), matches)
+	}
+	if matches[0].Hash != "bbb1" {
+		t.Errorf("expected subject match first, got %s", matches[0].Hash)
+	}
+	if matches[0].Subject != "Fix auth <mark>token</mark> <mark>refreshing</mark>" {
+		t.Errorf("subject highlight: got %q", matches[0].Subject)
+	}
+	if matches[0].Snippet != "" {
+		t.Errorf("expected no snippet for non-matching description, got %q", matches[0].Snippet)
+	}
+	if matches[1].Snippet == "" || matches[1].Date != "2025-01-15" {
+		t.Errorf("description match: got %+v", matches[1])
+	}
+	if matches[0].Score <= matches[1].Score {
+		t.Errorf("expected descending scores, got %f then %f", matches[0].Score, matches[1].Score)
+	}
+}
+
+func TestSearchCommits_Filters(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), "fix cache bug")
+	insertCommit(t, db, "bbb1", "Bob", "bob@example.com",
+		time.Date(2025, 1, 16, 10, 0, 0, 0, time.UTC), "fix cache eviction")
+	insertCommit(t, db, "bbb2", "Bob", "bob@example.com",
+		time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC), "fix cache again")
+
+	insertFileStat(t, db, "aaa1", "cache/lru.go", 1, 1)
+	insertFileStat(t, db, "bbb1", "server/handler.go", 1, 1)
+	insertFileStat(t, db, "bbb1", "cachegen/lru.go", 1, 1)
+	insertFileStat(t, db, "bbb2", "cache/lru.go", 1, 1)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	byAuthor, err := query.SearchCommits(db, "cache", from, to, "bob@example.com", "", 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("SearchCommits: %v", err)
+	}
+	if len(byAuthor) != 1 || byAuthor[0].Hash != "bbb1" {
+		t.Errorf("author filter: got %+v", byAuthor)
+	}
+
+	byPath, err := query.SearchCommits(db, "cache", from, to, "", "cache", 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("SearchCommits: %v", err)
+	}
+	if len(byPath) != 1 || byPath[0].Hash != "aaa1" {
+		t.Errorf("path filter: got %+v", byPath)
+	}
+}
+
+func TestSearchCommits_PrefixAndSpecialCharacters(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), `Handle "quoted" <input> in parser`)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	matches, err := query.SearchCommits(db, `pars* "quoted OR`, from, to, "", "", 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("SearchCommits: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("expected no match for literal OR term, got %+v", matches)
+	}
+
+	matches, err = query.SearchCommits(db, `pars* "quoted`, from, to, "", "", 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("SearchCommits: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %+v", matches)
+	}
+	want := `Handle &#34;<mark>quoted</mark>&#34; &lt;input&gt; in <mark>parser</mark>`
+	if matches[0].Subject != want {
+		t.Errorf("subject: got %q, want %q", matches[0].Subject, want)
+	}
+}
+
+func TestSearchCommits_EmptyQuery(t *testing.T) {
+	db := setupDB(t)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	matches, err := query.SearchCommits(db, "   ", from, to, "", "", 10, query.Filter{})
+	if err != nil {
+		t.Fatalf("SearchCommits: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("expected no matches, got %d", len(matches))
+	}
+}
@@ -0,0 +1,173 @@
+package query
+
+import (
+	"database/sql"
+	"path"
+	"sort"
+	"time"
+)
+
+// SurvivalGrouping selects how SurvivalCurves groups lines.
+type SurvivalGrouping string
+
+const (
+	SurvivalByAuthor    SurvivalGrouping = "author"
+	SurvivalByCohort    SurvivalGrouping = "cohort"
+	SurvivalByDirectory SurvivalGrouping = "directory"
+)
+
+// SurvivalPoint is the share of a group's lines still alive a number of
+// months after they were written.
+type SurvivalPoint struct {
+	AgeMonths int     `json:"age_months"`
+	Lines     int     `json:"lines"`    // lines alive at this age
+	Initial   int     `json:"initial"`  // lines alive at age 0, for the cohorts this old
+	Fraction  float64 `json:"fraction"` // Lines / Initial
+}
+
+// SurvivalCurve is the line survival of one author, cohort month or
+// directory.
+type SurvivalCurve struct {
+	Key          string          `json:"key"`  // author email, YYYY-MM or directory
+	Name         string          `json:"name"` // author name when grouped by author
+	Cohorts      int             `json:"cohorts"`
+	InitialLines int             `json:"initial_lines"`
+	Points       []SurvivalPoint `json:"points"`
+}
+
+// SurvivalCurves returns line survival curves from the monthly samples
+// recorded by indexer.IndexSurvival. Lines are put in cohorts by the
+// author-local month of the commit that last changed them; a cohort's age 0
+// is the sample at the end of that month. The fraction at age k pools every
+// cohort of the group that has reached age k, dividing the lines still alive
+// by those alive at age 0. Curves are sorted by initial lines descending.
+// Lines of commits or files excluded by filter are omitted.
+func SurvivalCurves(db *sql.DB, groupBy SurvivalGrouping, filter Filter) ([]SurvivalCurve, error) {
+	var latest sql.NullString
+	if err := db.QueryRow(`SELECT MAX(month) FROM survival_samples`).Scan(&latest); err != nil {
+		return nil, err
+	}
+	if !latest.Valid {
+		return []SurvivalCurve{}, nil
+	}
+
+	excludeSQL, excludeArgs := filter.fileClauses("sl.path")
+	commitSQL, commitArgs := filter.commitClauses()
+	rows, err := db.Query(
+		`SELECT sl.month, sl.path, strftime('%Y-%m', `+commitLocalTime+`, 'unixepoch'),
+		        c.author_email, c.author_name, sl.lines
+		 FROM survival_lines sl
+		 JOIN commits c ON c.hash = sl.commit_hash
+		 WHERE 1 = 1`+commitSQL+excludeSQL, append(commitArgs, excludeArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type group struct {
+		name  string
+		lines map[string]map[int]int // cohort -> age -> lines
+	}
+	groups := make(map[string]*group)
+	for rows.Next() {
+		var month, p, cohort, email, name string
+		var lines int
+		if err := rows.Scan(&month, &p, &cohort, &email, &name, &lines); err != nil {
+			return nil, err
+		}
+		age, err := monthsBetween(cohort, month)
+		if err != nil {
+			return nil, err
+		}
+		// Lines committed just before a month ended in UTC but dated in the
+		// next month locally are counted from their own month's sample.
+		if age < 0 {
+			continue
+		}
+		var key string
+		switch groupBy {
+		case SurvivalByCohort:
+			key = cohort
+		case SurvivalByDirectory:
+			key = path.Dir(p)
+		default:
+			key = email
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &group{lines: make(map[string]map[int]int)}
+			groups[key] = g
+		}
+		if groupBy == SurvivalByAuthor || groupBy == "" {
+			g.name = name
+		}
+		if g.lines[cohort] == nil {
+			g.lines[cohort] = make(map[int]int)
+		}
+		g.lines[cohort][age] += lines
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]SurvivalCurve, 0, len(groups))
+	for key, g := range groups {
+		curve := SurvivalCurve{Key: key, Name: g.name}
+		var alive, initial []int
+		for cohort, ages := range g.lines {
+			// Cohorts written before the first sample have no age 0.
+			if ages[0] == 0 {
+				continue
+			}
+			oldest, err := monthsBetween(cohort, latest.String)
+			if err != nil {
+				return nil, err
+			}
+			for len(alive) <= oldest {
+				alive = append(alive, 0)
+				initial = append(initial, 0)
+			}
+			for age := 0; age <= oldest; age++ {
+				alive[age] += ages[age]
+				initial[age] += ages[0]
+			}
+			curve.Cohorts++
+			curve.InitialLines += ages[0]
+		}
+		if curve.Cohorts == 0 {
+			continue
+		}
+		curve.Points = make([]SurvivalPoint, len(alive))
+		for age := range alive {
+			curve.Points[age] = SurvivalPoint{
+				AgeMonths: age,
+				Lines:     alive[age],
+				Initial:   initial[age],
+				Fraction:  float64(alive[age]) / float64(initial[age]),
+			}
+		}
+		result = append(result, curve)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].InitialLines != result[j].InitialLines {
+			return result[i].InitialLines > result[j].InitialLines
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result, nil
+}
+
+// monthsBetween returns the number of calendar months from one YYYY-MM month
+// to another.
+func monthsBetween(from, to string) (int, error) {
+	f, err := time.Parse("2006-01", from)
+	if err != nil {
+		return 0, err
+	}
+	t, err := time.Parse("2006-01", to)
+	if err != nil {
+		return 0, err
+	}
+	return (t.Year()-f.Year())*12 + int(t.Month()-f.Month()), nil
+}
@@ -0,0 +1,97 @@
+package query_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func insertSurvival(t *testing.T, db *sql.DB, month, path, commit string, lines int) {
+	t.Helper()
+	if _, err := db.Exec(`INSERT OR IGNORE INTO survival_samples (month, rev) VALUES (?, ?)`, month, "rev-"+month); err != nil {
+		t.Fatalf("insert survival sample: %v", err)
+	}
+	_, err := db.Exec(
+		`INSERT INTO survival_lines (month, path, commit_hash, lines) VALUES (?, ?, ?, ?)`,
+		month, path, commit, lines,
+	)
+	if err != nil {
+		t.Fatalf("insert survival lines: %v", err)
+	}
+}
+
+func TestSurvivalCurves(t *testing.T) {
+	db := setupDB(t)
+
+	// Alice writes 100 lines in January and 50 in February; Bob writes 40
+	// vendored lines in February. Alice's February commit was made on
+	// January 31st in UTC, but already in February in her zone.
+	tokyo := time.FixedZone("", 9*60*60)
+	insertCommit(t, db, "a0", "Alice", "alice@example.com", time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC), "old")
+	insertCommit(t, db, "a1", "Alice", "alice@example.com", time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), "jan")
+	insertCommit(t, db, "a2", "Alice", "alice@example.com", time.Date(2025, 2, 1, 8, 0, 0, 0, tokyo), "feb")
+	insertCommit(t, db, "b2", "Bob", "bob@example.com", time.Date(2025, 2, 10, 10, 0, 0, 0, time.UTC), "vendor")
+	insertSurvival(t, db, "2025-01", "src/a.go", "a1", 100)
+	insertSurvival(t, db, "2025-02", "src/a.go", "a1", 60)
+	insertSurvival(t, db, "2025-02", "src/a.go", "a2", 50)
+	insertSurvival(t, db, "2025-02", "vendor/x.go", "b2", 40)
+	insertSurvival(t, db, "2025-03", "src/a.go", "a1", 30)
+	insertSurvival(t, db, "2025-03", "src/a.go", "a2", 45)
+	insertSurvival(t, db, "2025-03", "vendor/x.go", "b2", 40)
+	// Lines older than the first sample have no age 0 and are ignored.
+	insertSurvival(t, db, "2025-03", "src/a.go", "a0", 5)
+
+	curves, err := query.SurvivalCurves(db, query.SurvivalByAuthor, query.Filter{})
+	if err != nil {
+		t.Fatalf("SurvivalCurves: %v", err)
+	}
+	if len(curves) != 2 {
+		t.Fatalf("expected 2 curves, got %+v", curves)
+	}
+	alice := curves[0]
+	if alice.Key != "alice@example.com" || alice.Cohorts != 2 || alice.InitialLines != 150 || len(alice.Points) != 3 {
+		t.Fatalf("got %+v, want Alice with 2 cohorts of 150 lines over 3 ages", alice)
+	}
+	// Age 1 pools both cohorts: (60 + 45) / (100 + 50). Age 2 has January only.
+	want := []query.SurvivalPoint{
+		{AgeMonths: 0, Lines: 150, Initial: 150, Fraction: 1},
+		{AgeMonths: 1, Lines: 105, Initial: 150, Fraction: 0.7},
+		{AgeMonths: 2, Lines: 30, Initial: 100, Fraction: 0.3},
+	}
+	for i, p := range want {
+		if alice.Points[i] != p {
+			t.Errorf("point %d: got %+v, want %+v", i, alice.Points[i], p)
+		}
+	}
+
+	curves, err = query.SurvivalCurves(db, query.SurvivalByCohort, query.Filter{ExcludeGlobs: []string{"vendor/*"}})
+	if err != nil {
+		t.Fatalf("SurvivalCurves: %v", err)
+	}
+	if len(curves) != 2 || curves[0].Key != "2025-01" || curves[1].Key != "2025-02" {
+		t.Fatalf("got %+v, want the January and February cohorts", curves)
+	}
+	if feb := curves[1]; feb.InitialLines != 50 || len(feb.Points) != 2 || feb.Points[1].Fraction != 0.9 {
+		t.Errorf("got %+v, want February at 90%% after a month without vendor/", feb)
+	}
+
+	curves, err = query.SurvivalCurves(db, query.SurvivalByDirectory, query.Filter{})
+	if err != nil {
+		t.Fatalf("SurvivalCurves: %v", err)
+	}
+	if len(curves) != 2 || curves[0].Key != "src" || curves[1].Key != "vendor" || curves[1].Points[1].Fraction != 1 {
+		t.Errorf("got %+v, want src and a fully surviving vendor", curves)
+	}
+
+	// Only Alice's January commit is a feature.
+	setChangeType(t, db, "a1", "feat")
+	curves, err = query.SurvivalCurves(db, query.SurvivalByCohort, query.Filter{ChangeTypes: []string{"feat"}})
+	if err != nil {
+		t.Fatalf("SurvivalCurves: %v", err)
+	}
+	if len(curves) != 1 || curves[0].Key != "2025-01" || curves[0].InitialLines != 100 {
+		t.Errorf("got %+v, want the January cohort only", curves)
+	}
+}
@@ -0,0 +1,203 @@
+package query
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Granularity selects the calendar bucket size of a time series.
+type Granularity string
+
+const (
+	GranularityDay     Granularity = "day"
+	GranularityWeek    Granularity = "week"
+	GranularityMonth   Granularity = "month"
+	GranularityQuarter Granularity = "quarter"
+)
+
+// periodExpr returns the SQL expression labelling commits aliased as c with
+// their bucket in the author's local time. Labels are "2006-01-02" for days,
+// the Monday starting the week for weeks, "2006-01" for months and "2006-Q1"
+// for quarters; they sort chronologically.
+func periodExpr(g Granularity) (string, error) {
+	return periodExprOf(commitLocalTime, g)
+}
+
+// periodExprOf is like periodExpr but buckets the seconds since the Unix
+// epoch given by the SQL expression t.
+func periodExprOf(t string, g Granularity) (string, error) {
+	switch g {
+	case GranularityDay:
+		return `date(` + t + `, 'unixepoch')`, nil
+	case GranularityWeek:
+		// 'weekday 0' advances to the next Sunday (or stays on one), so
+		// stepping back six days lands on the week's Monday.
+		return `date(` + t + `, 'unixepoch', 'weekday 0', '-6 days')`, nil
+	case GranularityMonth:
+		return `strftime('%Y-%m', ` + t + `, 'unixepoch')`, nil
+	case GranularityQuarter:
+		return `strftime('%Y', ` + t + `, 'unixepoch') || '-Q' ||
+		        ((CAST(strftime('%m', ` + t + `, 'unixepoch') AS INTEGER) + 2) / 3)`, nil
+	}
+	return "", fmt.Errorf("unknown granularity %q", g)
+}
+
+// periodStart returns the start of the bucket containing t's calendar date.
+func periodStart(t time.Time, g Granularity) time.Time {
+	y, m, d := t.Date()
+	switch g {
+	case GranularityWeek:
+		day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case GranularityMonth:
+		return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+	case GranularityQuarter:
+		return time.Date(y, m-(m-1)%3, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// nextPeriod returns the start of the bucket following the one starting at t.
+func nextPeriod(t time.Time, g Granularity) time.Time {
+	switch g {
+	case GranularityWeek:
+		return t.AddDate(0, 0, 7)
+	case GranularityMonth:
+		return t.AddDate(0, 1, 0)
+	case GranularityQuarter:
+		return t.AddDate(0, 3, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// periodLabel formats the bucket starting at t the same way periodExpr does.
+func periodLabel(t time.Time, g Granularity) string {
+	switch g {
+	case GranularityMonth:
+		return t.Format("2006-01")
+	case GranularityQuarter:
+		return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())+2)/3)
+	}
+	return t.Format("2006-01-02")
+}
+
+// periodLabels returns the labels of every bucket overlapping [from, to).
+func periodLabels(from, to time.Time, g Granularity) []string {
+	var labels []string
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	for t := periodStart(from, g); t.Before(end); t = nextPeriod(t, g) {
+		labels = append(labels, periodLabel(t, g))
+	}
+	return labels
+}
+
+// SeriesPoint holds activity totals for one time bucket.
+type SeriesPoint struct {
+	Period       string `json:"period"`
+	Commits      int    `json:"commits"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+	Authors      int    `json:"authors"`
+	FilesTouched int    `json:"files_touched"`
+}
+
+// ActivitySeries returns commits, additions, deletions, active authors and
+// files touched per bucket of the given granularity for commits between from
+// (inclusive) and to (exclusive). Every bucket in the range is returned in
+// chronological order, including empty ones, so gaps show up as zeros.
+//
+// If pathPrefix is non-empty, only changes to files under it are counted and
+// only commits touching such files contribute. If email is non-empty,
+// results are filtered to that author. Only commits kept by filter count, and
+// files it excludes are left out of file-level metrics.
+func ActivitySeries(db *sql.DB, from, to time.Time, g Granularity, pathPrefix, email string, filter Filter) ([]SeriesPoint, error) {
+	return activitySeries(db, from, to, g, seriesScope{pathPrefix: pathPrefix, email: email}, filter)
+}
+
+// seriesScope narrows an activity series to a set of files and/or an author.
+// Empty fields do not constrain the series. Files given by paths are chosen
+// explicitly, so the filter's file clauses do not apply to them.
+type seriesScope struct {
+	pathPrefix string
+	paths      []string
+	email      string
+}
+
+func activitySeries(db *sql.DB, from, to time.Time, g Granularity, scope seriesScope, filter Filter) ([]SeriesPoint, error) {
+	period, err := periodExpr(g)
+	if err != nil {
+		return nil, err
+	}
+	var excludeSQL string
+	var excludeArgs []any
+	if len(scope.paths) == 0 {
+		excludeSQL, excludeArgs = filter.fileClauses("fs.file_path")
+	}
+	commitSQL, commitArgs := filter.commitClauses()
+
+	q := `SELECT ` + period + ` AS period,
+	        COUNT(DISTINCT c.hash) AS commits,
+	        COALESCE(SUM(fs.additions), 0) AS additions,
+	        COALESCE(SUM(fs.deletions), 0) AS deletions,
+	        COUNT(DISTINCT c.author_email) AS authors,
+	        COUNT(DISTINCT fs.file_path) AS files_touched
+	 FROM commits c
+	 LEFT JOIN file_stats fs ON fs.commit_hash = c.hash` + excludeSQL
+
+	args := make([]any, 0, len(excludeArgs)+len(scope.paths)+5)
+	args = append(args, excludeArgs...)
+	if scope.pathPrefix != "" {
+		underSQL, underArgs := underPath("fs.file_path", scope.pathPrefix)
+		q += ` AND ` + underSQL
+		args = append(args, underArgs...)
+	}
+	if len(scope.paths) > 0 {
+		q += ` AND fs.file_path IN (` + placeholders(len(scope.paths)) + `)`
+		for _, p := range scope.paths {
+			args = append(args, p)
+		}
+	}
+	q += `
+	 WHERE ` + commitInRange + commitSQL
+	args = append(args, wallClock(from), wallClock(to))
+	args = append(args, commitArgs...)
+	if scope.pathPrefix != "" || len(scope.paths) > 0 {
+		q += ` AND fs.file_path IS NOT NULL`
+	}
+	if scope.email != "" {
+		q += ` AND c.author_email = ?`
+		args = append(args, scope.email)
+	}
+	q += `
+	 GROUP BY period`
+
+	rows, err := db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byPeriod := make(map[string]SeriesPoint)
+	for rows.Next() {
+		var p SeriesPoint
+		if err := rows.Scan(&p.Period, &p.Commits, &p.Additions, &p.Deletions, &p.Authors, &p.FilesTouched); err != nil {
+			return nil, err
+		}
+		byPeriod[p.Period] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	labels := periodLabels(from, to, g)
+	result := make([]SeriesPoint, len(labels))
+	for i, label := range labels {
+		p, ok := byPeriod[label]
+		if !ok {
+			p = SeriesPoint{Period: label}
+		}
+		result[i] = p
+	}
+	return result, nil
+}
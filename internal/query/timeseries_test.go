@@ -0,0 +1,178 @@
+package query_test
+
+import (
+	"testing"
+	"time"
+
+	"git-analytics/internal/query"
+)
+
+func TestActivitySeries_Monthly(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), "jan 1")
+	insertCommit(t, db, "bbb1", "Bob", "bob@example.com",
+		time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC), "jan 2")
+	insertCommit(t, db, "aaa2", "Alice", "alice@example.com",
+		time.Date(2025, 3, 5, 10, 0, 0, 0, time.UTC), "mar")
+
+	insertFileStat(t, db, "aaa1", "main.go", 10, 5)
+	insertFileStat(t, db, "aaa1", "util.go", 3, 2)
+	insertFileStat(t, db, "bbb1", "main.go", 20, 10)
+	insertFileStat(t, db, "aaa2", "main.go", 1, 1)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	series, err := query.ActivitySeries(db, from, to, query.GranularityMonth, "", "", query.Filter{})
+	if err != nil {
+		t.Fatalf("ActivitySeries: %v", err)
+	}
+
+	if len(series) != 3 {
+		t.Fatalf("expected 3 months, got %d: %v", len(series), series)
+	}
+	want := []query.SeriesPoint{
+		{Period: "2025-01", Commits: 2, Additions: 33, Deletions: 17, Authors: 2, FilesTouched: 2},
+		{Period: "2025-02"},
+		{Period: "2025-03", Commits: 1, Additions: 1, Deletions: 1, Authors: 1, FilesTouched: 1},
+	}
+	for i, w := range want {
+		if series[i] != w {
+			t.Errorf("point %d: got %+v, want %+v", i, series[i], w)
+		}
+	}
+}
+
+func TestActivitySeries_WeeklyStartsOnMonday(t *testing.T) {
+	db := setupDB(t)
+
+	// Sunday 2025-01-19 belongs to the week starting Monday 2025-01-13.
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 19, 23, 0, 0, 0, time.UTC), "sunday")
+	// Monday 2025-01-20 starts the next week.
+	insertCommit(t, db, "aaa2", "Alice", "alice@example.com",
+		time.Date(2025, 1, 20, 1, 0, 0, 0, time.UTC), "monday")
+
+	from := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 27, 0, 0, 0, 0, time.UTC)
+
+	series, err := query.ActivitySeries(db, from, to, query.GranularityWeek, "", "", query.Filter{})
+	if err != nil {
+		t.Fatalf("ActivitySeries: %v", err)
+	}
+
+	if len(series) != 2 {
+		t.Fatalf("expected 2 weeks, got %d: %v", len(series), series)
+	}
+	if series[0].Period != "2025-01-13" || series[0].Commits != 1 {
+		t.Errorf("week 0: got %+v, want {2025-01-13, 1 commit}", series[0])
+	}
+	if series[1].Period != "2025-01-20" || series[1].Commits != 1 {
+		t.Errorf("week 1: got %+v, want {2025-01-20, 1 commit}", series[1])
+	}
+}
+
+func TestActivitySeries_Quarterly(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 2, 15, 10, 0, 0, 0, time.UTC), "q1")
+	insertCommit(t, db, "aaa2", "Alice", "alice@example.com",
+		time.Date(2025, 6, 30, 10, 0, 0, 0, time.UTC), "q2")
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	series, err := query.ActivitySeries(db, from, to, query.GranularityQuarter, "", "", query.Filter{})
+	if err != nil {
+		t.Fatalf("ActivitySeries: %v", err)
+	}
+
+	if len(series) != 2 {
+		t.Fatalf("expected 2 quarters, got %d: %v", len(series), series)
+	}
+	if series[0].Period != "2025-Q1" || series[0].Commits != 1 {
+		t.Errorf("quarter 0: got %+v, want {2025-Q1, 1 commit}", series[0])
+	}
+	if series[1].Period != "2025-Q2" || series[1].Commits != 1 {
+		t.Errorf("quarter 1: got %+v, want {2025-Q2, 1 commit}", series[1])
+	}
+}
+
+func TestActivitySeries_PathPrefixAndAuthor(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), "alice api")
+	insertCommit(t, db, "aaa2", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC), "alice docs")
+	insertCommit(t, db, "bbb1", "Bob", "bob@example.com",
+		time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC), "bob api")
+
+	insertFileStat(t, db, "aaa1", "api/server.go", 10, 5)
+	insertFileStat(t, db, "aaa1", "README.md", 1, 0)
+	insertFileStat(t, db, "aaa2", "docs/guide.md", 7, 0)
+	insertFileStat(t, db, "bbb1", "api/client.go", 4, 4)
+
+	from := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)
+
+	series, err := query.ActivitySeries(db, from, to, query.GranularityDay, "api/", "", query.Filter{})
+	if err != nil {
+		t.Fatalf("ActivitySeries: %v", err)
+	}
+	want := query.SeriesPoint{Period: "2025-01-15", Commits: 2, Additions: 14, Deletions: 9, Authors: 2, FilesTouched: 2}
+	if len(series) != 1 || series[0] != want {
+		t.Fatalf("path prefix: got %+v, want [%+v]", series, want)
+	}
+
+	series, err = query.ActivitySeries(db, from, to, query.GranularityDay, "api/", "alice@example.com", query.Filter{})
+	if err != nil {
+		t.Fatalf("ActivitySeries: %v", err)
+	}
+	want = query.SeriesPoint{Period: "2025-01-15", Commits: 1, Additions: 10, Deletions: 5, Authors: 1, FilesTouched: 1}
+	if len(series) != 1 || series[0] != want {
+		t.Errorf("path prefix + author: got %+v, want [%+v]", series, want)
+	}
+}
+
+func TestActivitySeries_PathPrefixSiblings(t *testing.T) {
+	db := setupDB(t)
+
+	insertCommit(t, db, "aaa1", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), "src")
+	insertCommit(t, db, "aaa2", "Alice", "alice@example.com",
+		time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC), "siblings")
+	insertFileStat(t, db, "aaa1", "src/main.go", 3, 1)
+	insertFileStat(t, db, "aaa2", "srcgen/main.go", 50, 0)
+	insertFileStat(t, db, "aaa2", "src_old.go", 20, 0)
+
+	from := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)
+
+	// With or without a trailing slash, the prefix is a directory, so its
+	// siblings sharing the name do not count.
+	want := query.SeriesPoint{Period: "2025-01-15", Commits: 1, Additions: 3, Deletions: 1, Authors: 1, FilesTouched: 1}
+	for _, prefix := range []string{"src", "src/"} {
+		series, err := query.ActivitySeries(db, from, to, query.GranularityDay, prefix, "", query.Filter{})
+		if err != nil {
+			t.Fatalf("ActivitySeries: %v", err)
+		}
+		if len(series) != 1 || series[0] != want {
+			t.Errorf("%q: got %+v, want [%+v]", prefix, series, want)
+		}
+	}
+}
+
+func TestActivitySeries_UnknownGranularity(t *testing.T) {
+	db := setupDB(t)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := query.ActivitySeries(db, from, to, "year", "", "", query.Filter{}); err == nil {
+		t.Error("expected error for unknown granularity")
+	}
+}
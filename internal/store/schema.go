@@ -3,24 +3,243 @@ package store
 // SchemaSQL contains the DDL for the analytics database.
 const SchemaSQL = `
 CREATE TABLE IF NOT EXISTS commits (
-	hash         VARCHAR PRIMARY KEY,
+	id           INTEGER PRIMARY KEY, -- stable row number, referenced by commits_fts and index_state
+	hash         VARCHAR NOT NULL UNIQUE,
 	author_name  VARCHAR NOT NULL,
 	author_email VARCHAR NOT NULL,
-	committed_at TIMESTAMP NOT NULL,
+	committed_at INTEGER NOT NULL,           -- UTC seconds since the Unix epoch
+	tz_offset    INTEGER NOT NULL DEFAULT 0, -- author UTC offset in minutes
 	message      VARCHAR NOT NULL,
-	description  TEXT NOT NULL DEFAULT ''
+	description  TEXT NOT NULL DEFAULT '',
+	change_type  VARCHAR NOT NULL DEFAULT 'other', -- see package classify
+	change_scope VARCHAR NOT NULL DEFAULT '',
+	breaking     BOOLEAN NOT NULL DEFAULT 0,
+	reverts       VARCHAR NOT NULL DEFAULT '', -- hash of the commit this one reverts
+	revert_method VARCHAR NOT NULL DEFAULT ''  -- how the revert was detected: message or inverse
 );
 
 CREATE TABLE IF NOT EXISTS file_stats (
 	commit_hash VARCHAR NOT NULL,
 	file_path   VARCHAR NOT NULL,
+	old_path    VARCHAR NOT NULL DEFAULT '', -- previous path when renamed
+	status      VARCHAR NOT NULL DEFAULT 'M', -- A, M, D or R (see package git)
+	binary      BOOLEAN NOT NULL DEFAULT 0,
+	size_delta  INTEGER NOT NULL DEFAULT 0, -- change in blob size in bytes, binary files only
+	old_blob    VARCHAR NOT NULL DEFAULT '', -- blob hash before the change, '' if absent
+	new_blob    VARCHAR NOT NULL DEFAULT '', -- blob hash after the change, '' if absent
 	additions   INTEGER NOT NULL,
 	deletions   INTEGER NOT NULL,
 	PRIMARY KEY (commit_hash, file_path)
 );
 
+-- Full-text index over commit messages. It is an external-content table
+-- reading from commits by id, kept in sync by the triggers below.
+CREATE VIRTUAL TABLE IF NOT EXISTS commits_fts USING fts5(
+	message,
+	description,
+	content = 'commits',
+	content_rowid = 'id',
+	tokenize = 'porter unicode61'
+);
+
+CREATE TRIGGER IF NOT EXISTS commits_fts_insert AFTER INSERT ON commits BEGIN
+	INSERT INTO commits_fts (rowid, message, description)
+	VALUES (new.id, new.message, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS commits_fts_delete AFTER DELETE ON commits BEGIN
+	INSERT INTO commits_fts (commits_fts, rowid, message, description)
+	VALUES ('delete', old.id, old.message, old.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS commits_fts_update AFTER UPDATE OF message, description ON commits BEGIN
+	INSERT INTO commits_fts (commits_fts, rowid, message, description)
+	VALUES ('delete', old.id, old.message, old.description);
+	INSERT INTO commits_fts (rowid, message, description)
+	VALUES (new.id, new.message, new.description);
+END;
+
+-- Issue keys referenced by commit messages.
+CREATE TABLE IF NOT EXISTS commit_issues (
+	commit_hash VARCHAR NOT NULL,
+	issue_key   VARCHAR NOT NULL,
+	PRIMARY KEY (commit_hash, issue_key)
+);
+
+-- Issue metadata imported from a tracker export.
+CREATE TABLE IF NOT EXISTS issues (
+	key         VARCHAR PRIMARY KEY,
+	type        VARCHAR NOT NULL DEFAULT '',
+	title       VARCHAR NOT NULL DEFAULT '',
+	status      VARCHAR NOT NULL DEFAULT '',
+	created_at  INTEGER, -- UTC seconds, NULL if unknown
+	resolved_at INTEGER  -- UTC seconds, NULL if unresolved or unknown
+);
+
+-- Pull requests (merge requests on GitLab) imported from a hosting provider.
+CREATE TABLE IF NOT EXISTS pull_requests (
+	number       INTEGER PRIMARY KEY,
+	title        VARCHAR NOT NULL,
+	author       VARCHAR NOT NULL,
+	state        VARCHAR NOT NULL, -- open, merged or closed
+	created_at   INTEGER NOT NULL, -- UTC seconds
+	updated_at   INTEGER,          -- UTC seconds, NULL if unknown
+	merged_at    INTEGER,          -- UTC seconds, NULL if not merged
+	closed_at    INTEGER,          -- UTC seconds, NULL if open
+	merge_commit VARCHAR NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS pr_reviewers (
+	pr_number INTEGER NOT NULL,
+	reviewer  VARCHAR NOT NULL,
+	PRIMARY KEY (pr_number, reviewer)
+);
+
+CREATE TABLE IF NOT EXISTS pr_reviews (
+	pr_number    INTEGER NOT NULL,
+	reviewer     VARCHAR NOT NULL,
+	state        VARCHAR NOT NULL,
+	submitted_at INTEGER -- UTC seconds, NULL if unknown
+);
+
+CREATE TABLE IF NOT EXISTS pr_commits (
+	pr_number   INTEGER NOT NULL,
+	commit_hash VARCHAR NOT NULL,
+	PRIMARY KEY (pr_number, commit_hash)
+);
+
+-- Files in the tree of the most recently indexed HEAD.
+CREATE TABLE IF NOT EXISTS head_files (
+	path      VARCHAR PRIMARY KEY,
+	blob_hash VARCHAR NOT NULL
+);
+
+-- Blame results, cached by the blob blamed. A blob in blamed_blobs has been
+-- blamed; blame_entries holds its lines per commit that last changed them.
+CREATE TABLE IF NOT EXISTS blamed_blobs (
+	path      VARCHAR NOT NULL,
+	blob_hash VARCHAR NOT NULL,
+	PRIMARY KEY (path, blob_hash)
+);
+
+CREATE TABLE IF NOT EXISTS blame_entries (
+	path         VARCHAR NOT NULL,
+	blob_hash    VARCHAR NOT NULL,
+	commit_hash  VARCHAR NOT NULL,
+	author_name  VARCHAR NOT NULL,
+	author_email VARCHAR NOT NULL,
+	committed_at INTEGER NOT NULL,           -- UTC seconds
+	tz_offset    INTEGER NOT NULL DEFAULT 0, -- author UTC offset in minutes
+	lines        INTEGER NOT NULL,
+	PRIMARY KEY (path, blob_hash, commit_hash)
+);
+
+-- Go function declarations, cached by the blob parsed. A blob in
+-- function_blobs has been parsed; functions holds its declarations.
+CREATE TABLE IF NOT EXISTS function_blobs (
+	path        VARCHAR NOT NULL,
+	blob_hash   VARCHAR NOT NULL,
+	parse_error VARCHAR NOT NULL DEFAULT '', -- why the blob did not parse
+	PRIMARY KEY (path, blob_hash)
+);
+
+CREATE TABLE IF NOT EXISTS functions (
+	path       VARCHAR NOT NULL,
+	blob_hash  VARCHAR NOT NULL,
+	name       VARCHAR NOT NULL,
+	start_line INTEGER NOT NULL,
+	end_line   INTEGER NOT NULL,
+	PRIMARY KEY (path, blob_hash, start_line)
+);
+
+-- Size and complexity metrics, cached by the blob measured.
+CREATE TABLE IF NOT EXISTS file_metrics (
+	path       VARCHAR NOT NULL,
+	blob_hash  VARCHAR NOT NULL,
+	lines      INTEGER NOT NULL,
+	code       INTEGER NOT NULL,
+	comment    INTEGER NOT NULL,
+	blank      INTEGER NOT NULL,
+	indent     INTEGER NOT NULL,
+	max_indent INTEGER NOT NULL,
+	cyclomatic INTEGER NOT NULL,
+	PRIMARY KEY (path, blob_hash)
+);
+
+-- The blobs of files at past commits, looked up on demand. blob_hash is ''
+-- if the file did not exist at the commit.
+CREATE TABLE IF NOT EXISTS revision_blobs (
+	commit_hash VARCHAR NOT NULL,
+	path        VARCHAR NOT NULL,
+	blob_hash   VARCHAR NOT NULL,
+	PRIMARY KEY (commit_hash, path)
+);
+
+-- The language of every path in the history, as detected at the commit
+-- recorded in index_state under last_language_commit. Paths of unknown
+-- language are absent.
+CREATE TABLE IF NOT EXISTS file_languages (
+	path     VARCHAR PRIMARY KEY,
+	language VARCHAR NOT NULL
+);
+
+-- Paths of the history that are generated, vendored or documentation, as
+-- classified at the commit recorded in index_state under
+-- last_path_kind_commit. Other paths are absent.
+CREATE TABLE IF NOT EXISTS path_kinds (
+	path          VARCHAR PRIMARY KEY,
+	generated     BOOLEAN NOT NULL DEFAULT FALSE,
+	vendored      BOOLEAN NOT NULL DEFAULT FALSE,
+	documentation BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- Line survival samples: the lines alive at the end of each sampled month,
+-- by the commit that last changed them.
+CREATE TABLE IF NOT EXISTS survival_samples (
+	month VARCHAR PRIMARY KEY, -- YYYY-MM
+	rev   VARCHAR NOT NULL     -- commit sampled, empty if none
+);
+
+CREATE TABLE IF NOT EXISTS survival_lines (
+	month       VARCHAR NOT NULL,
+	path        VARCHAR NOT NULL,
+	commit_hash VARCHAR NOT NULL,
+	lines       INTEGER NOT NULL,
+	PRIMARY KEY (month, path, commit_hash)
+);
+
+-- Changed line ranges per file per commit (see indexer.IndexHunks). seq
+-- orders a file's hunks within the commit; a coalesced hunk spans the many
+-- hunks of a huge diff.
+CREATE TABLE IF NOT EXISTS hunks (
+	commit_hash VARCHAR NOT NULL,
+	file_path   VARCHAR NOT NULL,
+	seq         INTEGER NOT NULL,
+	old_start   INTEGER NOT NULL,
+	old_lines   INTEGER NOT NULL,
+	new_start   INTEGER NOT NULL,
+	new_lines   INTEGER NOT NULL,
+	coalesced   BOOLEAN NOT NULL DEFAULT 0,
+	PRIMARY KEY (commit_hash, file_path, seq)
+);
+
 CREATE TABLE IF NOT EXISTS index_state (
 	key   VARCHAR PRIMARY KEY,
 	value VARCHAR NOT NULL
 );
 `
+
+// IndexSQL contains index DDL. It runs after migrations so that indexes may
+// reference columns added to existing databases.
+const IndexSQL = `
+-- Date ranges and calendar buckets are evaluated in author-local time.
+CREATE INDEX IF NOT EXISTS idx_commits_local_time ON commits (committed_at + tz_offset * 60);
+CREATE INDEX IF NOT EXISTS idx_file_stats_path ON file_stats (file_path);
+CREATE INDEX IF NOT EXISTS idx_file_stats_binary ON file_stats (file_path) WHERE binary;
+CREATE INDEX IF NOT EXISTS idx_file_stats_blobs ON file_stats (new_blob, old_blob);
+CREATE INDEX IF NOT EXISTS idx_hunks_path ON hunks (file_path);
+CREATE INDEX IF NOT EXISTS idx_commits_reverts ON commits (reverts);
+CREATE INDEX IF NOT EXISTS idx_commit_issues_key ON commit_issues (issue_key);
+CREATE INDEX IF NOT EXISTS idx_pr_reviews_number ON pr_reviews (pr_number);
+CREATE INDEX IF NOT EXISTS idx_pr_commits_hash ON pr_commits (commit_hash);
+`
@@ -0,0 +1,52 @@
+package sqlite
+
+import "git-analytics/internal/git"
+
+func (s *sqliteStore) UnblamedFiles(files []git.TreeFile) ([]git.TreeFile, error) {
+	stmt, err := s.db.Prepare(
+		`SELECT NOT EXISTS (SELECT 1 FROM blamed_blobs WHERE path = ? AND blob_hash = ?)
+		    AND NOT EXISTS (SELECT 1 FROM file_stats WHERE file_path = ? AND binary)`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var unblamed []git.TreeFile
+	for _, f := range files {
+		var pending bool
+		if err := stmt.QueryRow(f.Path, f.Blob, f.Path).Scan(&pending); err != nil {
+			return nil, err
+		}
+		if pending {
+			unblamed = append(unblamed, f)
+		}
+	}
+	return unblamed, nil
+}
+
+func (s *sqliteStore) InsertBlame(file git.TreeFile, entries []git.BlameEntry) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		`INSERT OR IGNORE INTO blamed_blobs (path, blob_hash) VALUES (?, ?)`,
+		file.Path, file.Blob,
+	); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		_, offset := e.Date.Zone()
+		if _, err := tx.Exec(
+			`INSERT OR REPLACE INTO blame_entries
+			   (path, blob_hash, commit_hash, author_name, author_email, committed_at, tz_offset, lines)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			file.Path, file.Blob, e.Commit, e.AuthorName, e.AuthorEmail, e.Date.Unix(), offset/60, e.Lines,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,67 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-analytics/internal/git"
+	sqlitestore "git-analytics/internal/store/sqlite"
+)
+
+func TestBlameCache(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	if err := s.InsertCommits([]git.Commit{{
+		Hash: "aaa1", AuthorName: "A", AuthorEmail: "a@example.com", Date: time.Now(), Message: "msg",
+		FilesChanged: []git.FileStat{{Path: "logo.png", Binary: true}},
+	}}); err != nil {
+		t.Fatalf("InsertCommits: %v", err)
+	}
+	head := []git.TreeFile{{Path: "a.go", Blob: "b1"}, {Path: "b.go", Blob: "b2"}, {Path: "logo.png", Blob: "b3"}}
+	if err := s.SetHeadFiles(head); err != nil {
+		t.Fatalf("SetHeadFiles: %v", err)
+	}
+
+	tokyo := time.FixedZone("", 9*60*60)
+	if err := s.InsertBlame(head[0], []git.BlameEntry{
+		{Commit: "aaa1", AuthorName: "A", AuthorEmail: "a@example.com", Date: time.Date(2025, 1, 1, 9, 0, 0, 0, tokyo), Lines: 7},
+	}); err != nil {
+		t.Fatalf("InsertBlame: %v", err)
+	}
+
+	// a.go is cached and logo.png is binary, which leaves b.go.
+	files, err := s.UnblamedFiles(head)
+	if err != nil {
+		t.Fatalf("UnblamedFiles: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "b.go" {
+		t.Errorf("expected only b.go unblamed, got %+v", files)
+	}
+
+	// A new blob at the same path needs blaming again.
+	files, err = s.UnblamedFiles([]git.TreeFile{head[0], {Path: "a.go", Blob: "b4"}})
+	if err != nil {
+		t.Fatalf("UnblamedFiles: %v", err)
+	}
+	if len(files) != 1 || files[0].Blob != "b4" {
+		t.Errorf("expected a.go's new blob unblamed, got %+v", files)
+	}
+
+	var lines, offset int
+	if err := db.QueryRow(`SELECT lines, tz_offset FROM blame_entries WHERE blob_hash = 'b1'`).Scan(&lines, &offset); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if lines != 7 || offset != 540 {
+		t.Errorf("got lines=%d tz_offset=%d, want 7, 540", lines, offset)
+	}
+}
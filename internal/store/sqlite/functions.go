@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"git-analytics/internal/git"
+	"git-analytics/internal/gosrc"
+)
+
+func (s *sqliteStore) UnparsedFiles(files []git.TreeFile) ([]git.TreeFile, error) {
+	stmt, err := s.db.Prepare(`SELECT NOT EXISTS (SELECT 1 FROM function_blobs WHERE path = ? AND blob_hash = ?)`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var unparsed []git.TreeFile
+	for _, f := range files {
+		var pending bool
+		if err := stmt.QueryRow(f.Path, f.Blob).Scan(&pending); err != nil {
+			return nil, err
+		}
+		if pending {
+			unparsed = append(unparsed, f)
+		}
+	}
+	return unparsed, nil
+}
+
+func (s *sqliteStore) InsertFunctions(file git.TreeFile, funcs []gosrc.Func, parseErr error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var parseError string
+	if parseErr != nil {
+		parseError = parseErr.Error()
+	}
+	if _, err := tx.Exec(
+		`INSERT OR REPLACE INTO function_blobs (path, blob_hash, parse_error) VALUES (?, ?, ?)`,
+		file.Path, file.Blob, parseError,
+	); err != nil {
+		return err
+	}
+	for _, f := range funcs {
+		if _, err := tx.Exec(
+			`INSERT OR REPLACE INTO functions (path, blob_hash, name, start_line, end_line)
+			 VALUES (?, ?, ?, ?, ?)`,
+			file.Path, file.Blob, f.Name, f.StartLine, f.EndLine,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
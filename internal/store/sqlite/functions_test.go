@@ -0,0 +1,63 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"git-analytics/internal/git"
+	"git-analytics/internal/gosrc"
+	sqlitestore "git-analytics/internal/store/sqlite"
+)
+
+func TestFunctionCache(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	parsed := git.TreeFile{Path: "a.go", Blob: "b1"}
+	if err := s.InsertFunctions(parsed, []gosrc.Func{{Name: "F", StartLine: 3, EndLine: 9}}, nil); err != nil {
+		t.Fatalf("InsertFunctions: %v", err)
+	}
+	// A file without functions is cached too.
+	empty := git.TreeFile{Path: "doc.go", Blob: "b2"}
+	if err := s.InsertFunctions(empty, nil, nil); err != nil {
+		t.Fatalf("InsertFunctions: %v", err)
+	}
+	// So is a file that does not parse, along with the error.
+	broken := git.TreeFile{Path: "bad.go", Blob: "b4"}
+	if err := s.InsertFunctions(broken, nil, errors.New("expected name")); err != nil {
+		t.Fatalf("InsertFunctions: %v", err)
+	}
+
+	files, err := s.UnparsedFiles([]git.TreeFile{parsed, empty, broken, {Path: "a.go", Blob: "b3"}})
+	if err != nil {
+		t.Fatalf("UnparsedFiles: %v", err)
+	}
+	if len(files) != 1 || files[0].Blob != "b3" {
+		t.Errorf("expected only a.go's new blob unparsed, got %+v", files)
+	}
+
+	var name string
+	var end int
+	if err := db.QueryRow(`SELECT name, end_line FROM functions WHERE blob_hash = 'b1'`).Scan(&name, &end); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if name != "F" || end != 9 {
+		t.Errorf("got %s ending at %d, want F ending at 9", name, end)
+	}
+	var parseError string
+	if err := db.QueryRow(`SELECT parse_error FROM function_blobs WHERE blob_hash = 'b4'`).Scan(&parseError); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if parseError != "expected name" {
+		t.Errorf("got parse error %q, want expected name", parseError)
+	}
+}
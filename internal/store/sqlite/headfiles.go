@@ -0,0 +1,26 @@
+package sqlite
+
+import "git-analytics/internal/git"
+
+func (s *sqliteStore) SetHeadFiles(files []git.TreeFile) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM head_files`); err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO head_files (path, blob_hash) VALUES (?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, f := range files {
+		if _, err := stmt.Exec(f.Path, f.Blob); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,39 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"git-analytics/internal/git"
+	sqlitestore "git-analytics/internal/store/sqlite"
+)
+
+func TestSetHeadFiles(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	if err := s.SetHeadFiles([]git.TreeFile{{Path: "a.go", Blob: "b1"}, {Path: "b.go", Blob: "b2"}}); err != nil {
+		t.Fatalf("SetHeadFiles: %v", err)
+	}
+	// A later HEAD replaces the previous tree entirely.
+	if err := s.SetHeadFiles([]git.TreeFile{{Path: "a.go", Blob: "b3"}}); err != nil {
+		t.Fatalf("SetHeadFiles: %v", err)
+	}
+
+	var count int
+	var blob string
+	if err := db.QueryRow(`SELECT COUNT(*), MAX(blob_hash) FROM head_files`).Scan(&count, &blob); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if count != 1 || blob != "b3" {
+		t.Errorf("got %d files with blob %q, want 1 with b3", count, blob)
+	}
+}
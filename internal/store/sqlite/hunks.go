@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"git-analytics/internal/git"
+)
+
+func (s *sqliteStore) InsertHunks(commits []git.CommitHunks) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(
+		`INSERT OR REPLACE INTO hunks (commit_hash, file_path, seq, old_start, old_lines, new_start, new_lines, coalesced)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, c := range commits {
+		seq := make(map[string]int)
+		for _, h := range c.Hunks {
+			if _, err := stmt.Exec(c.Hash, h.Path, seq[h.Path], h.OldStart, h.OldLines, h.NewStart, h.NewLines, h.Coalesced); err != nil {
+				return err
+			}
+			seq[h.Path]++
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteStore) GetLastHunkCommit() (string, error) {
+	var hash string
+	err := s.db.QueryRow(
+		`SELECT value FROM index_state WHERE key = 'last_hunk_commit'`).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return hash, err
+}
+
+func (s *sqliteStore) SetLastHunkCommit(hash string) error {
+	_, err := s.db.Exec(
+		`INSERT OR REPLACE INTO index_state (key, value)
+		 VALUES ('last_hunk_commit', ?)`, hash)
+	return err
+}
@@ -0,0 +1,59 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"git-analytics/internal/git"
+	sqlitestore "git-analytics/internal/store/sqlite"
+)
+
+func TestInsertHunks(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	if err := s.InsertHunks([]git.CommitHunks{{Hash: "aaa1", Hunks: []git.Hunk{
+		{Path: "a.go", OldStart: 2, OldLines: 1, NewStart: 2, NewLines: 3},
+		{Path: "b.go", OldStart: 0, OldLines: 0, NewStart: 1, NewLines: 4},
+		{Path: "a.go", OldStart: 9, OldLines: 200, NewStart: 11, NewLines: 0, Coalesced: true},
+	}}}); err != nil {
+		t.Fatalf("InsertHunks: %v", err)
+	}
+
+	var seq, oldStart, newLines int
+	var coalesced bool
+	if err := db.QueryRow(
+		`SELECT seq, old_start, new_lines, coalesced FROM hunks WHERE file_path = 'a.go' ORDER BY seq DESC LIMIT 1`,
+	).Scan(&seq, &oldStart, &newLines, &coalesced); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if seq != 1 || oldStart != 9 || newLines != 0 || !coalesced {
+		t.Errorf("got seq=%d old_start=%d new_lines=%d coalesced=%v, want 1, 9, 0, true", seq, oldStart, newLines, coalesced)
+	}
+
+	hash, err := s.GetLastHunkCommit()
+	if err != nil {
+		t.Fatalf("GetLastHunkCommit: %v", err)
+	}
+	if hash != "" {
+		t.Errorf("got last hunk commit %q, want none", hash)
+	}
+	if err := s.SetLastHunkCommit("aaa1"); err != nil {
+		t.Fatalf("SetLastHunkCommit: %v", err)
+	}
+	if hash, err = s.GetLastHunkCommit(); err != nil || hash != "aaa1" {
+		t.Errorf("got last hunk commit %q, %v, want aaa1", hash, err)
+	}
+	// Hunk extraction resumes separately from commit indexing.
+	if hash, err = s.GetLastIndexedCommit(); err != nil || hash != "" {
+		t.Errorf("got last indexed commit %q, %v, want none", hash, err)
+	}
+}
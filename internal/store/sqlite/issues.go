@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"git-analytics/internal/issues"
+)
+
+// index_state keys tracking issue linking progress.
+const (
+	issuePatternsKey = "issue_patterns"
+	issueLinkedKey   = "issue_linked_rowid"
+)
+
+func (s *sqliteStore) LinkIssues(e *issues.Extractor) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var signature string
+	err = tx.QueryRow(`SELECT value FROM index_state WHERE key = ?`, issuePatternsKey).Scan(&signature)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	var linked int64
+	if signature == e.Signature() {
+		var value string
+		err := tx.QueryRow(`SELECT value FROM index_state WHERE key = ?`, issueLinkedKey).Scan(&value)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		linked, _ = strconv.ParseInt(value, 10, 64)
+	} else if _, err := tx.Exec(`DELETE FROM commit_issues`); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(
+		`SELECT id, hash, message, description FROM commits WHERE id > ? ORDER BY id`, linked)
+	if err != nil {
+		return err
+	}
+	type link struct{ hash, key string }
+	var links []link
+	for rows.Next() {
+		var hash, message, description string
+		if err := rows.Scan(&linked, &hash, &message, &description); err != nil {
+			rows.Close()
+			return err
+		}
+		for _, key := range e.Extract(message, description) {
+			links = append(links, link{hash, key})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`INSERT OR IGNORE INTO commit_issues (commit_hash, issue_key) VALUES (?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, l := range links {
+		if _, err := stmt.Exec(l.hash, l.key); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(
+		`INSERT OR REPLACE INTO index_state (key, value) VALUES (?, ?), (?, ?)`,
+		issuePatternsKey, e.Signature(), issueLinkedKey, strconv.FormatInt(linked, 10),
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteStore) ImportIssues(list []issues.Issue) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(
+		`INSERT OR REPLACE INTO issues (key, type, title, status, created_at, resolved_at)
+		 VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, i := range list {
+		if _, err := stmt.Exec(i.Key, i.Type, i.Title, i.Status, unixOrNull(i.CreatedAt), unixOrNull(i.ResolvedAt)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// unixOrNull returns t as UTC epoch seconds, or nil for the zero time.
+func unixOrNull(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Unix()
+}
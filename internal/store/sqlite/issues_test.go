@@ -0,0 +1,113 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"git-analytics/internal/git"
+	"git-analytics/internal/issues"
+	sqlitestore "git-analytics/internal/store/sqlite"
+)
+
+func issueLinks(t *testing.T, db *sql.DB) map[string][]string {
+	t.Helper()
+	rows, err := db.Query(`SELECT commit_hash, issue_key FROM commit_issues ORDER BY commit_hash, issue_key`)
+	if err != nil {
+		t.Fatalf("query commit_issues: %v", err)
+	}
+	defer rows.Close()
+	links := map[string][]string{}
+	for rows.Next() {
+		var hash, key string
+		if err := rows.Scan(&hash, &key); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		links[hash] = append(links[hash], key)
+	}
+	return links
+}
+
+func TestLinkIssues(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	date := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	if err := s.InsertCommits([]git.Commit{
+		{Hash: "aaa1", AuthorName: "A", AuthorEmail: "a@example.com", Date: date, Message: "PROJ-1: login", Description: "Refs #7"},
+		{Hash: "aaa2", AuthorName: "A", AuthorEmail: "a@example.com", Date: date, Message: "no issue"},
+	}); err != nil {
+		t.Fatalf("InsertCommits: %v", err)
+	}
+
+	defaults, _ := issues.NewExtractor(nil)
+	if err := s.LinkIssues(defaults); err != nil {
+		t.Fatalf("LinkIssues: %v", err)
+	}
+	if got, want := issueLinks(t, db), map[string][]string{"aaa1": {"#7", "PROJ-1"}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// Only new commits are scanned on the next run.
+	if err := s.InsertCommits([]git.Commit{
+		{Hash: "aaa3", AuthorName: "A", AuthorEmail: "a@example.com", Date: date, Message: "PROJ-2 logout"},
+	}); err != nil {
+		t.Fatalf("InsertCommits: %v", err)
+	}
+	if _, err := db.Exec(`DELETE FROM commit_issues WHERE commit_hash = 'aaa1'`); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := s.LinkIssues(defaults); err != nil {
+		t.Fatalf("LinkIssues: %v", err)
+	}
+	if got, want := issueLinks(t, db), map[string][]string{"aaa3": {"PROJ-2"}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// Changing the patterns relinks everything.
+	jiraOnly, _ := issues.NewExtractor([]string{`\bPROJ-[0-9]+\b`})
+	if err := s.LinkIssues(jiraOnly); err != nil {
+		t.Fatalf("LinkIssues: %v", err)
+	}
+	if got, want := issueLinks(t, db), map[string][]string{"aaa1": {"PROJ-1"}, "aaa3": {"PROJ-2"}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestImportIssues(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	created := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	if err := s.ImportIssues([]issues.Issue{
+		{Key: "PROJ-1", Type: "Story", CreatedAt: created},
+		{Key: "PROJ-1", Type: "Bug", Title: "Login fails", CreatedAt: created},
+	}); err != nil {
+		t.Fatalf("ImportIssues: %v", err)
+	}
+
+	var issueType string
+	var createdAt, resolvedAt sql.NullInt64
+	if err := db.QueryRow(`SELECT type, created_at, resolved_at FROM issues WHERE key = 'PROJ-1'`).Scan(&issueType, &createdAt, &resolvedAt); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if issueType != "Bug" || createdAt.Int64 != created.Unix() || resolvedAt.Valid {
+		t.Errorf("got (%q, %v, %v), want (Bug, %d, NULL)", issueType, createdAt, resolvedAt, created.Unix())
+	}
+}
@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"git-analytics/internal/linguist"
+)
+
+func (s *sqliteStore) FilePaths() ([]string, error) {
+	rows, err := s.db.Query(`SELECT file_path FROM file_stats UNION SELECT path FROM head_files`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+	return paths, rows.Err()
+}
+
+func (s *sqliteStore) SetFileLanguages(langs map[string]string, rev string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM file_languages`); err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`INSERT INTO file_languages (path, language) VALUES (?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for p, lang := range langs {
+		if _, err := stmt.Exec(p, lang); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(
+		`INSERT OR REPLACE INTO index_state (key, value)
+		 VALUES ('last_language_commit', ?)`, rev); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteStore) GetLastLanguageCommit() (string, error) {
+	var hash string
+	err := s.db.QueryRow(
+		`SELECT value FROM index_state WHERE key = 'last_language_commit'`).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return hash, err
+}
+
+func (s *sqliteStore) SetPathKinds(kinds map[string]linguist.Kinds, rev string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM path_kinds`); err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`INSERT INTO path_kinds (path, generated, vendored, documentation) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for p, k := range kinds {
+		if !k.Any() {
+			continue
+		}
+		if _, err := stmt.Exec(p, k.Generated, k.Vendored, k.Documentation); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(
+		`INSERT OR REPLACE INTO index_state (key, value)
+		 VALUES ('last_path_kind_commit', ?)`, rev); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteStore) GetLastPathKindCommit() (string, error) {
+	var hash string
+	err := s.db.QueryRow(
+		`SELECT value FROM index_state WHERE key = 'last_path_kind_commit'`).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return hash, err
+}
@@ -0,0 +1,105 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"git-analytics/internal/git"
+	"git-analytics/internal/linguist"
+	sqlitestore "git-analytics/internal/store/sqlite"
+)
+
+func TestFileLanguages(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	if err := s.InsertCommits([]git.Commit{{
+		Hash: "aaa1", AuthorEmail: "a@example.com", Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		FilesChanged: []git.FileStat{{Path: "old.py", Status: git.StatusDeleted}, {Path: "main.go", Status: git.StatusAdded}},
+	}}); err != nil {
+		t.Fatalf("InsertCommits: %v", err)
+	}
+	if err := s.SetHeadFiles([]git.TreeFile{{Path: "main.go", Blob: "b1"}, {Path: "run", Blob: "b2"}}); err != nil {
+		t.Fatalf("SetHeadFiles: %v", err)
+	}
+
+	paths, err := s.FilePaths()
+	if err != nil {
+		t.Fatalf("FilePaths: %v", err)
+	}
+	sort.Strings(paths)
+	if len(paths) != 3 || paths[0] != "main.go" || paths[1] != "old.py" || paths[2] != "run" {
+		t.Errorf("got paths %v, want main.go, old.py and run", paths)
+	}
+
+	if err := s.SetFileLanguages(map[string]string{"main.go": "Go", "old.py": "Python"}, "aaa1"); err != nil {
+		t.Fatalf("SetFileLanguages: %v", err)
+	}
+	// Languages are replaced, not merged.
+	if err := s.SetFileLanguages(map[string]string{"main.go": "Go", "run": "Shell"}, "aaa2"); err != nil {
+		t.Fatalf("SetFileLanguages: %v", err)
+	}
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM file_languages WHERE path IN ('main.go', 'run')`).Scan(&n); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	var total int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM file_languages`).Scan(&total); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if n != 2 || total != 2 {
+		t.Errorf("got %d of %d languages for main.go and run, want 2 of 2", n, total)
+	}
+	if rev, err := s.GetLastLanguageCommit(); err != nil || rev != "aaa2" {
+		t.Errorf("got last language commit %q, %v, want aaa2", rev, err)
+	}
+}
+
+func TestPathKinds(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	if err := s.SetPathKinds(map[string]linguist.Kinds{"old.pb.go": {Generated: true}}, "aaa1"); err != nil {
+		t.Fatalf("SetPathKinds: %v", err)
+	}
+	err = s.SetPathKinds(map[string]linguist.Kinds{
+		"api.pb.go":   {Generated: true},
+		"vendor/x.go": {Vendored: true},
+		"docs/a.md":   {Documentation: true},
+		"cmd/main.go": {},
+	}, "aaa2")
+	if err != nil {
+		t.Fatalf("SetPathKinds: %v", err)
+	}
+
+	var total, generated, vendored int
+	if err := db.QueryRow(
+		`SELECT COUNT(*), COALESCE(SUM(generated), 0), COALESCE(SUM(vendored), 0) FROM path_kinds`,
+	).Scan(&total, &generated, &vendored); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	// Plain source files and the paths of earlier runs are not stored.
+	if total != 3 || generated != 1 || vendored != 1 {
+		t.Errorf("got %d paths, %d generated and %d vendored, want 3, 1 and 1", total, generated, vendored)
+	}
+	if rev, err := s.GetLastPathKindCommit(); err != nil || rev != "aaa2" {
+		t.Errorf("got last path kind commit %q, %v, want aaa2", rev, err)
+	}
+}
@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"git-analytics/internal/complexity"
+	"git-analytics/internal/git"
+)
+
+func (s *sqliteStore) UnmeasuredFiles(files []git.TreeFile) ([]git.TreeFile, error) {
+	stmt, err := s.db.Prepare(
+		`SELECT NOT EXISTS (SELECT 1 FROM file_metrics WHERE path = ? AND blob_hash = ?)
+		    AND NOT EXISTS (SELECT 1 FROM file_stats WHERE file_path = ? AND binary)`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var unmeasured []git.TreeFile
+	for _, f := range files {
+		var pending bool
+		if err := stmt.QueryRow(f.Path, f.Blob, f.Path).Scan(&pending); err != nil {
+			return nil, err
+		}
+		if pending {
+			unmeasured = append(unmeasured, f)
+		}
+	}
+	return unmeasured, nil
+}
+
+func (s *sqliteStore) InsertMetrics(metrics map[git.TreeFile]complexity.Metrics) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(
+		`INSERT OR REPLACE INTO file_metrics (path, blob_hash, lines, code, comment, blank, indent, max_indent, cyclomatic)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+	)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for f, m := range metrics {
+		if _, err := stmt.Exec(f.Path, f.Blob, m.Lines, m.Code, m.Comment, m.Blank, m.Indent, m.MaxIndent, m.Cyclomatic); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteStore) UnresolvedRevisions(revs []git.FileRevision) ([]git.FileRevision, error) {
+	stmt, err := s.db.Prepare(`SELECT NOT EXISTS (SELECT 1 FROM revision_blobs WHERE commit_hash = ? AND path = ?)`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var unresolved []git.FileRevision
+	for _, r := range revs {
+		var pending bool
+		if err := stmt.QueryRow(r.Commit, r.Path).Scan(&pending); err != nil {
+			return nil, err
+		}
+		if pending {
+			unresolved = append(unresolved, r)
+		}
+	}
+	return unresolved, nil
+}
+
+func (s *sqliteStore) InsertRevisionBlobs(blobs map[git.FileRevision]string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO revision_blobs (commit_hash, path, blob_hash) VALUES (?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for r, blob := range blobs {
+		if _, err := stmt.Exec(r.Commit, r.Path, blob); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,83 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-analytics/internal/complexity"
+	"git-analytics/internal/git"
+	sqlitestore "git-analytics/internal/store/sqlite"
+)
+
+func TestMetricsCache(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	measured := git.TreeFile{Path: "a.go", Blob: "b1"}
+	err = s.InsertMetrics(map[git.TreeFile]complexity.Metrics{
+		measured: {Lines: 10, Code: 7, Comment: 2, Blank: 1, Indent: 5, MaxIndent: 2, Cyclomatic: 3},
+	})
+	if err != nil {
+		t.Fatalf("InsertMetrics: %v", err)
+	}
+
+	if err := s.InsertCommits([]git.Commit{{
+		Hash: "aaa1", AuthorName: "A", AuthorEmail: "a@example.com", Date: time.Now(), Message: "msg",
+		FilesChanged: []git.FileStat{{Path: "logo.png", Binary: true}},
+	}}); err != nil {
+		t.Fatalf("InsertCommits: %v", err)
+	}
+
+	// a.go's old blob is cached and logo.png is binary, which leaves a.go's
+	// new blob.
+	files, err := s.UnmeasuredFiles([]git.TreeFile{measured, {Path: "a.go", Blob: "b2"}, {Path: "logo.png", Blob: "b3"}})
+	if err != nil {
+		t.Fatalf("UnmeasuredFiles: %v", err)
+	}
+	if len(files) != 1 || files[0].Blob != "b2" {
+		t.Errorf("expected only a.go's new blob unmeasured, got %+v", files)
+	}
+
+	var code, cyclomatic int
+	if err := db.QueryRow(`SELECT code, cyclomatic FROM file_metrics WHERE blob_hash = 'b1'`).Scan(&code, &cyclomatic); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if code != 7 || cyclomatic != 3 {
+		t.Errorf("got code %d and cyclomatic %d, want 7 and 3", code, cyclomatic)
+	}
+}
+
+func TestRevisionBlobs(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	present := git.FileRevision{Commit: "c1", Path: "a.go"}
+	absent := git.FileRevision{Commit: "c2", Path: "a.go"}
+	if err := s.InsertRevisionBlobs(map[git.FileRevision]string{present: "b1", absent: ""}); err != nil {
+		t.Fatalf("InsertRevisionBlobs: %v", err)
+	}
+
+	revs, err := s.UnresolvedRevisions([]git.FileRevision{present, absent, {Commit: "c3", Path: "a.go"}})
+	if err != nil {
+		t.Fatalf("UnresolvedRevisions: %v", err)
+	}
+	if len(revs) != 1 || revs[0].Commit != "c3" {
+		t.Errorf("expected only c3 unresolved, got %+v", revs)
+	}
+}
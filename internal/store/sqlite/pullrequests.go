@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"git-analytics/internal/hosting"
+)
+
+func (s *sqliteStore) ImportPullRequests(prs []hosting.PullRequest) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, pr := range prs {
+		for _, table := range []string{"pr_reviewers", "pr_reviews", "pr_commits"} {
+			if _, err := tx.Exec(`DELETE FROM `+table+` WHERE pr_number = ?`, pr.Number); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(
+			`INSERT OR REPLACE INTO pull_requests
+			   (number, title, author, state, created_at, updated_at, merged_at, closed_at, merge_commit)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			pr.Number, pr.Title, pr.Author, pr.State, pr.CreatedAt.Unix(), unixOrNull(pr.UpdatedAt),
+			unixOrNull(pr.MergedAt), unixOrNull(pr.ClosedAt), pr.MergeCommit,
+		); err != nil {
+			return err
+		}
+		for _, reviewer := range pr.Reviewers {
+			if _, err := tx.Exec(
+				`INSERT OR IGNORE INTO pr_reviewers (pr_number, reviewer) VALUES (?, ?)`,
+				pr.Number, reviewer,
+			); err != nil {
+				return err
+			}
+		}
+		for _, r := range pr.Reviews {
+			if _, err := tx.Exec(
+				`INSERT INTO pr_reviews (pr_number, reviewer, state, submitted_at) VALUES (?, ?, ?, ?)`,
+				pr.Number, r.Reviewer, r.State, unixOrNull(r.SubmittedAt),
+			); err != nil {
+				return err
+			}
+		}
+		commits := pr.Commits
+		if pr.MergeCommit != "" {
+			commits = append(commits[:len(commits):len(commits)], pr.MergeCommit)
+		}
+		for _, hash := range commits {
+			if _, err := tx.Exec(
+				`INSERT OR IGNORE INTO pr_commits (pr_number, commit_hash) VALUES (?, ?)`,
+				pr.Number, hash,
+			); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteStore) GetLastPullRequestUpdate() (time.Time, error) {
+	var updatedAt sql.NullInt64
+	if err := s.db.QueryRow(`SELECT MAX(updated_at) FROM pull_requests`).Scan(&updatedAt); err != nil {
+		return time.Time{}, err
+	}
+	if !updatedAt.Valid {
+		return time.Time{}, nil
+	}
+	return time.Unix(updatedAt.Int64, 0).UTC(), nil
+}
@@ -0,0 +1,72 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-analytics/internal/hosting"
+	sqlitestore "git-analytics/internal/store/sqlite"
+)
+
+func TestImportPullRequests(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	if last, err := s.GetLastPullRequestUpdate(); err != nil || !last.IsZero() {
+		t.Fatalf("GetLastPullRequestUpdate: got (%v, %v), want zero time", last, err)
+	}
+
+	created := time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC)
+	pr := hosting.PullRequest{
+		Number: 12, Title: "Add login", Author: "alice", State: "open", CreatedAt: created, UpdatedAt: created,
+		Commits:   []string{"c1", "c2"},
+		Reviewers: []string{"bob"},
+		Reviews:   []hosting.Review{{Reviewer: "bob", State: "commented", SubmittedAt: created.Add(time.Hour)}},
+	}
+	if err := s.ImportPullRequests([]hosting.PullRequest{pr}); err != nil {
+		t.Fatalf("ImportPullRequests: %v", err)
+	}
+
+	// Importing again replaces the pull request instead of duplicating it.
+	pr.State = "merged"
+	pr.MergedAt = created.Add(24 * time.Hour)
+	pr.MergeCommit = "m12"
+	pr.UpdatedAt = pr.MergedAt
+	pr.Reviews = append(pr.Reviews, hosting.Review{Reviewer: "bob", State: "approved"})
+	if err := s.ImportPullRequests([]hosting.PullRequest{pr}); err != nil {
+		t.Fatalf("ImportPullRequests (again): %v", err)
+	}
+
+	var state string
+	var mergedAt sql.NullInt64
+	if err := db.QueryRow(`SELECT state, merged_at FROM pull_requests WHERE number = 12`).Scan(&state, &mergedAt); err != nil {
+		t.Fatalf("query pull_requests: %v", err)
+	}
+	if state != "merged" || mergedAt.Int64 != pr.MergedAt.Unix() {
+		t.Errorf("got (%q, %v), want (merged, %d)", state, mergedAt, pr.MergedAt.Unix())
+	}
+
+	if last, err := s.GetLastPullRequestUpdate(); err != nil || !last.Equal(pr.UpdatedAt) {
+		t.Errorf("GetLastPullRequestUpdate: got (%v, %v), want %v", last, err, pr.UpdatedAt)
+	}
+
+	counts := map[string]int{"pr_reviewers": 1, "pr_reviews": 2, "pr_commits": 3}
+	for table, want := range counts {
+		var got int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&got); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		if got != want {
+			t.Errorf("%s: got %d rows, want %d", table, got, want)
+		}
+	}
+}
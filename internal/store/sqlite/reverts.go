@@ -0,0 +1,175 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"git-analytics/internal/classify"
+)
+
+// revertCheckedKey is the index_state key holding the highest commits id
+// checked for reverts. Its name predates the id column, which kept the
+// values of the rowids it replaced.
+const revertCheckedKey = "revert_checked_rowid"
+
+// Revert detection methods stored in commits.revert_method.
+const (
+	revertByMessage = "message"
+	revertByInverse = "inverse"
+)
+
+// inverseRevertsSQL finds unchecked commits that exactly undo an earlier
+// commit: both change the same number of files, and each file of the revert
+// goes from the blob the original left to the blob it started from. Changes
+// that leave the blob as it was, such as pure renames, never match. When
+// several earlier commits match, the most recent one is taken.
+const inverseRevertsSQL = `
+WITH matches AS (
+    SELECT fc.commit_hash AS revert, fp.commit_hash AS original,
+           COUNT(DISTINCT fc.file_path) AS matched, COUNT(DISTINCT fp.file_path) AS matched_original
+    FROM commits c
+    JOIN file_stats fc ON fc.commit_hash = c.hash
+    JOIN file_stats fp ON fp.new_blob = fc.old_blob AND fp.old_blob = fc.new_blob
+         AND fp.old_blob != fp.new_blob
+    JOIN commits p ON p.hash = fp.commit_hash
+         AND p.hash != c.hash AND p.committed_at <= c.committed_at
+    WHERE c.id > ? AND c.reverts = ''
+    GROUP BY fc.commit_hash, fp.commit_hash
+)
+SELECT m.revert, m.original, MAX(p.committed_at)
+FROM matches m
+JOIN commits p ON p.hash = m.original
+WHERE m.matched = (SELECT COUNT(*) FROM file_stats WHERE commit_hash = m.revert)
+  AND m.matched_original = (SELECT COUNT(*) FROM file_stats WHERE commit_hash = m.original)
+GROUP BY m.revert`
+
+// forgetInverseReverts clears the reverts detected by inverseRevertsSQL and
+// makes the next DetectReverts check every commit again.
+func (s *sqliteStore) forgetInverseReverts() error {
+	if _, err := s.db.Exec(
+		`UPDATE commits SET reverts = '', revert_method = '' WHERE revert_method = ?`, revertByInverse,
+	); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`DELETE FROM index_state WHERE key = ?`, revertCheckedKey)
+	return err
+}
+
+// expandHash returns the full hash of the stored commit that prefix
+// abbreviates, or "" if no commit or more than one matches.
+func expandHash(tx *sql.Tx, prefix string) (string, error) {
+	prefix = strings.ToLower(prefix)
+	// A range over the hash, so that the lookup uses its unique index.
+	rows, err := tx.Query(`SELECT hash FROM commits WHERE hash >= ? AND hash < ? LIMIT 2`, prefix, prefix+"\x7f")
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	var matches []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return "", err
+		}
+		matches = append(matches, hash)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	if len(matches) != 1 {
+		return "", nil
+	}
+	return matches[0], nil
+}
+
+func (s *sqliteStore) DetectReverts() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var value string
+	err = tx.QueryRow(`SELECT value FROM index_state WHERE key = ?`, revertCheckedKey).Scan(&value)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	checked, _ := strconv.ParseInt(value, 10, 64)
+
+	rows, err := tx.Query(
+		`SELECT id, hash, message, description FROM commits WHERE id > ? ORDER BY id`, checked)
+	if err != nil {
+		return err
+	}
+	type revert struct{ hash, original string }
+	var named []revert
+	last := checked
+	for rows.Next() {
+		var hash, message, description string
+		if err := rows.Scan(&last, &hash, &message, &description); err != nil {
+			rows.Close()
+			return err
+		}
+		if ref := classify.RevertedCommit(message, description); ref != "" {
+			named = append(named, revert{hash, ref})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, r := range named {
+		// Expand abbreviated hashes; keep the reference as written if the
+		// commit is not in the database or the prefix is ambiguous.
+		full, err := expandHash(tx, r.original)
+		if err != nil {
+			return err
+		}
+		if full != "" {
+			r.original = full
+		}
+		if _, err := tx.Exec(
+			`UPDATE commits SET reverts = ?, revert_method = ? WHERE hash = ?`,
+			r.original, revertByMessage, r.hash,
+		); err != nil {
+			return err
+		}
+	}
+
+	rows, err = tx.Query(inverseRevertsSQL, checked)
+	if err != nil {
+		return err
+	}
+	var inverses []revert
+	for rows.Next() {
+		var r revert
+		var committedAt int64
+		if err := rows.Scan(&r.hash, &r.original, &committedAt); err != nil {
+			rows.Close()
+			return err
+		}
+		inverses = append(inverses, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, r := range inverses {
+		if _, err := tx.Exec(
+			`UPDATE commits SET reverts = ?, revert_method = ? WHERE hash = ?`,
+			r.original, revertByInverse, r.hash,
+		); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(
+		`INSERT OR REPLACE INTO index_state (key, value) VALUES (?, ?)`,
+		revertCheckedKey, strconv.FormatInt(last, 10),
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,90 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-analytics/internal/git"
+	sqlitestore "git-analytics/internal/store/sqlite"
+)
+
+func TestDetectReverts(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	at := func(day int) time.Time { return time.Date(2025, 1, day, 10, 0, 0, 0, time.UTC) }
+	commit := func(hash string, day int, message, description string, files ...git.FileStat) git.Commit {
+		return git.Commit{Hash: hash, AuthorName: "A", AuthorEmail: "a@example.com", Date: at(day),
+			Message: message, Description: description, FilesChanged: files}
+	}
+	change := func(path, oldBlob, newBlob string, additions, deletions int) git.FileStat {
+		return git.FileStat{Path: path, OldBlob: oldBlob, NewBlob: newBlob, Additions: additions, Deletions: deletions}
+	}
+	// Inserted newest first, as the indexer does.
+	if err := s.InsertCommits([]git.Commit{
+		commit("fff6", 6, `Revert "Tweak"`, "This reverts commit abcdef0.",
+			change("c.go", "c2", "c3", 1, 1)),
+		commit("abcdef02", 5, "Tweak", "", change("c.go", "c1", "c2", 1, 1)),
+		commit("abcdef01", 5, "Tweak again", "", change("d.go", "d1", "d2", 1, 1)),
+		commit("ddd4", 4, "Mirror the cache change", "", change("cache.go", "k3", "k4", 2, 40)),
+		commit("ccc3", 3, "Undo the cache", "", change("cache.go", "k2", "k1", 2, 40)),
+		commit("bbb2", 2, `Revert "Add login"`, "This reverts commit aaa1111.", change("login.go", "l1", "", 0, 10)),
+		commit("eee5", 2, "Add cache", "", change("cache.go", "k1", "k2", 40, 2)),
+		commit("aaa1111bcd", 1, "Add login", "", change("login.go", "", "l1", 10, 0),
+			change("b.go", "b1", "b2", 3, 3)),
+	}); err != nil {
+		t.Fatalf("InsertCommits: %v", err)
+	}
+	if err := s.DetectReverts(); err != nil {
+		t.Fatalf("DetectReverts: %v", err)
+	}
+
+	want := map[string][2]string{
+		"aaa1111bcd": {"", ""},
+		"bbb2":       {"aaa1111bcd", "message"},
+		"ccc3":       {"eee5", "inverse"},
+		"ddd4":       {"", ""}, // mirrored line counts, but not the same content
+		"eee5":       {"", ""},
+		"abcdef01":   {"", ""},
+		"abcdef02":   {"", ""},
+		"fff6":       {"abcdef0", "message"}, // ambiguous prefix, kept as written
+	}
+	rows, err := db.Query(`SELECT hash, reverts, revert_method FROM commits`)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var hash, reverts, method string
+		if err := rows.Scan(&hash, &reverts, &method); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		if got := [2]string{reverts, method}; got != want[hash] {
+			t.Errorf("%s: got %v, want %v", hash, got, want[hash])
+		}
+	}
+
+	// Already checked commits are not revisited.
+	if _, err := db.Exec(`UPDATE commits SET reverts = '', revert_method = '' WHERE hash = 'bbb2'`); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := s.DetectReverts(); err != nil {
+		t.Fatalf("DetectReverts (again): %v", err)
+	}
+	var reverts string
+	if err := db.QueryRow(`SELECT reverts FROM commits WHERE hash = 'bbb2'`).Scan(&reverts); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if reverts != "" {
+		t.Errorf("got reverts %q after second run, want unchanged empty", reverts)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	_ "modernc.org/sqlite"
 
+	"git-analytics/internal/classify"
 	"git-analytics/internal/git"
 	"git-analytics/internal/store"
 )
@@ -36,15 +37,242 @@ func NewFromDB(db *sql.DB) store.Store {
 }
 
 func (s *sqliteStore) Init() error {
+	var hasFTS bool
+	if err := s.db.QueryRow(
+		`SELECT COUNT(*) > 0 FROM sqlite_master WHERE name = 'commits_fts'`).Scan(&hasFTS); err != nil {
+		return err
+	}
 	if _, err := s.db.Exec(store.SchemaSQL); err != nil {
 		return err
 	}
 	// Migrate existing databases: adds the description column if absent.
 	// SQLite returns an error when the column already exists; we ignore it.
 	_, _ = s.db.Exec(`ALTER TABLE commits ADD COLUMN description TEXT NOT NULL DEFAULT ''`)
-	return nil
+	// Migrate existing databases: adds the tz_offset column and, when it was
+	// just added, backfills it from the offset embedded in committed_at.
+	if _, err := s.db.Exec(`ALTER TABLE commits ADD COLUMN tz_offset INTEGER NOT NULL DEFAULT 0`); err == nil {
+		if _, err := s.db.Exec(backfillTZOffsetSQL); err != nil {
+			return err
+		}
+	}
+	// Migrate existing databases: converts committed_at from the driver's
+	// string serialization to UTC epoch seconds. Only text rows are touched,
+	// so this is a no-op once the conversion has run.
+	if _, err := s.db.Exec(convertCommittedAtSQL); err != nil {
+		return err
+	}
+	// Migrate existing databases: adds the old_path column and, when it was
+	// just added, splits rename paths that were stored verbatim.
+	if _, err := s.db.Exec(`ALTER TABLE file_stats ADD COLUMN old_path VARCHAR NOT NULL DEFAULT ''`); err == nil {
+		if err := s.splitStoredRenames(); err != nil {
+			return err
+		}
+	}
+	// Migrate existing databases: adds the change classification columns and,
+	// when they were just added, classifies the stored commit messages.
+	if _, err := s.db.Exec(`ALTER TABLE commits ADD COLUMN change_type VARCHAR NOT NULL DEFAULT 'other'`); err == nil {
+		_, _ = s.db.Exec(`ALTER TABLE commits ADD COLUMN change_scope VARCHAR NOT NULL DEFAULT ''`)
+		_, _ = s.db.Exec(`ALTER TABLE commits ADD COLUMN breaking BOOLEAN NOT NULL DEFAULT 0`)
+		if err := s.classifyStoredCommits(); err != nil {
+			return err
+		}
+	}
+	// Migrate existing databases: adds the revert columns. Commits are
+	// checked for reverts by DetectReverts, which starts from scratch on
+	// databases that have never run it.
+	_, _ = s.db.Exec(`ALTER TABLE commits ADD COLUMN reverts VARCHAR NOT NULL DEFAULT ''`)
+	_, _ = s.db.Exec(`ALTER TABLE commits ADD COLUMN revert_method VARCHAR NOT NULL DEFAULT ''`)
+	// Migrate existing databases: rebuilds commits with an id column when it
+	// has none, as the full-text index and the issue and revert watermarks
+	// would otherwise refer to rowids that VACUUM may renumber.
+	var hasID bool
+	if err := s.db.QueryRow(
+		`SELECT COUNT(*) > 0 FROM pragma_table_info('commits') WHERE name = 'id'`).Scan(&hasID); err != nil {
+		return err
+	}
+	if !hasID {
+		if err := s.addCommitIDs(); err != nil {
+			return err
+		}
+	}
+	// Migrate existing databases: adds the file status, binary file and blob
+	// hash columns. When any of them was just added, the last indexed commit
+	// is forgotten so that the next index run walks the whole history once
+	// and fills them all in.
+	rewalk := false
+	if _, err := s.db.Exec(`ALTER TABLE file_stats ADD COLUMN status VARCHAR NOT NULL DEFAULT 'M'`); err == nil {
+		if _, err := s.db.Exec(`UPDATE file_stats SET status = 'R' WHERE old_path != ''`); err != nil {
+			return err
+		}
+		rewalk = true
+	}
+	for _, col := range []string{
+		`binary BOOLEAN NOT NULL DEFAULT 0`,
+		`size_delta INTEGER NOT NULL DEFAULT 0`,
+		`new_blob VARCHAR NOT NULL DEFAULT ''`,
+	} {
+		if _, err := s.db.Exec(`ALTER TABLE file_stats ADD COLUMN ` + col); err == nil {
+			rewalk = true
+		}
+	}
+	// Inverse revert detection compares blob hashes, so commits checked
+	// before old_blob existed are checked again.
+	if _, err := s.db.Exec(`ALTER TABLE file_stats ADD COLUMN old_blob VARCHAR NOT NULL DEFAULT ''`); err == nil {
+		if err := s.forgetInverseReverts(); err != nil {
+			return err
+		}
+		rewalk = true
+	}
+	if rewalk {
+		if err := s.forgetLastIndexedCommit(); err != nil {
+			return err
+		}
+	}
+	// Migrate existing databases: commits indexed before the full-text index
+	// existed are added to it once, when it is first created.
+	if !hasFTS {
+		if _, err := s.db.Exec(`INSERT INTO commits_fts (commits_fts) VALUES ('rebuild')`); err != nil {
+			return err
+		}
+	}
+	_, err := s.db.Exec(store.IndexSQL)
+	return err
+}
+
+// addCommitIDs recreates the commits table with the current schema, giving
+// every commit its rowid as id so that the full-text index and the
+// watermarks stored in index_state stay valid. The full-text index is
+// recreated too and filled in by its insert trigger.
+func (s *sqliteStore) addCommitIDs() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range []string{
+		`DROP TRIGGER IF EXISTS commits_fts_insert`,
+		`DROP TRIGGER IF EXISTS commits_fts_delete`,
+		`DROP TRIGGER IF EXISTS commits_fts_update`,
+		`DROP TABLE IF EXISTS commits_fts`,
+		`ALTER TABLE commits RENAME TO commits_old`,
+		store.SchemaSQL,
+		`INSERT INTO commits (id, hash, author_name, author_email, committed_at, tz_offset, message, description,
+		                      change_type, change_scope, breaking, reverts, revert_method)
+		 SELECT rowid, hash, author_name, author_email, committed_at, tz_offset, message, description,
+		        change_type, change_scope, breaking, reverts, revert_method
+		 FROM commits_old`,
+		`DROP TABLE commits_old`,
+	} {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
+// splitStoredRenames rewrites file_stats rows whose file_path still holds a
+// diffstat rename ("old => new" or "dir/{old => new}") into separate
+// file_path and old_path values.
+func (s *sqliteStore) splitStoredRenames() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT commit_hash, file_path FROM file_stats WHERE file_path LIKE '% => %'`)
+	if err != nil {
+		return err
+	}
+	type renameRow struct{ hash, path string }
+	var renames []renameRow
+	for rows.Next() {
+		var r renameRow
+		if err := rows.Scan(&r.hash, &r.path); err != nil {
+			rows.Close()
+			return err
+		}
+		renames = append(renames, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, r := range renames {
+		oldPath, newPath := git.SplitRenamePath(r.path)
+		if _, err := tx.Exec(
+			`UPDATE OR IGNORE file_stats SET file_path = ?, old_path = ? WHERE commit_hash = ? AND file_path = ?`,
+			newPath, oldPath, r.hash, r.path,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// classifyStoredCommits sets the change classification of every stored commit
+// from its message.
+func (s *sqliteStore) classifyStoredCommits() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT hash, message, description FROM commits`)
+	if err != nil {
+		return err
+	}
+	type classifiedRow struct {
+		hash string
+		c    classify.Classification
+	}
+	var classified []classifiedRow
+	for rows.Next() {
+		var hash, message, description string
+		if err := rows.Scan(&hash, &message, &description); err != nil {
+			rows.Close()
+			return err
+		}
+		classified = append(classified, classifiedRow{hash, classify.Classify(message, description)})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, r := range classified {
+		if _, err := tx.Exec(
+			`UPDATE commits SET change_type = ?, change_scope = ?, breaking = ? WHERE hash = ?`,
+			r.c.Type, r.c.Scope, r.c.Breaking, r.hash,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// backfillTZOffsetSQL derives tz_offset for rows written before the column
+// existed. Those timestamps were serialized via time.Time.String(), e.g.
+// "2025-01-15 10:00:00 +0900 +0900", so the numeric offset starts at
+// character 21. UTC values were written as RFC 3339 ("...Z") and stay 0.
+const backfillTZOffsetSQL = `
+UPDATE commits
+SET tz_offset = (CASE SUBSTR(committed_at, 21, 1) WHEN '-' THEN -1 ELSE 1 END) *
+                (CAST(SUBSTR(committed_at, 22, 2) AS INTEGER) * 60 +
+                 CAST(SUBSTR(committed_at, 24, 2) AS INTEGER))
+WHERE committed_at GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9] [0-9][0-9]:[0-9][0-9]:[0-9][0-9] [+-][0-9][0-9][0-9][0-9]*'`
+
+// convertCommittedAtSQL rewrites text timestamps as UTC epoch seconds. The
+// first 19 characters hold the author's wall-clock time in both serialized
+// forms, so subtracting the (already backfilled) offset yields UTC.
+const convertCommittedAtSQL = `
+UPDATE commits
+SET committed_at = CAST(strftime('%s', SUBSTR(committed_at, 1, 19)) AS INTEGER) - tz_offset * 60
+WHERE typeof(committed_at) = 'text'`
+
 func (s *sqliteStore) InsertCommits(commits []git.Commit) error {
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -53,28 +281,41 @@ func (s *sqliteStore) InsertCommits(commits []git.Commit) error {
 	defer tx.Rollback()
 
 	commitStmt, err := tx.Prepare(
-		`INSERT OR IGNORE INTO commits (hash, author_name, author_email, committed_at, message, description)
-		 VALUES (?, ?, ?, ?, ?, ?)`)
+		`INSERT OR IGNORE INTO commits (hash, author_name, author_email, committed_at, tz_offset, message, description,
+		                                change_type, change_scope, breaking)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 	if err != nil {
 		return err
 	}
 	defer commitStmt.Close()
 
 	fileStmt, err := tx.Prepare(
-		`INSERT OR IGNORE INTO file_stats (commit_hash, file_path, additions, deletions)
-		 VALUES (?, ?, ?, ?)`)
+		`INSERT INTO file_stats (commit_hash, file_path, old_path, status, additions, deletions, binary, size_delta,
+		                         old_blob, new_blob)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (commit_hash, file_path) DO UPDATE
+		 SET status = excluded.status, binary = excluded.binary, size_delta = excluded.size_delta,
+		     old_blob = excluded.old_blob, new_blob = excluded.new_blob`)
 	if err != nil {
 		return err
 	}
 	defer fileStmt.Close()
 
 	for _, c := range commits {
-		_, err := commitStmt.Exec(c.Hash, c.AuthorName, c.AuthorEmail, c.Date, c.Message, c.Description)
+		_, offset := c.Date.Zone()
+		class := classify.Classify(c.Message, c.Description)
+		_, err := commitStmt.Exec(c.Hash, c.AuthorName, c.AuthorEmail, c.Date.Unix(), offset/60, c.Message, c.Description,
+			class.Type, class.Scope, class.Breaking)
 		if err != nil {
 			return err
 		}
 		for _, f := range c.FilesChanged {
-			_, err := fileStmt.Exec(c.Hash, f.Path, f.Additions, f.Deletions)
+			status := f.Status
+			if status == "" {
+				status = git.StatusModified
+			}
+			_, err := fileStmt.Exec(c.Hash, f.Path, f.OldPath, status, f.Additions, f.Deletions, f.Binary, f.SizeDelta,
+				f.OldBlob, f.NewBlob)
 			if err != nil {
 				return err
 			}
@@ -94,6 +335,13 @@ func (s *sqliteStore) GetLastIndexedCommit() (string, error) {
 	return hash, err
 }
 
+// forgetLastIndexedCommit makes the next index run walk the whole history,
+// so that migrations can fill in data for commits already stored.
+func (s *sqliteStore) forgetLastIndexedCommit() error {
+	_, err := s.db.Exec(`DELETE FROM index_state WHERE key = 'last_indexed_commit'`)
+	return err
+}
+
 func (s *sqliteStore) SetLastIndexedCommit(hash string) error {
 	_, err := s.db.Exec(
 		`INSERT OR REPLACE INTO index_state (key, value)
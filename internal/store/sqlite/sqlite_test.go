@@ -1,6 +1,7 @@
 package sqlite_test
 
 import (
+	"database/sql"
 	"path/filepath"
 	"testing"
 	"time"
@@ -122,3 +123,473 @@ func TestInitIdempotent(t *testing.T) {
 		t.Fatalf("Init (second): %v", err)
 	}
 }
+
+func TestInsertStoresEpochAndOffset(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	s, err := sqlitestore.Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	commits := []git.Commit{{
+		Hash:        "abc123def456abc123def456abc123def456abc1",
+		AuthorName:  "Alice",
+		AuthorEmail: "alice@example.com",
+		Date:        time.Date(2025, 1, 15, 10, 30, 0, 0, time.FixedZone("", -(5*60+30)*60)),
+		Message:     "initial commit",
+	}}
+	if err := s.InsertCommits(commits); err != nil {
+		t.Fatalf("InsertCommits: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	var committedAt int64
+	var offset int
+	if err := db.QueryRow(`SELECT committed_at, tz_offset FROM commits`).Scan(&committedAt, &offset); err != nil {
+		t.Fatalf("query commit: %v", err)
+	}
+	if committedAt != commits[0].Date.Unix() {
+		t.Errorf("expected committed_at %d, got %d", commits[0].Date.Unix(), committedAt)
+	}
+	if offset != -330 {
+		t.Errorf("expected tz_offset -330, got %d", offset)
+	}
+}
+
+func TestInitMigratesLegacyTimestamps(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// Simulate a database created before tz_offset existed.
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE commits (
+		hash         VARCHAR PRIMARY KEY,
+		author_name  VARCHAR NOT NULL,
+		author_email VARCHAR NOT NULL,
+		committed_at TIMESTAMP NOT NULL,
+		message      VARCHAR NOT NULL
+	)`); err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+	legacy := []struct {
+		hash string
+		at   time.Time
+	}{
+		{"aaa1", time.Date(2025, 1, 15, 10, 0, 0, 0, time.FixedZone("", 9*60*60))},
+		{"bbb1", time.Date(2025, 1, 15, 10, 0, 0, 0, time.FixedZone("", -7*60*60))},
+		{"ccc1", time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range legacy {
+		if _, err := db.Exec(
+			`INSERT INTO commits (hash, author_name, author_email, committed_at, message) VALUES (?, 'A', 'a@example.com', ?, 'msg')`,
+			c.hash, c.at,
+		); err != nil {
+			t.Fatalf("insert legacy commit: %v", err)
+		}
+	}
+
+	if err := sqlitestore.NewFromDB(db).Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	want := map[string]int{"aaa1": 540, "bbb1": -420, "ccc1": 0}
+	for hash, offset := range want {
+		var got int
+		if err := db.QueryRow(`SELECT tz_offset FROM commits WHERE hash = ?`, hash).Scan(&got); err != nil {
+			t.Fatalf("query tz_offset for %s: %v", hash, err)
+		}
+		if got != offset {
+			t.Errorf("%s: expected tz_offset %d, got %d", hash, offset, got)
+		}
+	}
+
+	// committed_at is converted to UTC epoch seconds.
+	for _, c := range legacy {
+		var got int64
+		if err := db.QueryRow(`SELECT committed_at FROM commits WHERE hash = ?`, c.hash).Scan(&got); err != nil {
+			t.Fatalf("query committed_at for %s: %v", c.hash, err)
+		}
+		if got != c.at.Unix() {
+			t.Errorf("%s: expected committed_at %d, got %d", c.hash, c.at.Unix(), got)
+		}
+	}
+
+	// Running Init again leaves converted rows untouched.
+	if err := sqlitestore.NewFromDB(db).Init(); err != nil {
+		t.Fatalf("Init (second): %v", err)
+	}
+	var got int64
+	if err := db.QueryRow(`SELECT committed_at FROM commits WHERE hash = 'aaa1'`).Scan(&got); err != nil {
+		t.Fatalf("query committed_at: %v", err)
+	}
+	if got != legacy[0].at.Unix() {
+		t.Errorf("aaa1 after second Init: expected %d, got %d", legacy[0].at.Unix(), got)
+	}
+}
+
+func TestInitSplitsLegacyRenamePaths(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// Simulate a database created before old_path existed, where renames
+	// were stored with git's diffstat notation.
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE file_stats (
+		commit_hash VARCHAR NOT NULL,
+		file_path   VARCHAR NOT NULL,
+		additions   INTEGER NOT NULL,
+		deletions   INTEGER NOT NULL,
+		PRIMARY KEY (commit_hash, file_path)
+	)`); err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+	for _, path := range []string{"src/{old.go => new.go}", "a.txt => b.txt", "main.go"} {
+		if _, err := db.Exec(`INSERT INTO file_stats VALUES ('aaa1', ?, 1, 1)`, path); err != nil {
+			t.Fatalf("insert legacy file_stat: %v", err)
+		}
+	}
+
+	if err := sqlitestore.NewFromDB(db).Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	want := map[string]string{"src/new.go": "src/old.go", "b.txt": "a.txt", "main.go": ""}
+	rows, err := db.Query(`SELECT file_path, old_path FROM file_stats`)
+	if err != nil {
+		t.Fatalf("query file_stats: %v", err)
+	}
+	defer rows.Close()
+	got := map[string]string{}
+	for rows.Next() {
+		var path, oldPath string
+		if err := rows.Scan(&path, &oldPath); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		got[path] = oldPath
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d rows, got %v", len(want), got)
+	}
+	for path, oldPath := range want {
+		if got[path] != oldPath {
+			t.Errorf("%s: expected old_path %q, got %q", path, oldPath, got[path])
+		}
+	}
+}
+
+func TestFullTextIndex(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// Simulate a database created before the full-text index existed.
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE commits (
+		hash         VARCHAR PRIMARY KEY,
+		author_name  VARCHAR NOT NULL,
+		author_email VARCHAR NOT NULL,
+		committed_at INTEGER NOT NULL,
+		tz_offset    INTEGER NOT NULL DEFAULT 0,
+		message      VARCHAR NOT NULL,
+		description  TEXT NOT NULL DEFAULT ''
+	)`); err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+	if _, err := db.Exec(
+		`INSERT INTO commits VALUES ('aaa1', 'A', 'a@example.com', 0, 0, 'legacy subject', 'about tokens')`,
+	); err != nil {
+		t.Fatalf("insert legacy commit: %v", err)
+	}
+
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	commits := []git.Commit{{
+		Hash:        "bbb1",
+		AuthorName:  "B",
+		AuthorEmail: "b@example.com",
+		Date:        time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+		Message:     "refresh tokens",
+	}}
+	if err := s.InsertCommits(commits); err != nil {
+		t.Fatalf("InsertCommits: %v", err)
+	}
+	// Re-inserting must not duplicate index entries.
+	if err := s.InsertCommits(commits); err != nil {
+		t.Fatalf("InsertCommits (duplicate): %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM commits_fts WHERE commits_fts MATCH 'token'`).Scan(&count); err != nil {
+		t.Fatalf("MATCH: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 matches (legacy + new), got %d", count)
+	}
+}
+
+func TestFullTextIndexFollowsCommitIDs(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	// Simulate a database created before commits had an id, with a gap in
+	// its rowids.
+	if _, err := db.Exec(`CREATE TABLE commits (
+		hash         VARCHAR PRIMARY KEY,
+		author_name  VARCHAR NOT NULL,
+		author_email VARCHAR NOT NULL,
+		committed_at INTEGER NOT NULL,
+		tz_offset    INTEGER NOT NULL DEFAULT 0,
+		message      VARCHAR NOT NULL,
+		description  TEXT NOT NULL DEFAULT ''
+	)`); err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO commits VALUES
+		('aaa1', 'A', 'a@example.com', 0, 0, 'first apple', ''),
+		('aaa2', 'A', 'a@example.com', 0, 0, 'second banana', ''),
+		('aaa3', 'A', 'a@example.com', 0, 0, 'third cherry', '')`); err != nil {
+		t.Fatalf("insert legacy commits: %v", err)
+	}
+	if _, err := db.Exec(`DELETE FROM commits WHERE hash = 'aaa2'`); err != nil {
+		t.Fatalf("delete legacy commit: %v", err)
+	}
+
+	if err := sqlitestore.NewFromDB(db).Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	var id int
+	if err := db.QueryRow(`SELECT id FROM commits WHERE hash = 'aaa3'`).Scan(&id); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if id != 3 {
+		t.Errorf("got id %d, want the former rowid 3", id)
+	}
+
+	// VACUUM keeps ids, and the index follows updates and deletions.
+	for _, stmt := range []string{
+		`VACUUM`,
+		`UPDATE commits SET message = 'first date' WHERE hash = 'aaa1'`,
+		`DELETE FROM commits WHERE hash = 'aaa3'`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	match := func(term string) []string {
+		t.Helper()
+		rows, err := db.Query(
+			`SELECT c.hash FROM commits_fts JOIN commits c ON c.id = commits_fts.rowid WHERE commits_fts MATCH ?`, term)
+		if err != nil {
+			t.Fatalf("MATCH %s: %v", term, err)
+		}
+		defer rows.Close()
+		var hashes []string
+		for rows.Next() {
+			var h string
+			if err := rows.Scan(&h); err != nil {
+				t.Fatalf("scan: %v", err)
+			}
+			hashes = append(hashes, h)
+		}
+		return hashes
+	}
+	if got := match("date"); len(got) != 1 || got[0] != "aaa1" {
+		t.Errorf("date: got %v, want aaa1", got)
+	}
+	for _, term := range []string{"apple", "cherry", "banana"} {
+		if got := match(term); len(got) != 0 {
+			t.Errorf("%s: got %v, want no match", term, got)
+		}
+	}
+	// The index holds no stale entries.
+	if _, err := db.Exec(`INSERT INTO commits_fts (commits_fts) VALUES ('integrity-check')`); err != nil {
+		t.Errorf("integrity-check: %v", err)
+	}
+}
+
+func TestInsertClassifiesCommits(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	date := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	if err := s.InsertCommits([]git.Commit{
+		{Hash: "aaa1", AuthorName: "Alice", AuthorEmail: "alice@example.com", Date: date, Message: "feat(api)!: drop v1"},
+		{Hash: "aaa2", AuthorName: "Alice", AuthorEmail: "alice@example.com", Date: date, Message: "Fix crash on startup"},
+	}); err != nil {
+		t.Fatalf("InsertCommits: %v", err)
+	}
+
+	var changeType, scope string
+	var breaking bool
+	if err := db.QueryRow(`SELECT change_type, change_scope, breaking FROM commits WHERE hash = 'aaa1'`).Scan(&changeType, &scope, &breaking); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if changeType != "feat" || scope != "api" || !breaking {
+		t.Errorf("got (%q, %q, %v), want (feat, api, true)", changeType, scope, breaking)
+	}
+	if err := db.QueryRow(`SELECT change_type FROM commits WHERE hash = 'aaa2'`).Scan(&changeType); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if changeType != "fix" {
+		t.Errorf("got %q, want fix", changeType)
+	}
+}
+
+func TestInitClassifiesLegacyCommits(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	// Simulate a database created before commits were classified.
+	if _, err := db.Exec(`CREATE TABLE commits (
+		hash         VARCHAR PRIMARY KEY,
+		author_name  VARCHAR NOT NULL,
+		author_email VARCHAR NOT NULL,
+		committed_at INTEGER NOT NULL,
+		tz_offset    INTEGER NOT NULL DEFAULT 0,
+		message      VARCHAR NOT NULL,
+		description  TEXT NOT NULL DEFAULT ''
+	)`); err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO commits VALUES ('aaa1', 'Alice', 'alice@example.com', 0, 0, 'docs: readme', '')`); err != nil {
+		t.Fatalf("insert legacy commit: %v", err)
+	}
+
+	if err := sqlitestore.NewFromDB(db).Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	var changeType string
+	if err := db.QueryRow(`SELECT change_type FROM commits WHERE hash = 'aaa1'`).Scan(&changeType); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if changeType != "docs" {
+		t.Errorf("got %q, want docs", changeType)
+	}
+}
+
+func TestInitBackfillsLegacyFileStatus(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// Simulate a database created before file statuses were recorded.
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE file_stats (
+		commit_hash VARCHAR NOT NULL,
+		file_path   VARCHAR NOT NULL,
+		old_path    VARCHAR NOT NULL DEFAULT '',
+		additions   INTEGER NOT NULL,
+		deletions   INTEGER NOT NULL,
+		PRIMARY KEY (commit_hash, file_path)
+	);
+	CREATE TABLE index_state (key VARCHAR PRIMARY KEY, value VARCHAR NOT NULL);
+	INSERT INTO index_state VALUES ('last_indexed_commit', 'aaa1');
+	INSERT INTO file_stats VALUES ('aaa1', 'new.go', 'old.go', 1, 1), ('aaa1', 'added.go', '', 5, 0)`); err != nil {
+		t.Fatalf("create legacy tables: %v", err)
+	}
+
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	// The last indexed commit is forgotten so that history is walked again.
+	last, err := s.GetLastIndexedCommit()
+	if err != nil {
+		t.Fatalf("GetLastIndexedCommit: %v", err)
+	}
+	if last != "" {
+		t.Errorf("expected last indexed commit to be reset, got %q", last)
+	}
+
+	// Walking history again fills in the statuses of stored rows.
+	if err := s.InsertCommits([]git.Commit{{
+		Hash: "aaa1", AuthorName: "A", AuthorEmail: "a@example.com", Date: time.Now(), Message: "msg",
+		FilesChanged: []git.FileStat{
+			{Path: "new.go", OldPath: "old.go", Status: git.StatusRenamed, Additions: 1, Deletions: 1},
+			{Path: "added.go", Status: git.StatusAdded, Additions: 5},
+		},
+	}}); err != nil {
+		t.Fatalf("InsertCommits: %v", err)
+	}
+
+	want := map[string]string{"new.go": "R", "added.go": "A"}
+	for path, status := range want {
+		var got string
+		if err := db.QueryRow(`SELECT status FROM file_stats WHERE file_path = ?`, path).Scan(&got); err != nil {
+			t.Fatalf("query status for %s: %v", path, err)
+		}
+		if got != status {
+			t.Errorf("%s: expected status %q, got %q", path, status, got)
+		}
+	}
+}
+
+func TestInsertRecordsBinaryFiles(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	if err := s.InsertCommits([]git.Commit{{
+		Hash: "aaa1", AuthorName: "A", AuthorEmail: "a@example.com", Date: time.Now(), Message: "msg",
+		FilesChanged: []git.FileStat{
+			{Path: "logo.png", Status: git.StatusModified, Binary: true, SizeDelta: -42},
+			{Path: "main.go", Status: git.StatusModified, Additions: 3},
+		},
+	}}); err != nil {
+		t.Fatalf("InsertCommits: %v", err)
+	}
+
+	var binary bool
+	var delta int64
+	if err := db.QueryRow(`SELECT binary, size_delta FROM file_stats WHERE file_path = 'logo.png'`).Scan(&binary, &delta); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if !binary || delta != -42 {
+		t.Errorf("got binary=%v size_delta=%d, want true, -42", binary, delta)
+	}
+}
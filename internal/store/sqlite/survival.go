@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"git-analytics/internal/git"
+)
+
+func (s *sqliteStore) FirstCommitTime() (time.Time, error) {
+	var first sql.NullInt64
+	if err := s.db.QueryRow(`SELECT MIN(committed_at) FROM commits`).Scan(&first); err != nil {
+		return time.Time{}, err
+	}
+	if !first.Valid {
+		return time.Time{}, nil
+	}
+	return time.Unix(first.Int64, 0).UTC(), nil
+}
+
+func (s *sqliteStore) SurvivalSamples() ([]string, error) {
+	rows, err := s.db.Query(`SELECT month FROM survival_samples ORDER BY month`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var months []string
+	for rows.Next() {
+		var m string
+		if err := rows.Scan(&m); err != nil {
+			return nil, err
+		}
+		months = append(months, m)
+	}
+	return months, rows.Err()
+}
+
+func (s *sqliteStore) InsertSurvivalSample(month, rev string, files []git.TreeFile) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM survival_lines WHERE month = ?`, month); err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(
+		`INSERT INTO survival_lines (month, path, commit_hash, lines)
+		 SELECT ?, path, commit_hash, lines
+		 FROM blame_entries
+		 WHERE path = ? AND blob_hash = ?`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, f := range files {
+		if _, err := stmt.Exec(month, f.Path, f.Blob); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(
+		`INSERT OR REPLACE INTO survival_samples (month, rev) VALUES (?, ?)`, month, rev,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
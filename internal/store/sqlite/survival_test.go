@@ -0,0 +1,94 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-analytics/internal/git"
+	sqlitestore "git-analytics/internal/store/sqlite"
+)
+
+func TestSurvivalSample(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	s := sqlitestore.NewFromDB(db)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	first, err := s.FirstCommitTime()
+	if err != nil {
+		t.Fatalf("FirstCommitTime: %v", err)
+	}
+	if !first.IsZero() {
+		t.Errorf("got first commit %v on an empty store, want zero", first)
+	}
+
+	if err := s.InsertCommits([]git.Commit{
+		{Hash: "aaa1", AuthorName: "A", AuthorEmail: "a@example.com", Date: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), Message: "a"},
+		{Hash: "aaa2", AuthorName: "A", AuthorEmail: "a@example.com", Date: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), Message: "b"},
+	}); err != nil {
+		t.Fatalf("InsertCommits: %v", err)
+	}
+	first, err = s.FirstCommitTime()
+	if err != nil {
+		t.Fatalf("FirstCommitTime: %v", err)
+	}
+	if want := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC); !first.Equal(want) {
+		t.Errorf("got first commit %v, want %v", first, want)
+	}
+
+	tokyo := time.FixedZone("", 9*60*60)
+	file := git.TreeFile{Path: "a.go", Blob: "b1"}
+	if err := s.InsertBlame(file, []git.BlameEntry{
+		{Commit: "aaa1", AuthorName: "A", AuthorEmail: "a@example.com", Date: time.Date(2025, 1, 10, 9, 0, 0, 0, tokyo), Lines: 5},
+		{Commit: "aaa3", AuthorName: "A", AuthorEmail: "a@example.com", Date: time.Date(2025, 2, 1, 8, 0, 0, 0, tokyo), Lines: 2},
+		{Commit: "aaa2", AuthorName: "B", AuthorEmail: "b@example.com", Date: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), Lines: 3},
+	}); err != nil {
+		t.Fatalf("InsertBlame: %v", err)
+	}
+	if err := s.InsertSurvivalSample("2025-03", "aaa2", []git.TreeFile{file}); err != nil {
+		t.Fatalf("InsertSurvivalSample: %v", err)
+	}
+	// Sampling a month again replaces its lines.
+	if err := s.InsertSurvivalSample("2025-03", "aaa2", []git.TreeFile{file}); err != nil {
+		t.Fatalf("InsertSurvivalSample: %v", err)
+	}
+
+	months, err := s.SurvivalSamples()
+	if err != nil {
+		t.Fatalf("SurvivalSamples: %v", err)
+	}
+	if len(months) != 1 || months[0] != "2025-03" {
+		t.Errorf("got samples %v, want [2025-03]", months)
+	}
+
+	got := map[string]int{}
+	rows, err := db.Query(`SELECT commit_hash, lines FROM survival_lines WHERE month = '2025-03'`)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key string
+		var lines int
+		if err := rows.Scan(&key, &lines); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		got[key] = lines
+	}
+	want := map[string]int{"aaa1": 5, "aaa3": 2, "aaa2": 3}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: got %d lines, want %d", k, got[k], v)
+		}
+	}
+}
@@ -1,6 +1,15 @@
 package store
 
-import "git-analytics/internal/git"
+import (
+	"time"
+
+	"git-analytics/internal/complexity"
+	"git-analytics/internal/git"
+	"git-analytics/internal/gosrc"
+	"git-analytics/internal/hosting"
+	"git-analytics/internal/issues"
+	"git-analytics/internal/linguist"
+)
 
 // Store persists extracted git analytics data.
 type Store interface {
@@ -13,5 +22,78 @@ type Store interface {
 	GetLastIndexedCommit() (string, error)
 	// SetLastIndexedCommit records the hash of the most recently indexed commit.
 	SetLastIndexedCommit(hash string) error
+	// InsertHunks inserts the hunks of a batch of commits.
+	InsertHunks(commits []git.CommitHunks) error
+	// GetLastHunkCommit returns the hash of the last commit whose hunks were
+	// extracted, or an empty string if none were.
+	GetLastHunkCommit() (string, error)
+	// SetLastHunkCommit records the hash of the most recent commit whose
+	// hunks were extracted.
+	SetLastHunkCommit(hash string) error
+	// SetHeadFiles replaces the recorded files of HEAD's tree.
+	SetHeadFiles(files []git.TreeFile) error
+	// UnblamedFiles returns the text files among files whose blobs have not
+	// been blamed yet.
+	UnblamedFiles(files []git.TreeFile) ([]git.TreeFile, error)
+	// InsertBlame caches the blame of file's blob.
+	InsertBlame(file git.TreeFile, entries []git.BlameEntry) error
+	// UnparsedFiles returns the files among files whose blobs have not been
+	// parsed for function declarations yet.
+	UnparsedFiles(files []git.TreeFile) ([]git.TreeFile, error)
+	// InsertFunctions caches the function declarations of file's blob, or
+	// parseErr if it does not parse.
+	InsertFunctions(file git.TreeFile, funcs []gosrc.Func, parseErr error) error
+	// UnmeasuredFiles returns the text files among files whose blobs have no
+	// cached metrics yet.
+	UnmeasuredFiles(files []git.TreeFile) ([]git.TreeFile, error)
+	// InsertMetrics caches the metrics of files' blobs.
+	InsertMetrics(metrics map[git.TreeFile]complexity.Metrics) error
+	// UnresolvedRevisions returns the revisions among revs whose blobs have
+	// not been recorded yet.
+	UnresolvedRevisions(revs []git.FileRevision) ([]git.FileRevision, error)
+	// InsertRevisionBlobs records the blob of each file revision, "" for
+	// revisions where the file does not exist.
+	InsertRevisionBlobs(blobs map[git.FileRevision]string) error
+	// FilePaths returns every path changed by an indexed commit or present
+	// at the indexed HEAD.
+	FilePaths() ([]string, error)
+	// SetFileLanguages replaces the stored languages of paths with langs,
+	// detected at commit rev.
+	SetFileLanguages(langs map[string]string, rev string) error
+	// GetLastLanguageCommit returns the commit at which languages were last
+	// detected, or "" if they never were.
+	GetLastLanguageCommit() (string, error)
+	// SetPathKinds replaces the stored kinds of paths with kinds,
+	// classified at commit rev.
+	SetPathKinds(kinds map[string]linguist.Kinds, rev string) error
+	// GetLastPathKindCommit returns the commit at which paths were last
+	// classified, or "" if they never were.
+	GetLastPathKindCommit() (string, error)
+	// FirstCommitTime returns the time of the oldest indexed commit, or the
+	// zero time if none are indexed.
+	FirstCommitTime() (time.Time, error)
+	// SurvivalSamples returns the months (YYYY-MM) already sampled for line
+	// survival.
+	SurvivalSamples() ([]string, error)
+	// InsertSurvivalSample records the lines alive at the end of month, as
+	// given by the cached blames of files, the tree of commit rev.
+	InsertSurvivalSample(month, rev string, files []git.TreeFile) error
+	// DetectReverts marks commits not yet checked that revert an earlier
+	// commit, either by naming it in the message or by changing the same
+	// files back to the blobs they had before it.
+	DetectReverts() error
+	// LinkIssues records the issue keys e finds in the messages of commits
+	// not yet linked. If e's patterns differ from those used previously,
+	// all commits are relinked.
+	LinkIssues(e *issues.Extractor) error
+	// ImportIssues inserts or replaces issue metadata.
+	ImportIssues(list []issues.Issue) error
+	// ImportPullRequests inserts or replaces pull requests together with
+	// their reviewers, reviews and commits.
+	ImportPullRequests(prs []hosting.PullRequest) error
+	// GetLastPullRequestUpdate returns the latest update time of the
+	// imported pull requests, or the zero time if there are none, so that
+	// the next import can fetch only what changed since.
+	GetLastPullRequestUpdate() (time.Time, error)
 	Close() error
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"embed"
+	_ "time/tzdata" // embedded zone database for viewer timezones on systems without one
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"